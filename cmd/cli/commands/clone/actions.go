@@ -15,6 +15,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/AlekSi/pointer"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
 
@@ -27,12 +28,16 @@ import (
 
 // list runs a request to list clones of an instance.
 func list(cliCtx *cli.Context) error {
+	if isPaginatedList(cliCtx) {
+		return listPage(cliCtx)
+	}
+
 	dblabClient, err := commands.ClientByCLIContext(cliCtx)
 	if err != nil {
 		return err
 	}
 
-	body, err := dblabClient.ListClonesRaw(cliCtx.Context)
+	body, err := dblabClient.ListClonesRawBySelector(cliCtx.Context, cliCtx.StringSlice(cloneSelectorFlag))
 	if err != nil {
 		return err
 	}
@@ -59,6 +64,61 @@ func list(cliCtx *cli.Context) error {
 	return err
 }
 
+// isPaginatedList checks if the clone list requires sorting, filtering or pagination provided by the clones endpoint.
+func isPaginatedList(cliCtx *cli.Context) bool {
+	for _, flag := range []string{cloneListOffsetFlag, cloneListLimitFlag, cloneListSortFlag, cloneListOrderFlag,
+		cloneListStatusFlag, cloneListSnapshotIDFlag, cloneListPoolFlag} {
+		if cliCtx.IsSet(flag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// listPage runs a request to list a filtered and sorted page of clones.
+func listPage(cliCtx *cli.Context) error {
+	dblabClient, err := commands.ClientByCLIContext(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	listRequest := types.CloneListRequest{
+		Offset:     cliCtx.Int(cloneListOffsetFlag),
+		Limit:      cliCtx.Int(cloneListLimitFlag),
+		SortBy:     cliCtx.String(cloneListSortFlag),
+		Order:      cliCtx.String(cloneListOrderFlag),
+		Status:     cliCtx.String(cloneListStatusFlag),
+		SnapshotID: cliCtx.String(cloneListSnapshotIDFlag),
+		Pool:       cliCtx.String(cloneListPoolFlag),
+		Labels:     cliCtx.StringSlice(cloneSelectorFlag),
+	}
+
+	body, err := dblabClient.ListClonesPageRaw(cliCtx.Context, listRequest)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = body.Close() }()
+
+	viewClonesPage := &models.ClonesPageView{
+		Clones: make([]*models.CloneView, 0),
+	}
+
+	if err := json.NewDecoder(body).Decode(&viewClonesPage); err != nil {
+		return err
+	}
+
+	commandResponse, err := json.MarshalIndent(viewClonesPage, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cliCtx.App.Writer, string(commandResponse))
+
+	return err
+}
+
 // status runs a request to get clone info.
 func status(cliCtx *cli.Context) error {
 	dblabClient, err := commands.ClientByCLIContext(cliCtx)
@@ -111,7 +171,28 @@ func create(cliCtx *cli.Context) error {
 		cloneRequest.Snapshot = &types.SnapshotCloneFieldRequest{ID: cliCtx.String("snapshot-id")}
 	}
 
+	cloneRequest.ConfigProfile = cliCtx.String(cloneConfigProfileFlag)
 	cloneRequest.ExtraConf = splitFlags(cliCtx.StringSlice("extra-config"))
+	cloneRequest.Labels = splitFlags(cliCtx.StringSlice(cloneLabelFlag))
+	cloneRequest.TTL = cliCtx.Uint(cloneTTLFlag)
+	cloneRequest.DeleteAt = cliCtx.Timestamp(cloneDeleteAtFlag)
+
+	if cliCtx.IsSet(cloneRecoveryTimeFlag) || cliCtx.IsSet(cloneRecoveryLSNFlag) {
+		cloneRequest.RecoveryTarget = &types.RecoveryTargetRequest{
+			Time: cliCtx.Timestamp(cloneRecoveryTimeFlag),
+			LSN:  cliCtx.String(cloneRecoveryLSNFlag),
+		}
+	}
+
+	if cliCtx.IsSet(cloneCPUSharesFlag) || cliCtx.IsSet(cloneCPUQuotaFlag) || cliCtx.IsSet(cloneMemoryFlag) ||
+		cliCtx.IsSet(cloneBlkioWeightFlag) {
+		cloneRequest.Resources = &types.CloneResourcesRequest{
+			CPUShares:   cliCtx.Uint64(cloneCPUSharesFlag),
+			CPUQuota:    cliCtx.Uint64(cloneCPUQuotaFlag),
+			Memory:      cliCtx.String(cloneMemoryFlag),
+			BlkioWeight: uint16(cliCtx.Uint(cloneBlkioWeightFlag)),
+		}
+	}
 
 	var clone *models.Clone
 
@@ -147,8 +228,19 @@ func update(cliCtx *cli.Context) error {
 		return err
 	}
 
-	updateRequest := types.CloneUpdateRequest{
-		Protected: cliCtx.Bool("protected"),
+	updateRequest := types.CloneUpdateRequest{}
+
+	if cliCtx.IsSet("protected") {
+		updateRequest.Protected = pointer.ToBool(cliCtx.Bool("protected"))
+	}
+
+	if cliCtx.IsSet(cloneLabelFlag) {
+		updateRequest.Labels = splitFlags(cliCtx.StringSlice(cloneLabelFlag))
+	}
+
+	if cliCtx.IsSet(cloneOwnerFlag) {
+		owner := cliCtx.String(cloneOwnerFlag)
+		updateRequest.Owner = &owner
 	}
 
 	cloneID := cliCtx.Args().First()
@@ -173,6 +265,44 @@ func update(cliCtx *cli.Context) error {
 	return err
 }
 
+// extend runs a request to extend the lease of an existing clone.
+func extend(cliCtx *cli.Context) error {
+	dblabClient, err := commands.ClientByCLIContext(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	extendRequest := types.CloneExtendRequest{
+		TTL:      cliCtx.Uint(cloneTTLFlag),
+		DeleteAt: cliCtx.Timestamp(cloneDeleteAtFlag),
+	}
+
+	if extendRequest.TTL == 0 && extendRequest.DeleteAt == nil {
+		return commands.NewActionError(fmt.Sprintf("either --%s or --%s must be specified", cloneTTLFlag, cloneDeleteAtFlag))
+	}
+
+	cloneID := cliCtx.Args().First()
+
+	clone, err := dblabClient.ExtendClone(cliCtx.Context, cloneID, extendRequest)
+	if err != nil {
+		return err
+	}
+
+	viewClone, err := convertCloneView(clone)
+	if err != nil {
+		return err
+	}
+
+	commandResponse, err := json.MarshalIndent(viewClone, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cliCtx.App.Writer, string(commandResponse))
+
+	return err
+}
+
 func convertCloneView(clone *models.Clone) (*models.CloneView, error) {
 	data, err := json.Marshal(clone)
 	if err != nil {
@@ -187,6 +317,288 @@ func convertCloneView(clone *models.Clone) (*models.CloneView, error) {
 	return viewClone, nil
 }
 
+// snapshot runs a request to create a snapshot of clone.
+func snapshot(cliCtx *cli.Context) error {
+	dblabClient, err := commands.ClientByCLIContext(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	cloneID := cliCtx.Args().First()
+
+	cloneSnapshot, err := dblabClient.SnapshotClone(cliCtx.Context, cloneID)
+	if err != nil {
+		return err
+	}
+
+	snapshotView := &models.SnapshotView{
+		Snapshot:     cloneSnapshot,
+		PhysicalSize: models.Size(cloneSnapshot.PhysicalSize),
+		LogicalSize:  models.Size(cloneSnapshot.LogicalSize),
+	}
+
+	commandResponse, err := json.MarshalIndent(snapshotView, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cliCtx.App.Writer, string(commandResponse))
+
+	return err
+}
+
+// touch runs a request to reset the idle timer of a clone.
+func touch(cliCtx *cli.Context) error {
+	dblabClient, err := commands.ClientByCLIContext(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	clone, err := dblabClient.TouchClone(cliCtx.Context, cliCtx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	viewClone, err := convertCloneView(clone)
+	if err != nil {
+		return err
+	}
+
+	commandResponse, err := json.MarshalIndent(viewClone, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cliCtx.App.Writer, string(commandResponse))
+
+	return err
+}
+
+// restart runs a request to restart the clone container.
+func restart(cliCtx *cli.Context) error {
+	dblabClient, err := commands.ClientByCLIContext(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	cloneID := cliCtx.Args().First()
+
+	var commandResponse []byte
+
+	if cliCtx.Bool("async") {
+		operation, err := dblabClient.RestartCloneAsync(cliCtx.Context, cloneID)
+		if err != nil {
+			return err
+		}
+
+		commandResponse, err = json.MarshalIndent(operation, "", "    ")
+		if err != nil {
+			return err
+		}
+	} else {
+		clone, err := dblabClient.RestartClone(cliCtx.Context, cloneID)
+		if err != nil {
+			return err
+		}
+
+		viewClone, err := convertCloneView(clone)
+		if err != nil {
+			return err
+		}
+
+		commandResponse, err = json.MarshalIndent(viewClone, "", "    ")
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintln(cliCtx.App.Writer, string(commandResponse))
+
+	return err
+}
+
+// logs runs a request to print logs of clone.
+func logs(cliCtx *cli.Context) error {
+	dblabClient, err := commands.ClientByCLIContext(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	cloneLogs, err := dblabClient.CloneLogs(cliCtx.Context, cliCtx.Args().First(), types.CloneLogsRequest{
+		Source: cliCtx.String(cloneLogsSourceFlag),
+		Since:  cliCtx.String(cloneLogsSinceFlag),
+		Tail:   cliCtx.Int(cloneLogsTailFlag),
+		Follow: cliCtx.Bool(cloneLogsFollowFlag),
+	})
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = cloneLogs.Close() }()
+
+	_, err = io.Copy(cliCtx.App.Writer, cloneLogs)
+
+	return err
+}
+
+// export runs a request to export the clone database.
+func export(cliCtx *cli.Context) error {
+	dblabClient, err := commands.ClientByCLIContext(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	cloneID := cliCtx.Args().First()
+
+	exportRequest := types.CloneExportRequest{
+		Format:  cliCtx.String(cloneExportFormatFlag),
+		DBName:  cliCtx.String(cloneExportDBNameFlag),
+		Schemas: cliCtx.StringSlice(cloneExportSchemaFlag),
+		Tables:  cliCtx.StringSlice(cloneExportTableFlag),
+		Target:  cliCtx.String(cloneExportTargetFlag),
+	}
+
+	var commandResponse []byte
+
+	if cliCtx.Bool("async") {
+		operation, err := dblabClient.ExportCloneAsync(cliCtx.Context, cloneID, exportRequest)
+		if err != nil {
+			return err
+		}
+
+		commandResponse, err = json.MarshalIndent(operation, "", "    ")
+		if err != nil {
+			return err
+		}
+	} else {
+		cloneExport, err := dblabClient.ExportClone(cliCtx.Context, cloneID, exportRequest)
+		if err != nil {
+			return err
+		}
+
+		commandResponse, err = json.MarshalIndent(cloneExport, "", "    ")
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintln(cliCtx.App.Writer, string(commandResponse))
+
+	return err
+}
+
+// exports runs a request to list exports of clone.
+func exports(cliCtx *cli.Context) error {
+	dblabClient, err := commands.ClientByCLIContext(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	cloneExports, err := dblabClient.ListCloneExports(cliCtx.Context, cliCtx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	commandResponse, err := json.MarshalIndent(cloneExports, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cliCtx.App.Writer, string(commandResponse))
+
+	return err
+}
+
+// downloadExport runs a request to download the export of clone to a file.
+func downloadExport(cliCtx *cli.Context) error {
+	dblabClient, err := commands.ClientByCLIContext(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	content, err := dblabClient.DownloadCloneExport(cliCtx.Context, cliCtx.Args().Get(0), cliCtx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = content.Close() }()
+
+	outputFile, err := os.Create(cliCtx.String(cloneExportOutputFlag))
+	if err != nil {
+		return errors.Wrap(err, "failed to create the output file")
+	}
+
+	defer func() { _ = outputFile.Close() }()
+
+	if _, err := io.Copy(outputFile, content); err != nil {
+		return errors.Wrap(err, "failed to download the export")
+	}
+
+	return outputFile.Close()
+}
+
+// deleteExport runs a request to delete the export of clone.
+func deleteExport(cliCtx *cli.Context) error {
+	dblabClient, err := commands.ClientByCLIContext(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	exportID := cliCtx.Args().Get(1)
+
+	if err := dblabClient.DeleteCloneExport(cliCtx.Context, cliCtx.Args().Get(0), exportID); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(cliCtx.App.Writer, "The export has been successfully deleted: %s\n", exportID)
+
+	return err
+}
+
+// checkpoint runs a request to create a checkpoint of clone.
+func checkpoint(cliCtx *cli.Context) error {
+	dblabClient, err := commands.ClientByCLIContext(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	cloneCheckpoint, err := dblabClient.CreateCheckpoint(cliCtx.Context, cliCtx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	commandResponse, err := json.MarshalIndent(cloneCheckpoint, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cliCtx.App.Writer, string(commandResponse))
+
+	return err
+}
+
+// checkpoints runs a request to list checkpoints of clone.
+func checkpoints(cliCtx *cli.Context) error {
+	dblabClient, err := commands.ClientByCLIContext(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	cloneCheckpoints, err := dblabClient.ListCheckpoints(cliCtx.Context, cliCtx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	commandResponse, err := json.MarshalIndent(cloneCheckpoints, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cliCtx.App.Writer, string(commandResponse))
+
+	return err
+}
+
 // reset runs a request to reset clone.
 func reset(cliCtx *cli.Context) error {
 	dblabClient, err := commands.ClientByCLIContext(cliCtx)
@@ -196,8 +608,9 @@ func reset(cliCtx *cli.Context) error {
 
 	cloneID := cliCtx.Args().First()
 	resetOptions := types.ResetCloneRequest{
-		Latest:     cliCtx.Bool(cloneResetLatestFlag),
-		SnapshotID: cliCtx.String(cloneResetSnapshotIDFlag),
+		Latest:       cliCtx.Bool(cloneResetLatestFlag),
+		SnapshotID:   cliCtx.String(cloneResetSnapshotIDFlag),
+		CheckpointID: cliCtx.String(cloneResetCheckpointFlag),
 	}
 
 	if cliCtx.Bool("async") {
@@ -465,6 +878,11 @@ func splitFlags(flags []string) map[string]string {
 
 	for _, cfg := range flags {
 		parsed := strings.SplitN(cfg, "=", maxSplitParts)
+		if len(parsed) < maxSplitParts {
+			extraConfig[parsed[0]] = ""
+			continue
+		}
+
 		extraConfig[parsed[0]] = parsed[1]
 	}
 
@@ -5,6 +5,8 @@
 package clone
 
 import (
+	"time"
+
 	"github.com/urfave/cli/v2"
 
 	"gitlab.com/postgres-ai/database-lab/v3/cmd/cli/commands"
@@ -13,6 +15,36 @@ import (
 const (
 	cloneResetLatestFlag     = "latest"
 	cloneResetSnapshotIDFlag = "snapshot-id"
+	cloneResetCheckpointFlag = "checkpoint-id"
+	cloneTTLFlag             = "ttl"
+	cloneDeleteAtFlag        = "delete-at"
+	cloneLabelFlag           = "label"
+	cloneSelectorFlag        = "selector"
+	cloneListOffsetFlag      = "offset"
+	cloneListLimitFlag       = "limit"
+	cloneListSortFlag        = "sort"
+	cloneListOrderFlag       = "order"
+	cloneListStatusFlag      = "status"
+	cloneListSnapshotIDFlag  = "snapshot-id"
+	cloneListPoolFlag        = "pool"
+	cloneRecoveryTimeFlag    = "recovery-target-time"
+	cloneRecoveryLSNFlag     = "recovery-target-lsn"
+	cloneCPUSharesFlag       = "cpu-shares"
+	cloneCPUQuotaFlag        = "cpu-quota"
+	cloneMemoryFlag          = "memory"
+	cloneBlkioWeightFlag     = "blkio-weight"
+	cloneConfigProfileFlag   = "config-profile"
+	cloneLogsSourceFlag      = "source"
+	cloneLogsSinceFlag       = "since"
+	cloneLogsTailFlag        = "tail"
+	cloneLogsFollowFlag      = "follow"
+	cloneExportFormatFlag    = "format"
+	cloneExportDBNameFlag    = "db-name"
+	cloneExportSchemaFlag    = "schema"
+	cloneExportTableFlag     = "table"
+	cloneExportTargetFlag    = "target"
+	cloneExportOutputFlag    = "output"
+	cloneOwnerFlag           = "owner"
 )
 
 // CommandList returns available commands for a clones management.
@@ -25,6 +57,41 @@ func CommandList() []*cli.Command {
 				Name:   "list",
 				Usage:  "list all existing clones",
 				Action: list,
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:    cloneSelectorFlag,
+						Usage:   "list only clones matching the label selector. An example: team=analytics,pr=1234",
+						Aliases: []string{"l"},
+					},
+					&cli.IntFlag{
+						Name:  cloneListOffsetFlag,
+						Usage: "number of clones to skip",
+					},
+					&cli.IntFlag{
+						Name:  cloneListLimitFlag,
+						Usage: "maximum number of clones to list",
+					},
+					&cli.StringFlag{
+						Name:  cloneListSortFlag,
+						Usage: "sort clones by the field: createdAt, diffSize, status",
+					},
+					&cli.StringFlag{
+						Name:  cloneListOrderFlag,
+						Usage: "sorting order: asc, desc",
+					},
+					&cli.StringFlag{
+						Name:  cloneListStatusFlag,
+						Usage: "list only clones with the status code. An example: OK",
+					},
+					&cli.StringFlag{
+						Name:  cloneListSnapshotIDFlag,
+						Usage: "list only clones created from the snapshot",
+					},
+					&cli.StringFlag{
+						Name:  cloneListPoolFlag,
+						Usage: "list only clones located in the pool",
+					},
+				},
 			},
 			{
 				Name:      "status",
@@ -78,6 +145,66 @@ func CommandList() []*cli.Command {
 						Name:  "extra-config",
 						Usage: "set an extra database configuration for the clone. An example: statement_timeout='1s'",
 					},
+					&cli.StringFlag{
+						Name:  cloneConfigProfileFlag,
+						Usage: "use the database configuration profile defined on the instance, extra configuration overrides it (optional)",
+					},
+					&cli.StringSliceFlag{
+						Name:  cloneLabelFlag,
+						Usage: "set a label for the clone. An example: team=analytics",
+					},
+					&cli.UintFlag{
+						Name:  cloneTTLFlag,
+						Usage: "destroy the clone after the specified number of minutes (optional)",
+					},
+					&cli.TimestampFlag{
+						Name:   cloneDeleteAtFlag,
+						Usage:  "destroy the clone at the specified time, e.g. 2021-12-31T23:59:59Z (optional)",
+						Layout: time.RFC3339,
+					},
+					&cli.TimestampFlag{
+						Name:   cloneRecoveryTimeFlag,
+						Usage:  "replay archived WAL up to the specified time, e.g. 2021-12-31T14:32:00Z (optional, physical mode only)",
+						Layout: time.RFC3339,
+					},
+					&cli.StringFlag{
+						Name:  cloneRecoveryLSNFlag,
+						Usage: "replay archived WAL up to the specified LSN, e.g. 16/B374D848 (optional, physical mode only)",
+					},
+					&cli.Uint64Flag{
+						Name:  cloneCPUSharesFlag,
+						Usage: "relative CPU weight of the clone container (optional)",
+					},
+					&cli.Uint64Flag{
+						Name:  cloneCPUQuotaFlag,
+						Usage: "CPU time of the clone container in microseconds per 100ms, e.g. 50000 for half a CPU (optional)",
+					},
+					&cli.StringFlag{
+						Name:  cloneMemoryFlag,
+						Usage: "memory limit of the clone container, e.g. 2g (optional)",
+					},
+					&cli.UintFlag{
+						Name:  cloneBlkioWeightFlag,
+						Usage: "relative block IO weight of the clone container, between 10 and 1000 (optional)",
+					},
+				},
+			},
+			{
+				Name:      "extend",
+				Usage:     "extend the lease of an existing clone",
+				ArgsUsage: "CLONE_ID",
+				Before:    checkCloneIDBefore,
+				Action:    extend,
+				Flags: []cli.Flag{
+					&cli.UintFlag{
+						Name:  cloneTTLFlag,
+						Usage: "destroy the clone after the specified number of minutes from now",
+					},
+					&cli.TimestampFlag{
+						Name:   cloneDeleteAtFlag,
+						Usage:  "destroy the clone at the specified time, e.g. 2021-12-31T23:59:59Z",
+						Layout: time.RFC3339,
+					},
 				},
 			},
 			{
@@ -92,6 +219,14 @@ func CommandList() []*cli.Command {
 						Usage:   "mark instance as protected from deletion",
 						Aliases: []string{"p"},
 					},
+					&cli.StringSliceFlag{
+						Name:  cloneLabelFlag,
+						Usage: "replace clone labels with the specified ones. An example: team=analytics",
+					},
+					&cli.StringFlag{
+						Name:  cloneOwnerFlag,
+						Usage: "transfer the clone to another user (admins only)",
+					},
 				},
 			},
 			{
@@ -114,8 +249,146 @@ func CommandList() []*cli.Command {
 						Name:  cloneResetSnapshotIDFlag,
 						Usage: "snapshot ID used when resetting clone's state",
 					},
+					&cli.StringFlag{
+						Name:  cloneResetCheckpointFlag,
+						Usage: "roll clone's state back to the checkpoint; checkpoints taken after it are destroyed",
+					},
 				},
 			},
+			{
+				Name:      "snapshot",
+				Usage:     "create a snapshot of clone's state, which can be used to create new clones",
+				ArgsUsage: "CLONE_ID",
+				Before:    checkCloneIDBefore,
+				Action:    snapshot,
+			},
+			{
+				Name:      "touch",
+				Usage:     "reset the idle timer of the clone to postpone its idle deletion",
+				ArgsUsage: "CLONE_ID",
+				Before:    checkCloneIDBefore,
+				Action:    touch,
+			},
+			{
+				Name:      "restart",
+				Usage:     "restart the clone container, e.g. after Postgres has crashed",
+				ArgsUsage: "CLONE_ID",
+				Before:    checkCloneIDBefore,
+				Action:    restart,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:    "async",
+						Usage:   "run the command asynchronously",
+						Aliases: []string{"a"},
+					},
+				},
+			},
+			{
+				Name:      "logs",
+				Usage:     "print logs of the clone",
+				ArgsUsage: "CLONE_ID",
+				Before:    checkCloneIDBefore,
+				Action:    logs,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  cloneLogsSourceFlag,
+						Usage: "log source: \"container\" for the container output or \"csv\" for Postgres CSV logs",
+						Value: "container",
+					},
+					&cli.StringFlag{
+						Name:  cloneLogsSinceFlag,
+						Usage: "show logs since the time (e.g. 2021-12-31T14:32:00Z) or relative duration (e.g. 15m)",
+					},
+					&cli.IntFlag{
+						Name:  cloneLogsTailFlag,
+						Usage: "number of the last lines to show (0 - all lines)",
+					},
+					&cli.BoolFlag{
+						Name:    cloneLogsFollowFlag,
+						Usage:   "follow log output",
+						Aliases: []string{"f"},
+					},
+				},
+			},
+			{
+				Name:      "export",
+				Usage:     "export the clone database using pg_dump",
+				ArgsUsage: "CLONE_ID",
+				Before:    checkCloneIDBefore,
+				Action:    export,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  cloneExportFormatFlag,
+						Usage: "pg_dump output format: \"custom\" or \"directory\"",
+						Value: "custom",
+					},
+					&cli.StringFlag{
+						Name:  cloneExportDBNameFlag,
+						Usage: "database to export (optional)",
+					},
+					&cli.StringSliceFlag{
+						Name:  cloneExportSchemaFlag,
+						Usage: "export only schemas matching the pattern (optional)",
+					},
+					&cli.StringSliceFlag{
+						Name:  cloneExportTableFlag,
+						Usage: "export only tables matching the pattern (optional)",
+					},
+					&cli.StringFlag{
+						Name:  cloneExportTargetFlag,
+						Usage: "export storage: \"pool\" or \"s3\"",
+						Value: "pool",
+					},
+					&cli.BoolFlag{
+						Name:    "async",
+						Usage:   "run the command asynchronously",
+						Aliases: []string{"a"},
+					},
+				},
+			},
+			{
+				Name:      "exports",
+				Usage:     "list exports of the clone",
+				ArgsUsage: "CLONE_ID",
+				Before:    checkCloneIDBefore,
+				Action:    exports,
+			},
+			{
+				Name:      "download-export",
+				Usage:     "download the export of the clone, directory exports are downloaded as a tar archive",
+				ArgsUsage: "CLONE_ID EXPORT_ID",
+				Before:    checkExportIDBefore,
+				Action:    downloadExport,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     cloneExportOutputFlag,
+						Usage:    "path of the downloaded file",
+						Aliases:  []string{"o"},
+						Required: true,
+					},
+				},
+			},
+			{
+				Name:      "delete-export",
+				Usage:     "delete the export of the clone",
+				ArgsUsage: "CLONE_ID EXPORT_ID",
+				Before:    checkExportIDBefore,
+				Action:    deleteExport,
+			},
+			{
+				Name:      "checkpoint",
+				Usage:     "create a checkpoint of clone's state, which the clone can be reset to",
+				ArgsUsage: "CLONE_ID",
+				Before:    checkCloneIDBefore,
+				Action:    checkpoint,
+			},
+			{
+				Name:      "checkpoints",
+				Usage:     "list checkpoints of the clone",
+				ArgsUsage: "CLONE_ID",
+				Before:    checkCloneIDBefore,
+				Action:    checkpoints,
+			},
 			{
 				Name:      "destroy",
 				Usage:     "destroy clone",
@@ -244,3 +517,11 @@ func checkCloneIDBefore(c *cli.Context) error {
 
 	return nil
 }
+
+func checkExportIDBefore(c *cli.Context) error {
+	if c.NArg() < 2 {
+		return commands.NewActionError("CLONE_ID and EXPORT_ID arguments are required")
+	}
+
+	return nil
+}
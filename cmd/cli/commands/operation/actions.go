@@ -0,0 +1,59 @@
+/*
+2022 © Postgres.ai
+*/
+
+// Package operation provides commands to track clone operations.
+package operation
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"gitlab.com/postgres-ai/database-lab/v3/cmd/cli/commands"
+)
+
+// list runs a request to list clone operations.
+func list(cliCtx *cli.Context) error {
+	dblabClient, err := commands.ClientByCLIContext(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	operations, err := dblabClient.ListOperations(cliCtx.Context, cliCtx.String("clone-id"))
+	if err != nil {
+		return err
+	}
+
+	commandResponse, err := json.MarshalIndent(operations, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cliCtx.App.Writer, string(commandResponse))
+
+	return err
+}
+
+// status runs a request to get the operation status.
+func status(cliCtx *cli.Context) error {
+	dblabClient, err := commands.ClientByCLIContext(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	operation, err := dblabClient.GetOperation(cliCtx.Context, cliCtx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	commandResponse, err := json.MarshalIndent(operation, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cliCtx.App.Writer, string(commandResponse))
+
+	return err
+}
@@ -0,0 +1,49 @@
+/*
+2022 © Postgres.ai
+*/
+
+package operation
+
+import (
+	"github.com/urfave/cli/v2"
+
+	"gitlab.com/postgres-ai/database-lab/v3/cmd/cli/commands"
+)
+
+// CommandList returns available commands for a clone operation tracking.
+func CommandList() []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:  "operation",
+			Usage: "track clone operations",
+			Subcommands: []*cli.Command{
+				{
+					Name:  "list",
+					Usage: "list recent clone operations",
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "clone-id",
+							Usage: "show operations of the specified clone only",
+						},
+					},
+					Action: list,
+				},
+				{
+					Name:      "status",
+					Usage:     "display the operation status",
+					ArgsUsage: "OPERATION_ID",
+					Before:    checkOperationIDBefore,
+					Action:    status,
+				},
+			},
+		},
+	}
+}
+
+func checkOperationIDBefore(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return commands.NewActionError("OPERATION_ID argument is required")
+	}
+
+	return nil
+}
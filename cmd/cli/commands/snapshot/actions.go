@@ -9,10 +9,13 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/AlekSi/pointer"
 	"github.com/urfave/cli/v2"
 
 	"gitlab.com/postgres-ai/database-lab/v3/cmd/cli/commands"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/util"
 )
 
 // list runs a request to list snapshots of an instance.
@@ -44,3 +47,84 @@ func list(cliCtx *cli.Context) error {
 
 	return err
 }
+
+// create runs a request to take a new snapshot.
+func create(cliCtx *cli.Context) error {
+	dblabClient, err := commands.ClientByCLIContext(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	snapshotJob, err := dblabClient.CreateSnapshot(cliCtx.Context)
+	if err != nil {
+		return err
+	}
+
+	commandResponse, err := json.MarshalIndent(snapshotJob, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cliCtx.App.Writer, string(commandResponse))
+
+	return err
+}
+
+// update runs a request to update an existing snapshot.
+func update(cliCtx *cli.Context) error {
+	dblabClient, err := commands.ClientByCLIContext(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	updateRequest := types.SnapshotUpdateRequest{}
+
+	if cliCtx.IsSet("protected") {
+		updateRequest.Protected = pointer.ToBool(cliCtx.Bool("protected"))
+	}
+
+	if cliCtx.IsSet("description") {
+		updateRequest.Description = pointer.ToString(cliCtx.String("description"))
+	}
+
+	if cliCtx.IsSet("label") {
+		labels, err := util.ParseLabelSelector(cliCtx.StringSlice("label"))
+		if err != nil {
+			return commands.NewActionError(err.Error())
+		}
+
+		updateRequest.Labels = labels
+	}
+
+	snapshot, err := dblabClient.UpdateSnapshot(cliCtx.Context, cliCtx.Args().First(), updateRequest)
+	if err != nil {
+		return err
+	}
+
+	commandResponse, err := json.MarshalIndent(snapshot, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cliCtx.App.Writer, string(commandResponse))
+
+	return err
+}
+
+// destroy runs a request to destroy a snapshot.
+func destroy(cliCtx *cli.Context) error {
+	dblabClient, err := commands.ClientByCLIContext(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	snapshotID := cliCtx.Args().First()
+
+	if err := dblabClient.DestroySnapshot(cliCtx.Context, snapshotID, cliCtx.Bool("force")); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(cliCtx.App.Writer, "The snapshot has been successfully destroyed: %s\n", snapshotID)
+
+	return err
+}
@@ -6,6 +6,8 @@ package snapshot
 
 import (
 	"github.com/urfave/cli/v2"
+
+	"gitlab.com/postgres-ai/database-lab/v3/cmd/cli/commands"
 )
 
 // CommandList returns available commands for a snapshot management.
@@ -20,7 +22,56 @@ func CommandList() []*cli.Command {
 					Usage:  "list all existing snapshots",
 					Action: list,
 				},
+				{
+					Name:   "create",
+					Usage:  "take a new snapshot of the current data state (physical mode only)",
+					Action: create,
+				},
+				{
+					Name:      "update",
+					Usage:     "update existing snapshot",
+					ArgsUsage: "SNAPSHOT_ID",
+					Before:    checkSnapshotIDBefore,
+					Action:    update,
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:    "protected",
+							Usage:   "mark snapshot as protected from deletion and retention cleanup",
+							Aliases: []string{"p"},
+						},
+						&cli.StringFlag{
+							Name:  "description",
+							Usage: "set a description of the snapshot",
+						},
+						&cli.StringSliceFlag{
+							Name:  "label",
+							Usage: "replace snapshot labels with the specified ones. An example: stage=pre-release",
+						},
+					},
+				},
+				{
+					Name:      "destroy",
+					Usage:     "destroy an existing snapshot",
+					ArgsUsage: "SNAPSHOT_ID",
+					Before:    checkSnapshotIDBefore,
+					Action:    destroy,
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:    "force",
+							Usage:   "destroy the snapshot along with clones created from it",
+							Aliases: []string{"f"},
+						},
+					},
+				},
 			},
 		},
 	}
 }
+
+func checkSnapshotIDBefore(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return commands.NewActionError("SNAPSHOT_ID argument is required")
+	}
+
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"gitlab.com/postgres-ai/database-lab/v3/cmd/cli/commands/config"
 	"gitlab.com/postgres-ai/database-lab/v3/cmd/cli/commands/global"
 	"gitlab.com/postgres-ai/database-lab/v3/cmd/cli/commands/instance"
+	"gitlab.com/postgres-ai/database-lab/v3/cmd/cli/commands/operation"
 	"gitlab.com/postgres-ai/database-lab/v3/cmd/cli/commands/snapshot"
 	"gitlab.com/postgres-ai/database-lab/v3/cmd/cli/templates"
 	dblabLog "gitlab.com/postgres-ai/database-lab/v3/pkg/log"
@@ -34,6 +35,7 @@ func main() {
 			clone.CommandList(),
 			instance.CommandList(),
 			snapshot.CommandList(),
+			operation.CommandList(),
 
 			// CLI config.
 			config.CommandList(),
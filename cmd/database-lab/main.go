@@ -20,9 +20,11 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/pkg/errors"
 
+	"gitlab.com/postgres-ai/database-lab/v3/internal/audit"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/cloning"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/embeddedui"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/estimator"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/events"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/observer"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/platform"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/provision"
@@ -33,9 +35,11 @@ import (
 	"gitlab.com/postgres-ai/database-lab/v3/internal/retrieval/engine/postgres/tools/cont"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/srv"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/telemetry"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/webhooks"
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/config"
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/config/global"
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/util/networks"
 	"gitlab.com/postgres-ai/database-lab/v3/version"
 )
@@ -75,7 +79,7 @@ func main() {
 	log.Msg("Database Lab Instance ID:", engProps.InstanceID)
 	log.Msg("Database Lab Engine version:", version.GetVersion())
 
-	if cfg.Server.VerificationToken == "" {
+	if cfg.Server.VerificationToken == "" && len(cfg.Server.Tokens) == 0 && !cfg.Server.JWT.IsEnabled() {
 		log.Warn("Verification Token is empty. Database Lab Engine is insecure")
 	}
 
@@ -112,8 +116,25 @@ func main() {
 		log.Err(err.Error())
 	}
 
+	broker := events.NewBroker()
+
+	webhookSvc := webhooks.New(cfg.Webhooks, engProps.InstanceID, broker)
+	go webhookSvc.Run(ctx)
+
+	auditLog, err := audit.New(cfg.Audit)
+	if err != nil {
+		log.Errf(errors.WithMessage(err, "failed to initialize the audit log").Error())
+		return
+	}
+
+	defer func() {
+		if err := auditLog.Close(); err != nil {
+			log.Err("Failed to close the audit log:", err)
+		}
+	}()
+
 	// Create a new retrieval service to prepare a data directory and start snapshotting.
-	retrievalSvc := retrieval.New(cfg, engProps, docker, pm, tm, runner)
+	retrievalSvc := retrieval.New(cfg, engProps, docker, pm, tm, broker, auditLog, runner)
 
 	// Create a cloning service to provision new clones.
 	provisioner, err := provision.New(ctx, &cfg.Provision, dbCfg, docker, pm, engProps.InstanceID, internalNetworkID)
@@ -132,7 +153,7 @@ func main() {
 		shutdownDatabaseLabEngine(shutdownCtx, docker, engProps, pm.First())
 	}
 
-	cloningSvc := cloning.NewBase(&cfg.Cloning, provisioner, tm, observingChan)
+	cloningSvc := cloning.NewBase(&cfg.Cloning, provisioner, tm, broker, auditLog, observingChan)
 	if err = cloningSvc.Run(ctx); err != nil {
 		log.Err(err)
 		emergencyShutdown()
@@ -153,12 +174,17 @@ func main() {
 	})
 
 	embeddedUI := embeddedui.New(cfg.EmbeddedUI, engProps, runner, docker)
-	server := srv.NewServer(&cfg.Server, &cfg.Global, engProps, docker, cloningSvc, provisioner, retrievalSvc, platformSvc, obs, est, pm, tm)
+	server := srv.NewServer(&cfg.Server, &cfg.Global, engProps, docker, cloningSvc, provisioner, retrievalSvc, platformSvc, obs, est, pm, tm, broker, auditLog)
 	shutdownCh := setShutdownListener()
 
-	go setReloadListener(ctx, provisioner, tm, retrievalSvc, pm, cloningSvc, platformSvc, est, embeddedUI, server)
+	go setReloadListener(ctx, provisioner, tm, retrievalSvc, pm, cloningSvc, platformSvc, est, embeddedUI, webhookSvc, auditLog, server)
+
+	if err = server.InitHandlers(); err != nil {
+		log.Err(err)
+		emergencyShutdown()
 
-	server.InitHandlers()
+		return
+	}
 
 	go func() {
 		if err := server.Run(); err != nil {
@@ -224,7 +250,7 @@ func getEngineProperties(ctx context.Context, dockerCLI *client.Client, cfg *con
 
 func reloadConfig(ctx context.Context, provisionSvc *provision.Provisioner, tm *telemetry.Agent, retrievalSvc *retrieval.Retrieval,
 	pm *pool.Manager, cloningSvc *cloning.Base, platformSvc *platform.Service, est *estimator.Estimator, embeddedUI *embeddedui.UIManager,
-	server *srv.Server) error {
+	webhookSvc *webhooks.Service, auditLog *audit.Logger, server *srv.Server) error {
 	cfg, err := config.LoadConfiguration()
 	if err != nil {
 		return err
@@ -238,6 +264,14 @@ func reloadConfig(ctx context.Context, provisionSvc *provision.Provisioner, tm *
 		return err
 	}
 
+	if err := srv.IsValidConfig(cfg.Server); err != nil {
+		return err
+	}
+
+	if err := cloning.IsValidConfig(cfg.Cloning); err != nil {
+		return err
+	}
+
 	newPlatformSvc, err := platform.New(ctx, cfg.Platform)
 	if err != nil {
 		return err
@@ -251,6 +285,10 @@ func reloadConfig(ctx context.Context, provisionSvc *provision.Provisioner, tm *
 		return err
 	}
 
+	if err := auditLog.Reload(cfg.Audit); err != nil {
+		return err
+	}
+
 	dbCfg := resources.DB{
 		Username: cfg.Global.Database.User(),
 		DBName:   cfg.Global.Database.Name(),
@@ -262,6 +300,7 @@ func reloadConfig(ctx context.Context, provisionSvc *provision.Provisioner, tm *
 	cloningSvc.Reload(cfg.Cloning)
 	platformSvc.Reload(newPlatformSvc)
 	est.Reload(cfg.Estimator)
+	webhookSvc.Reload(cfg.Webhooks)
 	server.Reload(cfg.Server)
 
 	return nil
@@ -269,17 +308,21 @@ func reloadConfig(ctx context.Context, provisionSvc *provision.Provisioner, tm *
 
 func setReloadListener(ctx context.Context, provisionSvc *provision.Provisioner, tm *telemetry.Agent, retrievalSvc *retrieval.Retrieval,
 	pm *pool.Manager, cloningSvc *cloning.Base, platformSvc *platform.Service, est *estimator.Estimator, embeddedUI *embeddedui.UIManager,
-	server *srv.Server) {
+	webhookSvc *webhooks.Service, auditLog *audit.Logger, server *srv.Server) {
 	reloadCh := make(chan os.Signal, 1)
 	signal.Notify(reloadCh, syscall.SIGHUP)
 
 	for range reloadCh {
 		log.Msg("Reloading configuration")
 
-		if err := reloadConfig(ctx, provisionSvc, tm, retrievalSvc, pm, cloningSvc, platformSvc, est, embeddedUI, server); err != nil {
+		err := reloadConfig(ctx, provisionSvc, tm, retrievalSvc, pm, cloningSvc, platformSvc, est, embeddedUI, webhookSvc,
+			auditLog, server)
+		if err != nil {
 			log.Err("Failed to reload configuration", err)
 		}
 
+		auditLog.Record(audit.SystemEntry(models.AuditConfigReload, err))
+
 		log.Msg("Configuration has been reloaded")
 	}
 }
@@ -0,0 +1,460 @@
+/*
+2022 © Postgres.ai
+*/
+
+// Package audit records mutating API calls and engine actions to the audit log.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/util"
+)
+
+const (
+	// SystemUser identifies actions performed by the engine itself.
+	SystemUser = "system"
+
+	defaultFilename   = "audit.log"
+	defaultMaxSizeMB  = 100
+	defaultMaxBackups = 5
+	megabyte          = 1 << 20
+
+	// maxEntrySize limits the size of audit log lines read back.
+	maxEntrySize = 1 << 20
+
+	// readChunkSize defines the size of chunks, in which audit log files are read backwards.
+	readChunkSize = 64 << 10
+)
+
+// Config defines the audit log.
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+	// Path defines the audit log file. Default: "audit.log" in the metadata directory.
+	Path string `yaml:"path"`
+	// MaxSizeMB defines the size of the audit log file, which triggers rotation. Default: 100.
+	MaxSizeMB uint `yaml:"maxSizeMB"`
+	// MaxBackups defines the number of rotated files to keep. Default: 5.
+	MaxBackups uint `yaml:"maxBackups"`
+	// ExposeAPI allows reading the audit log with the API.
+	ExposeAPI bool `yaml:"exposeAPI"`
+}
+
+// Filter defines conditions of audit log entries.
+type Filter struct {
+	User   string
+	Action models.AuditAction
+	Target string
+	Limit  int
+}
+
+// matches checks whether the entry satisfies the filter.
+func (f Filter) matches(entry models.AuditEntry) bool {
+	return (f.User == "" || entry.User == f.User) &&
+		(f.Action == "" || entry.Action == f.Action) &&
+		(f.Target == "" || entry.Target == f.Target)
+}
+
+// Logger writes audit log entries as JSON lines and rotates the audit log file.
+type Logger struct {
+	mu         sync.Mutex
+	cfg        Config
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// New creates a new audit logger.
+func New(cfg Config) (*Logger, error) {
+	l := &Logger{}
+
+	if err := l.Reload(cfg); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Reload reloads the configuration of the audit log and reopens the audit log file.
+func (l *Logger) Reload(cfg Config) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.close(); err != nil {
+		log.Err("Failed to close the audit log:", err)
+	}
+
+	l.cfg = cfg
+	l.path = cfg.Path
+	l.maxSize = int64(cfg.MaxSizeMB) * megabyte
+	l.maxBackups = int(cfg.MaxBackups)
+
+	if l.maxSize == 0 {
+		l.maxSize = defaultMaxSizeMB * megabyte
+	}
+
+	if cfg.MaxBackups == 0 {
+		l.maxBackups = defaultMaxBackups
+	}
+
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if l.path == "" {
+		auditPath, err := util.GetMetaPath(defaultFilename)
+		if err != nil {
+			return fmt.Errorf("failed to get path of the audit log: %w", err)
+		}
+
+		l.path = auditPath
+	}
+
+	return l.open()
+}
+
+// IsAPIExposed checks whether the audit log can be read with the API.
+func (l *Logger) IsAPIExposed() bool {
+	if l == nil {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.cfg.Enabled && l.cfg.ExposeAPI
+}
+
+// Record writes the entry to the audit log. Recording to a nil or disabled logger is a no-op.
+func (l *Logger) Record(entry models.AuditEntry) {
+	if l == nil {
+		return
+	}
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Err("Failed to encode the audit log entry:", err)
+		return
+	}
+
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return
+	}
+
+	if l.size > 0 && l.size+int64(len(data)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			log.Err("Failed to rotate the audit log:", err)
+		}
+	}
+
+	n, err := l.file.Write(data)
+	l.size += int64(n)
+
+	if err != nil {
+		log.Err("Failed to write the audit log entry:", err)
+	}
+}
+
+// Entries returns entries of the audit log matching the filter starting with the most recent one.
+// Rotated files are read as well. Files are read backwards, so only the needed part is read if the limit is set.
+// Entries recorded while reading are not returned.
+func (l *Logger) Entries(filter Filter) ([]models.AuditEntry, error) {
+	files, err := l.openFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeFiles(files)
+
+	entries := []models.AuditEntry{}
+
+	collect := func(line []byte) bool {
+		var entry models.AuditEntry
+
+		if err := json.Unmarshal(line, &entry); err != nil {
+			log.Dbg("Skip malformed audit log entry:", err)
+			return true
+		}
+
+		if filter.matches(entry) {
+			entries = append(entries, entry)
+		}
+
+		return filter.Limit <= 0 || len(entries) < filter.Limit
+	}
+
+	for _, f := range files {
+		completed, err := readLinesBackwards(f.file, f.size, collect)
+		if err != nil {
+			return nil, err
+		}
+
+		if !completed {
+			break
+		}
+	}
+
+	return entries, nil
+}
+
+// RefreshEntry creates an entry of the data refresh reaching the retrieval status.
+// Only statuses of started, finished, and failed refreshes are recorded.
+func RefreshEntry(status models.RetrievalStatus) (models.AuditEntry, bool) {
+	entry := models.AuditEntry{
+		User:   SystemUser,
+		Action: models.AuditRefresh,
+	}
+
+	switch status {
+	case models.Refreshing:
+		entry.Result = models.AuditStarted
+	case models.Finished:
+		entry.Result = models.AuditSuccess
+	case models.Failed:
+		entry.Result = models.AuditFailure
+	default:
+		return entry, false
+	}
+
+	return entry, true
+}
+
+// CloneDeletionEntry creates an entry of the clone deletion initiated by the engine, e.g. because of the idleness.
+func CloneDeletionEntry(cloneID, reason string, err error) models.AuditEntry {
+	entry := SystemEntry(models.AuditCloneDestroy, err)
+	entry.Target = cloneID
+
+	if err == nil {
+		entry.Message = reason
+	}
+
+	return entry
+}
+
+// SystemEntry creates an entry of the action performed by the engine itself.
+func SystemEntry(action models.AuditAction, err error) models.AuditEntry {
+	entry := models.AuditEntry{
+		User:   SystemUser,
+		Action: action,
+		Result: models.AuditSuccess,
+	}
+
+	if err != nil {
+		entry.Result = models.AuditFailure
+		entry.Message = err.Error()
+	}
+
+	return entry
+}
+
+// Close closes the audit log file.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.close()
+}
+
+func (l *Logger) open() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return fmt.Errorf("failed to create the audit log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open the audit log: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to get the audit log size: %w", err)
+	}
+
+	l.file = file
+	l.size = info.Size()
+
+	return nil
+}
+
+func (l *Logger) close() error {
+	if l.file == nil {
+		return nil
+	}
+
+	err := l.file.Close()
+	l.file = nil
+
+	return err
+}
+
+// rotate shifts rotated files, moves the current file to the first backup, and opens a new file.
+// The oldest backup is removed.
+func (l *Logger) rotate() error {
+	if err := l.close(); err != nil {
+		return err
+	}
+
+	for i := l.maxBackups; i > 0; i-- {
+		if err := os.Rename(l.backupPath(i-1), l.backupPath(i)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if l.maxBackups == 0 {
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return l.open()
+}
+
+// backupPath returns the path of the rotated file. Zero index stands for the current file.
+func (l *Logger) backupPath(index int) string {
+	if index == 0 {
+		return l.path
+	}
+
+	return fmt.Sprintf("%s.%d", l.path, index)
+}
+
+// auditFile defines an audit log file opened for reading and its size at the moment of opening.
+type auditFile struct {
+	file *os.File
+	size int64
+}
+
+// openFiles opens the current and rotated files starting with the most recent one.
+// Files are opened under the lock to not race with rotation, but read without it,
+// so recording entries is not blocked by reading the audit log.
+func (l *Logger) openFiles() ([]auditFile, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	files := []auditFile{}
+
+	if l.file == nil {
+		return files, nil
+	}
+
+	for i := 0; i <= l.maxBackups; i++ {
+		file, err := os.Open(l.backupPath(i))
+		if err != nil {
+			if os.IsNotExist(err) {
+				break
+			}
+
+			closeFiles(files)
+
+			return nil, fmt.Errorf("failed to open the audit log: %w", err)
+		}
+
+		info, err := file.Stat()
+		if err != nil {
+			_ = file.Close()
+			closeFiles(files)
+
+			return nil, fmt.Errorf("failed to get the audit log size: %w", err)
+		}
+
+		files = append(files, auditFile{file: file, size: info.Size()})
+	}
+
+	return files, nil
+}
+
+func closeFiles(files []auditFile) {
+	for _, f := range files {
+		_ = f.file.Close()
+	}
+}
+
+// readLinesBackwards passes non-empty lines of the first size bytes of the file to fn starting with the last line.
+// Reading stops when fn returns false. Lines longer than maxEntrySize are skipped.
+// The returned flag reports whether all lines have been passed.
+func readLinesBackwards(file *os.File, size int64, fn func(line []byte) bool) (bool, error) {
+	var (
+		// tail keeps the beginning of the line, which has been read partially.
+		tail     []byte
+		skipLine bool
+		offset   = size
+	)
+
+	emit := func(line []byte) bool {
+		if skipLine {
+			skipLine = false
+			return true
+		}
+
+		if len(line) == 0 {
+			return true
+		}
+
+		if len(line) > maxEntrySize {
+			log.Dbg("Skip audit log entry exceeding the size limit")
+			return true
+		}
+
+		return fn(line)
+	}
+
+	for offset > 0 {
+		chunkSize := int64(readChunkSize)
+		if offset < chunkSize {
+			chunkSize = offset
+		}
+
+		offset -= chunkSize
+
+		chunk := make([]byte, chunkSize, chunkSize+int64(len(tail)))
+		if _, err := file.ReadAt(chunk, offset); err != nil {
+			return false, fmt.Errorf("failed to read the audit log: %w", err)
+		}
+
+		chunk = append(chunk, tail...)
+
+		for {
+			i := bytes.LastIndexByte(chunk, '\n')
+			if i < 0 {
+				break
+			}
+
+			if !emit(chunk[i+1:]) {
+				return false, nil
+			}
+
+			chunk = chunk[:i]
+		}
+
+		tail = chunk
+
+		if len(tail) > maxEntrySize {
+			log.Dbg("Skip audit log entry exceeding the size limit")
+
+			tail = nil
+			skipLine = true
+		}
+	}
+
+	return emit(tail), nil
+}
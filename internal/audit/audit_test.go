@@ -0,0 +1,167 @@
+/*
+2022 © Postgres.ai
+*/
+
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+func TestRecordAndRead(t *testing.T) {
+	auditPath := path.Join(t.TempDir(), "audit", "audit.log")
+
+	logger, err := New(Config{Enabled: true, Path: auditPath, ExposeAPI: true})
+	require.NoError(t, err)
+
+	defer func() { _ = logger.Close() }()
+
+	assert.True(t, logger.IsAPIExposed())
+
+	logger.Record(models.AuditEntry{User: "token:ci", Action: models.AuditCloneCreate, Target: "clone1", Result: models.AuditSuccess})
+	logger.Record(models.AuditEntry{User: "jwt:alice", Action: models.AuditCloneReset, Target: "clone1", Result: models.AuditSuccess})
+	logger.Record(models.AuditEntry{User: "token:ci", Action: models.AuditCloneDestroy, Target: "clone1", Result: models.AuditDenied})
+
+	entries, err := logger.Entries(Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, models.AuditCloneDestroy, entries[0].Action)
+	assert.Equal(t, models.AuditCloneCreate, entries[2].Action)
+	assert.False(t, entries[0].Time.IsZero())
+
+	entries, err = logger.Entries(Filter{User: "token:ci", Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, models.AuditDenied, entries[0].Result)
+
+	entries, err = logger.Entries(Filter{Action: models.AuditCloneReset, Target: "clone1"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "jwt:alice", entries[0].User)
+
+	data, err := os.ReadFile(auditPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"action":"clone_create"`)
+}
+
+func TestRotation(t *testing.T) {
+	auditPath := path.Join(t.TempDir(), "audit.log")
+
+	logger, err := New(Config{Enabled: true, Path: auditPath, MaxBackups: 2})
+	require.NoError(t, err)
+
+	defer func() { _ = logger.Close() }()
+
+	// Rotate the audit log after every entry.
+	logger.maxSize = 1
+
+	for i := 0; i < 5; i++ {
+		logger.Record(models.AuditEntry{Action: models.AuditCloneCreate, Target: fmt.Sprintf("clone%d", i), Result: models.AuditSuccess})
+	}
+
+	for _, rotatedPath := range []string{auditPath, auditPath + ".1", auditPath + ".2"} {
+		_, err := os.Stat(rotatedPath)
+		require.NoError(t, err)
+	}
+
+	_, err = os.Stat(auditPath + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	entries, err := logger.Entries(Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "clone4", entries[0].Target)
+	assert.Equal(t, "clone2", entries[2].Target)
+}
+
+func TestReadLargeLog(t *testing.T) {
+	auditPath := path.Join(t.TempDir(), "audit.log")
+
+	logger, err := New(Config{Enabled: true, Path: auditPath})
+	require.NoError(t, err)
+
+	defer func() { _ = logger.Close() }()
+
+	// Entries span several read chunks.
+	const entryCount = 2000
+
+	for i := 0; i < entryCount; i++ {
+		logger.Record(models.AuditEntry{Action: models.AuditCloneCreate, Target: fmt.Sprintf("clone%d", i), Result: models.AuditSuccess})
+
+		if i == entryCount/2 {
+			// A line exceeding the size limit is skipped.
+			logger.Record(models.AuditEntry{Action: models.AuditCloneCreate, Message: strings.Repeat("a", maxEntrySize)})
+		}
+	}
+
+	entries, err := logger.Entries(Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, entryCount)
+
+	for i, entry := range entries {
+		assert.Equal(t, fmt.Sprintf("clone%d", entryCount-1-i), entry.Target)
+	}
+
+	entries, err = logger.Entries(Filter{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, fmt.Sprintf("clone%d", entryCount-2), entries[1].Target)
+}
+
+func TestDisabledLogger(t *testing.T) {
+	auditPath := path.Join(t.TempDir(), "audit.log")
+
+	logger, err := New(Config{Path: auditPath, ExposeAPI: true})
+	require.NoError(t, err)
+
+	assert.False(t, logger.IsAPIExposed())
+
+	logger.Record(models.AuditEntry{Action: models.AuditCloneCreate, Result: models.AuditSuccess})
+
+	_, err = os.Stat(auditPath)
+	assert.True(t, os.IsNotExist(err))
+
+	var nilLogger *Logger
+
+	nilLogger.Record(models.AuditEntry{Action: models.AuditCloneCreate})
+	assert.False(t, nilLogger.IsAPIExposed())
+}
+
+func TestSystemEntries(t *testing.T) {
+	entry, ok := RefreshEntry(models.Refreshing)
+	require.True(t, ok)
+	assert.Equal(t, models.AuditRefresh, entry.Action)
+	assert.Equal(t, models.AuditStarted, entry.Result)
+	assert.Equal(t, SystemUser, entry.User)
+
+	entry, ok = RefreshEntry(models.Failed)
+	require.True(t, ok)
+	assert.Equal(t, models.AuditFailure, entry.Result)
+
+	_, ok = RefreshEntry(models.Inactive)
+	assert.False(t, ok)
+
+	entry = CloneDeletionEntry("clone1", "clone lease has expired", nil)
+	assert.Equal(t, models.AuditCloneDestroy, entry.Action)
+	assert.Equal(t, "clone1", entry.Target)
+	assert.Equal(t, models.AuditSuccess, entry.Result)
+	assert.Equal(t, "clone lease has expired", entry.Message)
+
+	entry = CloneDeletionEntry("clone1", "clone lease has expired", errors.New("clone is protected"))
+	assert.Equal(t, models.AuditFailure, entry.Result)
+	assert.Equal(t, "clone is protected", entry.Message)
+
+	entry = SystemEntry(models.AuditConfigReload, errors.New("invalid config"))
+	assert.Equal(t, models.AuditFailure, entry.Result)
+	assert.Equal(t, "invalid config", entry.Message)
+}
@@ -20,6 +20,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/xid"
 
+	"gitlab.com/postgres-ai/database-lab/v3/internal/audit"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/events"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/provision"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/telemetry"
@@ -38,8 +40,16 @@ const (
 
 // Config contains a cloning configuration.
 type Config struct {
-	MaxIdleMinutes uint   `yaml:"maxIdleMinutes"`
-	AccessHost     string `yaml:"accessHost"`
+	MaxIdleMinutes     uint                         `yaml:"maxIdleMinutes"`
+	IdleWarningMinutes uint                         `yaml:"idleWarningMinutes"`
+	AccessHost         string                       `yaml:"accessHost"`
+	AccessEndpoints    []AccessEndpoint             `yaml:"accessEndpoints"`
+	Quotas             Quotas                       `yaml:"quotas"`
+	WarmPool           WarmPool                     `yaml:"warmPool"`
+	MaxResources       ResourceLimits               `yaml:"maxResources"`
+	ConfigProfiles     map[string]map[string]string `yaml:"configProfiles"`
+	AllowedSettings    map[string][]string          `yaml:"allowedSettings"`
+	Export             ExportConfig                 `yaml:"export"`
 }
 
 // Base provides cloning service.
@@ -51,29 +61,51 @@ type Base struct {
 	provision   *provision.Provisioner
 	tm          *telemetry.Agent
 	observingCh chan string
+	warmPool    *warmSessions
+	operations  *operationHistory
+	exports     *exportRegistry
+	broker      *events.Broker
+	auditLog    *audit.Logger
 }
 
 // NewBase instances a new Base service.
-func NewBase(cfg *Config, provision *provision.Provisioner, tm *telemetry.Agent, observingCh chan string) *Base {
+func NewBase(cfg *Config, provision *provision.Provisioner, tm *telemetry.Agent, broker *events.Broker,
+	auditLog *audit.Logger, observingCh chan string) *Base {
 	return &Base{
 		config:      cfg,
 		clones:      make(map[string]*CloneWrapper),
 		provision:   provision,
 		tm:          tm,
 		observingCh: observingCh,
+		warmPool:    newWarmSessions(),
+		operations:  newOperationHistory(),
+		exports:     newExportRegistry(),
+		broker:      broker,
+		auditLog:    auditLog,
 		snapshotBox: SnapshotBox{
 			items: make(map[string]*models.Snapshot),
 		},
 	}
 }
 
+// IsValidConfig checks if the cloning configuration is valid.
+func IsValidConfig(cfg Config) error {
+	return cfg.Quotas.validate()
+}
+
 // Reload reloads base cloning configuration.
 func (c *Base) Reload(cfg Config) {
 	*c.config = cfg
+
+	c.refreshConnectionInfo()
 }
 
 // Run initializes and runs cloning component.
 func (c *Base) Run(ctx context.Context) error {
+	if err := IsValidConfig(*c.config); err != nil {
+		return errors.Wrap(err, `error in the "cloning" section of the config`)
+	}
+
 	if err := c.provision.Init(); err != nil {
 		return errors.Wrap(err, "failed to run cloning service")
 	}
@@ -82,10 +114,22 @@ func (c *Base) Run(ctx context.Context) error {
 		log.Err("No available snapshots: ", err)
 	}
 
+	if err := c.restoreOperations(); err != nil {
+		log.Err("Failed to load the operation history:", err)
+	}
+
+	if err := c.restoreExports(); err != nil {
+		log.Err("Failed to load clone exports:", err)
+	}
+
 	if err := c.RestoreClonesState(); err != nil {
 		log.Err("Failed to load stored sessions:", err)
 	}
 
+	c.refreshConnectionInfo()
+
+	c.finishInterruptedExports()
+
 	c.restartCloneContainers(ctx)
 
 	c.filterRunningClones(ctx)
@@ -100,6 +144,12 @@ func (c *Base) Run(ctx context.Context) error {
 
 	go c.runIdleCheck(ctx)
 
+	go c.runWarmPool(ctx)
+
+	go c.runSnapshotCheck(ctx)
+
+	go c.runHealthCheck(ctx)
+
 	return nil
 }
 
@@ -123,12 +173,12 @@ func (c *Base) cleanupInvalidClones() error {
 	return nil
 }
 
-// CreateClone creates a new clone.
-func (c *Base) CreateClone(cloneRequest *types.CloneCreateRequest) (*models.Clone, error) {
+// CreateClone creates a new clone owned by the user and returns the operation tracking its creation.
+func (c *Base) CreateClone(cloneRequest *types.CloneCreateRequest, owner string) (*models.Clone, *models.Operation, error) {
 	cloneRequest.ID = strings.TrimSpace(cloneRequest.ID)
 
 	if _, ok := c.findWrapper(cloneRequest.ID); ok {
-		return nil, models.New(models.ErrCodeBadRequest, "clone with such ID already exists")
+		return nil, nil, models.New(models.ErrCodeBadRequest, "clone with such ID already exists")
 	}
 
 	if cloneRequest.ID == "" {
@@ -139,25 +189,51 @@ func (c *Base) CreateClone(cloneRequest *types.CloneCreateRequest) (*models.Clon
 
 	err := c.fetchSnapshots()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to fetch snapshots")
+		return nil, nil, errors.Wrap(err, "failed to fetch snapshots")
 	}
 
 	snapshot, err := c.getLatestSnapshot()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to find the latest snapshot")
+		return nil, nil, errors.Wrap(err, "failed to find the latest snapshot")
 	}
 
 	if cloneRequest.Snapshot != nil {
 		snapshot, err = c.getSnapshotByID(cloneRequest.Snapshot.ID)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to find the requested snapshot")
+			return nil, nil, errors.Wrap(err, "failed to find the requested snapshot")
 		}
 	}
 
+	recoveryTarget := cloneRequest.RecoveryTarget
+
+	if recoveryTarget != nil && recoveryTarget.Time != nil {
+		if cloneRequest.Snapshot == nil {
+			snapshot, err = c.getSnapshotBefore(*recoveryTarget.Time)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if snapshot.DataStateAt > util.FormatTime(*recoveryTarget.Time) {
+			return nil, nil, models.New(models.ErrCodeBadRequest, "recovery target time precedes the data state of the snapshot")
+		}
+	}
+
+	limits, err := c.cloneResources(cloneRequest.Resources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	extraConf, err := c.cloneExtraConf(cloneRequest.ConfigProfile, cloneRequest.ExtraConf)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	clone := &models.Clone{
 		ID:        cloneRequest.ID,
 		Snapshot:  snapshot,
 		Protected: cloneRequest.Protected,
+		Labels:    cloneRequest.Labels,
 		CreatedAt: util.FormatTime(createdAt),
 		Status: models.Status{
 			Code:    models.StatusCreating,
@@ -167,12 +243,34 @@ func (c *Base) CreateClone(cloneRequest *types.CloneCreateRequest) (*models.Clon
 			Username: cloneRequest.DB.Username,
 			DBName:   cloneRequest.DB.DBName,
 		},
+		RecoveryTarget: recoveryTargetModel(recoveryTarget),
+		ConfigProfile:  cloneRequest.ConfigProfile,
+		Owner:          owner,
 	}
 
 	w := NewCloneWrapper(clone, createdAt)
+	w.setDeleteAt(expirationTime(createdAt, cloneRequest.TTL, cloneRequest.DeleteAt))
+
 	cloneID := clone.ID
 
-	c.setWrapper(clone.ID, w)
+	c.refreshQuotaMetadata()
+
+	c.cloneMutex.Lock()
+
+	if err := c.checkQuotas(quotaUser(clone)); err != nil {
+		c.cloneMutex.Unlock()
+		return nil, nil, err
+	}
+
+	if c.isSnapshotDeleting(clone.Snapshot.ID) {
+		c.cloneMutex.Unlock()
+		return nil, nil, models.New(models.ErrCodeBadRequest, "snapshot is being deleted")
+	}
+
+	c.clones[clone.ID] = w
+	c.cloneMutex.Unlock()
+
+	c.publishCloneStatus(cloneID, clone.Status)
 
 	ephemeralUser := resources.EphemeralUser{
 		Name:        cloneRequest.DB.Username,
@@ -183,12 +281,16 @@ func (c *Base) CreateClone(cloneRequest *types.CloneCreateRequest) (*models.Clon
 
 	c.incrementCloneNumber(clone.Snapshot.ID)
 
+	operation := c.operations.start(models.OperationCreateClone, cloneID, stepStartingSession)
+
 	go func() {
-		session, err := c.provision.StartSession(clone.Snapshot.ID, ephemeralUser, cloneRequest.ExtraConf)
+		session, err := c.startSession(clone.Snapshot.ID, ephemeralUser, extraConf, limits, recoveryTarget)
 		if err != nil {
 			// TODO(anatoly): Empty room case.
 			log.Errf("Failed to start session: %v.", err)
 
+			c.operations.finish(operation.ID, err)
+
 			if updateErr := c.UpdateCloneStatus(cloneID, models.Status{
 				Code:    models.StatusFatal,
 				Message: errors.Cause(err).Error(),
@@ -201,9 +303,47 @@ func (c *Base) CreateClone(cloneRequest *types.CloneCreateRequest) (*models.Clon
 
 		c.fillCloneSession(cloneID, session)
 		c.SaveClonesState()
+
+		c.publishCloneEvent(models.EventCloneCreated, cloneID, "")
+
+		c.operations.finish(operation.ID, nil)
 	}()
 
-	return clone, nil
+	return clone, &operation, nil
+}
+
+// startSession starts a clone session, replaying archived WAL if the recovery target is defined.
+// Clones without extra configuration and with the default resource limits are taken from the warm pool if possible.
+func (c *Base) startSession(snapshotID string, user resources.EphemeralUser, extraConf map[string]string,
+	limits *resources.ResourceLimits, recoveryTarget *types.RecoveryTargetRequest) (*resources.Session, error) {
+	if recoveryTarget == nil {
+		if len(extraConf) == 0 {
+			if session := c.assignWarmSession(snapshotID, user, limits); session != nil {
+				return session, nil
+			}
+		}
+
+		return c.provision.StartSession(snapshotID, user, extraConf, limits)
+	}
+
+	return c.provision.StartRecoverySession(snapshotID, user, extraConf, limits, &resources.RecoveryTarget{
+		Time: recoveryTarget.Time,
+		LSN:  recoveryTarget.LSN,
+	})
+}
+
+func recoveryTargetModel(recoveryTarget *types.RecoveryTargetRequest) *models.RecoveryTarget {
+	if recoveryTarget == nil {
+		return nil
+	}
+
+	target := &models.RecoveryTarget{LSN: recoveryTarget.LSN}
+
+	if recoveryTarget.Time != nil {
+		target.Time = util.FormatTime(*recoveryTarget.Time)
+	}
+
+	return target
 }
 
 func (c *Base) fillCloneSession(cloneID string, session *resources.Session) {
@@ -225,20 +365,15 @@ func (c *Base) fillCloneSession(cloneID string, session *resources.Session) {
 		Message: models.CloneMessageOK,
 	}
 
-	dbName := clone.DB.DBName
-	if dbName == "" {
-		dbName = defaultDatabaseName
-	}
-
-	clone.DB.Port = strconv.FormatUint(uint64(session.Port), 10)
-	clone.DB.Host = c.config.AccessHost
-	clone.DB.ConnStr = fmt.Sprintf("host=%s port=%s user=%s dbname=%s",
-		clone.DB.Host, clone.DB.Port, clone.DB.Username, dbName)
+	c.setConnectionInfo(&clone.DB, strconv.FormatUint(uint64(session.Port), 10))
 
 	clone.Metadata = models.CloneMetadata{
 		CloningTime:    w.TimeStartedAt.Sub(w.TimeCreatedAt).Seconds(),
 		MaxIdleMinutes: c.config.MaxIdleMinutes,
+		Resources:      resourcesModel(session.Resources),
 	}
+
+	c.publishCloneStatus(cloneID, clone.Status)
 }
 
 // ConnectToClone connects to clone by cloneID.
@@ -264,24 +399,34 @@ func connectionString(host, port, username, dbname string) string {
 		host, port, username, dbname)
 }
 
-// DestroyClone destroys clone.
-func (c *Base) DestroyClone(cloneID string) error {
+// DestroyClone destroys clone and returns the operation tracking its destruction.
+func (c *Base) DestroyClone(cloneID string) (*models.Operation, error) {
 	w, ok := c.findWrapper(cloneID)
 	if !ok {
-		return models.New(models.ErrCodeNotFound, "clone not found")
+		return nil, models.New(models.ErrCodeNotFound, "clone not found")
 	}
 
 	if w.Clone.Protected && w.Clone.Status.Code != models.StatusFatal {
-		return models.New(models.ErrCodeBadRequest, "clone is protected")
+		return nil, models.New(models.ErrCodeBadRequest, "clone is protected")
+	}
+
+	if err := c.checkOperationInProgress(cloneID); err != nil {
+		return nil, err
 	}
 
-	if err := c.UpdateCloneStatus(cloneID, models.Status{
+	if c.hasDependentClones(cloneID) {
+		return nil, models.New(models.ErrCodeBadRequest, "clone has dependent clones created from its snapshots")
+	}
+
+	if err := c.setChangingStatus(cloneID, models.Status{
 		Code:    models.StatusDeleting,
 		Message: models.CloneMessageDeleting,
 	}); err != nil {
-		return errors.Wrap(err, "failed to update clone status")
+		return nil, err
 	}
 
+	operation := c.operations.start(models.OperationDestroyClone, cloneID, stepStoppingSession)
+
 	if w.Session == nil {
 		c.deleteClone(cloneID)
 
@@ -289,13 +434,17 @@ func (c *Base) DestroyClone(cloneID string) error {
 			c.decrementCloneNumber(w.Clone.Snapshot.ID)
 		}
 
-		return nil
+		c.operations.finish(operation.ID, nil)
+
+		return &operation, nil
 	}
 
 	go func() {
 		if err := c.provision.StopSession(w.Session); err != nil {
 			log.Errf("Failed to delete a clone: %+v.", err)
 
+			c.operations.finish(operation.ID, err)
+
 			if updateErr := c.UpdateCloneStatus(cloneID, models.Status{
 				Code:    models.StatusFatal,
 				Message: errors.Cause(err).Error(),
@@ -307,6 +456,8 @@ func (c *Base) DestroyClone(cloneID string) error {
 		}
 
 		c.deleteClone(cloneID)
+		c.removeCloneSnapshots(cloneID)
+		c.removeCloneExports(cloneID)
 
 		if w.Clone.Snapshot != nil {
 			c.decrementCloneNumber(w.Clone.Snapshot.ID)
@@ -314,9 +465,11 @@ func (c *Base) DestroyClone(cloneID string) error {
 		c.observingCh <- cloneID
 
 		c.SaveClonesState()
+
+		c.operations.finish(operation.ID, nil)
 	}()
 
-	return nil
+	return &operation, nil
 }
 
 // GetClone returns clone by ID.
@@ -360,7 +513,19 @@ func (c *Base) UpdateClone(id string, patch types.CloneUpdateRequest) (*models.C
 
 	// Set fields.
 	c.cloneMutex.Lock()
-	w.Clone.Protected = patch.Protected
+
+	if patch.Protected != nil {
+		w.Clone.Protected = *patch.Protected
+	}
+
+	if patch.Labels != nil {
+		w.Clone.Labels = patch.Labels
+	}
+
+	if patch.Owner != nil {
+		w.Clone.Owner = *patch.Owner
+	}
+
 	clone = w.Clone
 	c.cloneMutex.Unlock()
 
@@ -369,6 +534,43 @@ func (c *Base) UpdateClone(id string, patch types.CloneUpdateRequest) (*models.C
 	return clone, nil
 }
 
+// ExtendClone extends the lease of the clone.
+func (c *Base) ExtendClone(id string, extendRequest types.CloneExtendRequest) (*models.Clone, error) {
+	w, ok := c.findWrapper(id)
+	if !ok {
+		return nil, models.New(models.ErrCodeNotFound, "clone not found")
+	}
+
+	deleteAt := expirationTime(time.Now(), extendRequest.TTL, extendRequest.DeleteAt)
+	if deleteAt.IsZero() {
+		return nil, models.New(models.ErrCodeBadRequest, "either TTL or expiration time must be specified")
+	}
+
+	var clone *models.Clone
+
+	c.cloneMutex.Lock()
+	w.setDeleteAt(deleteAt)
+	clone = w.Clone
+	c.cloneMutex.Unlock()
+
+	c.SaveClonesState()
+
+	return clone, nil
+}
+
+// expirationTime calculates the clone expiration time. The zero time means that the clone never expires.
+func expirationTime(from time.Time, ttl uint, deleteAt *time.Time) time.Time {
+	if deleteAt != nil {
+		return *deleteAt
+	}
+
+	if ttl > 0 {
+		return from.Add(time.Duration(ttl) * time.Minute)
+	}
+
+	return time.Time{}
+}
+
 // UpdateCloneStatus updates the clone status.
 func (c *Base) UpdateCloneStatus(cloneID string, status models.Status) error {
 	c.cloneMutex.Lock()
@@ -380,19 +582,140 @@ func (c *Base) UpdateCloneStatus(cloneID string, status models.Status) error {
 	}
 
 	w.Clone.Status = status
+	w.IdleWarned = false
+
+	c.publishCloneStatus(cloneID, status)
+
+	return nil
+}
+
+// setBusyStatus sets the status of the operation starting on the ready clone and returns the previous status.
+// If the clone is not ready, the current status is returned along with false.
+// The check and the change are made under the lock, so no other operation can start on the clone in between.
+func (c *Base) setBusyStatus(cloneID string, status models.Status) (models.Status, bool) {
+	c.cloneMutex.Lock()
+	defer c.cloneMutex.Unlock()
+
+	w, ok := c.clones[cloneID]
+	if !ok {
+		return models.Status{}, false
+	}
+
+	originalStatus := w.Clone.Status
+
+	if !w.isReady() {
+		return originalStatus, false
+	}
+
+	w.Clone.Status = status
+
+	c.publishCloneStatus(cloneID, status)
+
+	return originalStatus, true
+}
+
+// restoreBusyStatus returns the clone to its previous status after the operation
+// unless the status has been changed during the operation.
+func (c *Base) restoreBusyStatus(cloneID string, busyCode models.StatusCode, status models.Status) {
+	c.cloneMutex.Lock()
+	defer c.cloneMutex.Unlock()
+
+	w, ok := c.clones[cloneID]
+	if !ok || w.Clone.Status.Code != busyCode {
+		return
+	}
+
+	w.Clone.Status = status
+
+	c.publishCloneStatus(cloneID, status)
+}
+
+// MarkCloneExporting marks the clone as exporting data, e.g. artifacts of the observation session,
+// and returns its previous status to restore. Clones with operations in progress are refused.
+func (c *Base) MarkCloneExporting(cloneID string) (models.Status, error) {
+	c.cloneMutex.Lock()
+	defer c.cloneMutex.Unlock()
+
+	w, ok := c.clones[cloneID]
+	if !ok {
+		return models.Status{}, models.New(models.ErrCodeNotFound, "clone not found")
+	}
+
+	if w.isBusy() {
+		return models.Status{}, models.New(models.ErrCodeBadRequest,
+			fmt.Sprintf("clone has status %s, try again later", w.Clone.Status.Code))
+	}
+
+	originalStatus := w.Clone.Status
+
+	w.Clone.Status = models.Status{
+		Code:    models.StatusExporting,
+		Message: models.CloneMessageExporting,
+	}
+
+	c.publishCloneStatus(cloneID, w.Clone.Status)
+
+	return originalStatus, nil
+}
+
+// RestoreExportedCloneStatus returns the clone marked by MarkCloneExporting to its previous status.
+func (c *Base) RestoreExportedCloneStatus(cloneID string, status models.Status) {
+	c.restoreBusyStatus(cloneID, models.StatusExporting, status)
+}
+
+// checkOperationInProgress refuses changing the clone while it is being exported or snapshotted.
+func (c *Base) checkOperationInProgress(cloneID string) error {
+	c.cloneMutex.RLock()
+	defer c.cloneMutex.RUnlock()
+
+	w, ok := c.clones[cloneID]
+	if !ok {
+		return nil
+	}
+
+	return w.operationInProgressError()
+}
+
+// setChangingStatus sets the status of the clone being reset or destroyed.
+// The clone is checked for operations in progress under the lock, so that they cannot overlap with the change.
+func (c *Base) setChangingStatus(cloneID string, status models.Status) error {
+	c.cloneMutex.Lock()
+	defer c.cloneMutex.Unlock()
+
+	w, ok := c.clones[cloneID]
+	if !ok {
+		return models.New(models.ErrCodeNotFound, "clone not found")
+	}
+
+	if err := w.operationInProgressError(); err != nil {
+		return err
+	}
+
+	w.Clone.Status = status
+	w.IdleWarned = false
+
+	c.publishCloneStatus(cloneID, status)
 
 	return nil
 }
 
-// ResetClone resets clone to chosen snapshot.
-func (c *Base) ResetClone(cloneID string, resetOptions types.ResetCloneRequest) error {
+// ResetClone resets clone to chosen snapshot and returns the operation tracking the reset.
+func (c *Base) ResetClone(cloneID string, resetOptions types.ResetCloneRequest) (*models.Operation, error) {
 	w, ok := c.findWrapper(cloneID)
 	if !ok {
-		return models.New(models.ErrCodeNotFound, "the clone not found")
+		return nil, models.New(models.ErrCodeNotFound, "the clone not found")
 	}
 
 	if w.Session == nil || w.Clone == nil {
-		return models.New(models.ErrCodeNotFound, "clone is not started yet")
+		return nil, models.New(models.ErrCodeNotFound, "clone is not started yet")
+	}
+
+	if err := c.checkOperationInProgress(cloneID); err != nil {
+		return nil, err
+	}
+
+	if resetOptions.CheckpointID != "" {
+		return c.resetToCheckpoint(w, resetOptions.CheckpointID)
 	}
 
 	var snapshotID string
@@ -400,24 +723,43 @@ func (c *Base) ResetClone(cloneID string, resetOptions types.ResetCloneRequest)
 	if resetOptions.SnapshotID != "" {
 		snapshot, err := c.getSnapshotByID(resetOptions.SnapshotID)
 		if err != nil {
-			return errors.Wrap(err, "failed to get snapshot ID")
+			return nil, errors.Wrap(err, "failed to get snapshot ID")
 		}
 
 		snapshotID = snapshot.ID
 	}
 
-	// If the snapshotID variable is empty, the latest snapshot will be chosen.
-	if snapshotID == "" && !resetOptions.Latest {
+	if resetOptions.Latest {
+		if err := c.fetchSnapshots(); err != nil {
+			return nil, errors.Wrap(err, "failed to fetch snapshots")
+		}
+
+		// Snapshots of clones are excluded from the choice of the latest snapshot.
+		snapshot, err := c.getLatestSnapshot()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to find the latest snapshot")
+		}
+
+		snapshotID = snapshot.ID
+	}
+
+	if snapshotID == "" {
 		snapshotID = w.Clone.Snapshot.ID
 	}
 
-	if err := c.UpdateCloneStatus(cloneID, models.Status{
+	if c.hasDependentClones(cloneID) {
+		return nil, models.New(models.ErrCodeBadRequest, "clone has dependent clones created from its snapshots")
+	}
+
+	if err := c.setChangingStatus(cloneID, models.Status{
 		Code:    models.StatusResetting,
 		Message: models.CloneMessageResetting,
 	}); err != nil {
-		return errors.Wrap(err, "failed to update clone status")
+		return nil, err
 	}
 
+	operation := c.operations.start(models.OperationResetClone, cloneID, stepResettingSession)
+
 	go func() {
 		var originalSnapshotID string
 
@@ -429,6 +771,8 @@ func (c *Base) ResetClone(cloneID string, resetOptions types.ResetCloneRequest)
 		if err != nil {
 			log.Errf("Failed to reset clone: %v", err)
 
+			c.operations.finish(operation.ID, err)
+
 			if updateErr := c.UpdateCloneStatus(cloneID, models.Status{
 				Code:    models.StatusFatal,
 				Message: errors.Cause(err).Error(),
@@ -442,6 +786,7 @@ func (c *Base) ResetClone(cloneID string, resetOptions types.ResetCloneRequest)
 		c.cloneMutex.Lock()
 		w.Clone.Snapshot = snapshot
 		c.cloneMutex.Unlock()
+		c.removeCloneSnapshots(cloneID)
 		c.decrementCloneNumber(originalSnapshotID)
 		c.incrementCloneNumber(snapshot.ID)
 
@@ -454,6 +799,9 @@ func (c *Base) ResetClone(cloneID string, resetOptions types.ResetCloneRequest)
 
 		c.SaveClonesState()
 
+		c.operations.finish(operation.ID, nil)
+		c.publishCloneEvent(models.EventCloneReset, cloneID, "")
+
 		c.tm.SendEvent(context.Background(), telemetry.CloneResetEvent, telemetry.CloneCreated{
 			ID:          util.HashID(w.Clone.ID),
 			CloningTime: w.Clone.Metadata.CloningTime,
@@ -461,7 +809,7 @@ func (c *Base) ResetClone(cloneID string, resetOptions types.ResetCloneRequest)
 		})
 	}()
 
-	return nil
+	return &operation, nil
 }
 
 // GetCloningState returns the current state of instance.
@@ -471,6 +819,7 @@ func (c *Base) GetCloningState() models.Cloning {
 		ExpectedCloningTime: c.getExpectedCloningTime(),
 		Clones:              clones,
 		NumClones:           uint64(len(clones)),
+		ConfigProfiles:      c.configProfileNames(),
 	}
 
 	return cloning
@@ -488,6 +837,12 @@ func (c *Base) GetSnapshots() ([]models.Snapshot, error) {
 
 // GetClones returns the list of clones descend ordered by creation time.
 func (c *Base) GetClones() []*models.Clone {
+	return c.getClones(true)
+}
+
+// getClones returns the list of clones descend ordered by creation time.
+// Unless refreshMetadata is set, clones keep the last known metadata, so the storage is not queried for every clone.
+func (c *Base) getClones(refreshMetadata bool) []*models.Clone {
 	clones := make([]*models.Clone, 0, c.lenClones())
 
 	c.cloneMutex.RLock()
@@ -503,7 +858,9 @@ func (c *Base) GetClones() []*models.Clone {
 			}
 		}
 
-		c.refreshCloneMetadata(cloneWrapper)
+		if refreshMetadata {
+			c.refreshCloneMetadata(cloneWrapper)
+		}
 
 		clones = append(clones, cloneWrapper.Clone)
 	}
@@ -537,6 +894,8 @@ func (c *Base) deleteClone(cloneID string) {
 	c.cloneMutex.Lock()
 	delete(c.clones, cloneID)
 	c.cloneMutex.Unlock()
+
+	c.publishCloneEvent(models.EventCloneDestroyed, cloneID, "")
 }
 
 // lenClones returns the number of clones.
@@ -567,10 +926,6 @@ func (c *Base) getExpectedCloningTime() float64 {
 }
 
 func (c *Base) runIdleCheck(ctx context.Context) {
-	if c.config.MaxIdleMinutes == 0 {
-		return
-	}
-
 	idleTimer := time.NewTimer(idleCheckDuration)
 
 	for {
@@ -593,7 +948,24 @@ func (c *Base) destroyIdleClones(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		default:
-			isIdleClone, err := c.isIdleClone(cloneWrapper)
+			if isExpiredClone(cloneWrapper, time.Now()) {
+				log.Msg(fmt.Sprintf("Expired clone %q is going to be removed.", cloneWrapper.Clone.ID))
+
+				_, err := c.DestroyClone(cloneWrapper.Clone.ID)
+				if err != nil {
+					log.Errf("Failed to destroy clone: %+v.", err)
+				}
+
+				c.auditLog.Record(audit.CloneDeletionEntry(cloneWrapper.Clone.ID, "clone lease has expired", err))
+
+				continue
+			}
+
+			if c.config.MaxIdleMinutes == 0 {
+				continue
+			}
+
+			isIdleClone, err := c.isIdleClone(cloneWrapper, time.Duration(c.config.MaxIdleMinutes)*time.Minute)
 			if err != nil {
 				log.Errf("Failed to check the idleness of clone %s: %v.", cloneWrapper.Clone.ID, err)
 				continue
@@ -602,23 +974,49 @@ func (c *Base) destroyIdleClones(ctx context.Context) {
 			if isIdleClone {
 				log.Msg(fmt.Sprintf("Idle clone %q is going to be removed.", cloneWrapper.Clone.ID))
 
-				if err = c.DestroyClone(cloneWrapper.Clone.ID); err != nil {
+				reason := fmt.Sprintf("clone has been idle for more than %d minutes", c.config.MaxIdleMinutes)
+
+				_, err = c.DestroyClone(cloneWrapper.Clone.ID)
+
+				c.auditLog.Record(audit.CloneDeletionEntry(cloneWrapper.Clone.ID, reason, err))
+
+				if err != nil {
 					log.Errf("Failed to destroy clone: %+v.", err)
 					continue
 				}
+
+				c.publishCloneEvent(models.EventCloneIdleDeletion, cloneWrapper.Clone.ID, reason)
+
+				continue
 			}
+
+			c.checkIdleWarning(cloneWrapper)
 		}
 	}
 }
 
-// isIdleClone checks if clone is idle.
-func (c *Base) isIdleClone(wrapper *CloneWrapper) (bool, error) {
+// isExpiredClone checks if the lease of an unprotected clone is over.
+func isExpiredClone(wrapper *CloneWrapper, now time.Time) bool {
+	if wrapper.Clone == nil || wrapper.Clone.Protected || wrapper.TimeDeleteAt.IsZero() {
+		return false
+	}
+
+	switch wrapper.Clone.Status.Code {
+	case models.StatusCreating, models.StatusResetting, models.StatusDeleting, models.StatusExporting:
+		// The clone is busy, it will be checked next time.
+		return false
+	}
+
+	return wrapper.TimeDeleteAt.Before(now)
+}
+
+// isIdleClone checks if clone has been idle for the given duration.
+func (c *Base) isIdleClone(wrapper *CloneWrapper, idleDuration time.Duration) (bool, error) {
 	currentTime := time.Now()
 
-	idleDuration := time.Duration(c.config.MaxIdleMinutes) * time.Minute
 	minimumTime := currentTime.Add(-idleDuration)
 
-	if wrapper.Clone.Protected || wrapper.Clone.Status.Code == models.StatusExporting || wrapper.TimeStartedAt.After(minimumTime) {
+	if wrapper.Clone.Protected || wrapper.Clone.Status.Code == models.StatusExporting || wrapper.idleSince().After(minimumTime) {
 		return false, nil
 	}
 
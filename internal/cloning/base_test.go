@@ -6,11 +6,14 @@ package cloning
 
 import (
 	"testing"
+	"time"
 
+	"github.com/AlekSi/pointer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
 )
 
@@ -28,6 +31,9 @@ func (s *BaseCloningSuite) SetupSuite() {
 	cloning := &Base{
 		clones:      make(map[string]*CloneWrapper),
 		snapshotBox: SnapshotBox{items: make(map[string]*models.Snapshot)},
+		warmPool:    newWarmSessions(),
+		operations:  newOperationHistory(),
+		exports:     newExportRegistry(),
 	}
 
 	s.cloning = cloning
@@ -132,3 +138,111 @@ func (s *BaseCloningSuite) TestLenClones() {
 	lenClones = s.cloning.lenClones()
 	assert.Equal(s.T(), 1, lenClones)
 }
+
+func (s *BaseCloningSuite) TestExtendClone() {
+	s.cloning.setWrapper("testCloneID", &CloneWrapper{Clone: &models.Clone{ID: "testCloneID"}})
+
+	deleteAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	clone, err := s.cloning.ExtendClone("testCloneID", types.CloneExtendRequest{DeleteAt: &deleteAt})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "2030-01-02 03:04:05 UTC", clone.DeleteAt)
+
+	wrapper, ok := s.cloning.findWrapper("testCloneID")
+	require.True(s.T(), ok)
+	assert.Equal(s.T(), deleteAt, wrapper.TimeDeleteAt)
+
+	_, err = s.cloning.ExtendClone("unknownCloneID", types.CloneExtendRequest{TTL: 10})
+	assert.Error(s.T(), err)
+}
+
+func (s *BaseCloningSuite) TestUpdateCloneProtection() {
+	s.cloning.setWrapper("testCloneID", &CloneWrapper{Clone: &models.Clone{ID: "testCloneID", Protected: true}})
+
+	clone, err := s.cloning.UpdateClone("testCloneID", types.CloneUpdateRequest{Labels: map[string]string{"team": "qa"}})
+	require.NoError(s.T(), err)
+	assert.True(s.T(), clone.Protected)
+	assert.Equal(s.T(), map[string]string{"team": "qa"}, clone.Labels)
+
+	clone, err = s.cloning.UpdateClone("testCloneID", types.CloneUpdateRequest{Protected: pointer.ToBool(false)})
+	require.NoError(s.T(), err)
+	assert.False(s.T(), clone.Protected)
+	assert.Equal(s.T(), map[string]string{"team": "qa"}, clone.Labels)
+}
+
+func (s *BaseCloningSuite) TestUpdateCloneOwner() {
+	s.cloning.setWrapper("testCloneID", &CloneWrapper{Clone: &models.Clone{ID: "testCloneID", Owner: "alice", Protected: true}})
+
+	clone, err := s.cloning.UpdateClone("testCloneID", types.CloneUpdateRequest{Labels: map[string]string{"team": "qa"}})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "alice", clone.Owner)
+
+	newOwner := "bob"
+
+	clone, err = s.cloning.UpdateClone("testCloneID", types.CloneUpdateRequest{Owner: &newOwner})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "bob", clone.Owner)
+	assert.True(s.T(), clone.Protected)
+}
+
+func TestExpiredClone(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name     string
+		wrapper  *CloneWrapper
+		expected bool
+	}{
+		{
+			name:     "clone without expiration time",
+			wrapper:  &CloneWrapper{Clone: &models.Clone{Status: models.Status{Code: models.StatusOK}}},
+			expected: false,
+		},
+		{
+			name: "expired clone",
+			wrapper: &CloneWrapper{
+				Clone:        &models.Clone{Status: models.Status{Code: models.StatusOK}},
+				TimeDeleteAt: now.Add(-time.Minute),
+			},
+			expected: true,
+		},
+		{
+			name: "not expired clone",
+			wrapper: &CloneWrapper{
+				Clone:        &models.Clone{Status: models.Status{Code: models.StatusOK}},
+				TimeDeleteAt: now.Add(time.Minute),
+			},
+			expected: false,
+		},
+		{
+			name: "expired protected clone",
+			wrapper: &CloneWrapper{
+				Clone:        &models.Clone{Protected: true, Status: models.Status{Code: models.StatusOK}},
+				TimeDeleteAt: now.Add(-time.Minute),
+			},
+			expected: false,
+		},
+		{
+			name: "expired clone being deleted",
+			wrapper: &CloneWrapper{
+				Clone:        &models.Clone{Status: models.Status{Code: models.StatusDeleting}},
+				TimeDeleteAt: now.Add(-time.Minute),
+			},
+			expected: false,
+		},
+		{
+			name: "expired fatal clone",
+			wrapper: &CloneWrapper{
+				Clone:        &models.Clone{Status: models.Status{Code: models.StatusFatal}},
+				TimeDeleteAt: now.Add(-time.Minute),
+			},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isExpiredClone(tc.wrapper, now))
+		})
+	}
+}
@@ -0,0 +1,152 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"github.com/pkg/errors"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/util"
+)
+
+// CreateCheckpoint takes a checkpoint of the clone data, which the clone can be reset to.
+func (c *Base) CreateCheckpoint(cloneID string) (*models.Checkpoint, error) {
+	w, ok := c.findWrapper(cloneID)
+	if !ok {
+		return nil, models.New(models.ErrCodeNotFound, "clone not found")
+	}
+
+	originalStatus, ok := c.setBusyStatus(cloneID, models.Status{
+		Code:    models.StatusSnapshotting,
+		Message: models.CloneMessageCheckpointing,
+	})
+	if !ok {
+		return nil, models.New(models.ErrCodeBadRequest, "clone is not ready to take a checkpoint")
+	}
+
+	defer c.restoreBusyStatus(cloneID, models.StatusSnapshotting, originalStatus)
+
+	// The session is not replaced while the clone is busy.
+	c.cloneMutex.RLock()
+	session := w.Session
+	c.cloneMutex.RUnlock()
+
+	entry, err := c.provision.CreateCheckpoint(session)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create a checkpoint of the clone")
+	}
+
+	checkpoint := &models.Checkpoint{
+		ID:        entry.ID,
+		CloneID:   cloneID,
+		CreatedAt: util.FormatTime(entry.CreatedAt),
+	}
+
+	log.Dbg("clone checkpoint:", *checkpoint)
+
+	return checkpoint, nil
+}
+
+// ListCheckpoints returns checkpoints of the clone.
+func (c *Base) ListCheckpoints(cloneID string) ([]models.Checkpoint, error) {
+	w, ok := c.findWrapper(cloneID)
+	if !ok {
+		return nil, models.New(models.ErrCodeNotFound, "clone not found")
+	}
+
+	if w.Session == nil {
+		return nil, models.New(models.ErrCodeBadRequest, "clone is not started yet")
+	}
+
+	entries, err := c.provision.ListCheckpoints(w.Session)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list checkpoints of the clone")
+	}
+
+	checkpoints := make([]models.Checkpoint, 0, len(entries))
+
+	for _, entry := range entries {
+		checkpoints = append(checkpoints, models.Checkpoint{
+			ID:           entry.ID,
+			CloneID:      cloneID,
+			CreatedAt:    util.FormatTime(entry.CreatedAt),
+			PhysicalSize: entry.Used,
+		})
+	}
+
+	return checkpoints, nil
+}
+
+// resetToCheckpoint rolls the clone back to the checkpoint. Checkpoints and snapshots taken after it are destroyed.
+func (c *Base) resetToCheckpoint(w *CloneWrapper, checkpointID string) (*models.Operation, error) {
+	cloneID := w.Clone.ID
+
+	checkpoints, err := c.ListCheckpoints(cloneID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !containsCheckpoint(checkpoints, checkpointID) {
+		return nil, models.New(models.ErrCodeNotFound, "checkpoint not found")
+	}
+
+	if c.hasDependentClones(cloneID) {
+		return nil, models.New(models.ErrCodeBadRequest, "clone has dependent clones created from its snapshots")
+	}
+
+	if err := c.setChangingStatus(cloneID, models.Status{
+		Code:    models.StatusResetting,
+		Message: models.CloneMessageResetting,
+	}); err != nil {
+		return nil, err
+	}
+
+	operation := c.operations.start(models.OperationResetClone, cloneID, stepRollingBack)
+
+	go func() {
+		if err := c.provision.RollbackSession(w.Session, checkpointID); err != nil {
+			log.Errf("Failed to reset clone to checkpoint: %v", err)
+
+			c.operations.finish(operation.ID, err)
+
+			if updateErr := c.UpdateCloneStatus(cloneID, models.Status{
+				Code:    models.StatusFatal,
+				Message: errors.Cause(err).Error(),
+			}); updateErr != nil {
+				log.Errf("failed to update clone status: %v", updateErr)
+			}
+
+			return
+		}
+
+		// Snapshots of the clone taken after the checkpoint have been destroyed by the rollback.
+		if err := c.fetchSnapshots(); err != nil {
+			log.Err("failed to fetch snapshots:", err)
+		}
+
+		if err := c.UpdateCloneStatus(cloneID, models.Status{
+			Code:    models.StatusOK,
+			Message: models.CloneMessageOK,
+		}); err != nil {
+			log.Errf("failed to update clone status: %v", err)
+		}
+
+		c.operations.finish(operation.ID, nil)
+		c.publishCloneEvent(models.EventCloneReset, cloneID, "")
+	}()
+
+	return &operation, nil
+}
+
+func containsCheckpoint(checkpoints []models.Checkpoint, checkpointID string) bool {
+	for _, checkpoint := range checkpoints {
+		if checkpoint.ID == checkpointID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,62 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+func (s *BaseCloningSuite) TestCheckpointRestrictions() {
+	_, err := s.cloning.CreateCheckpoint("testCloneID")
+	require.EqualError(s.T(), err, "clone not found")
+
+	s.cloning.setWrapper("testCloneID", &CloneWrapper{Clone: &models.Clone{
+		ID:     "testCloneID",
+		Status: models.Status{Code: models.StatusCreating},
+	}})
+
+	_, err = s.cloning.CreateCheckpoint("testCloneID")
+	require.EqualError(s.T(), err, "clone is not ready to take a checkpoint")
+
+	_, err = s.cloning.ListCheckpoints("testCloneID")
+	require.EqualError(s.T(), err, "clone is not started yet")
+}
+
+func (s *BaseCloningSuite) TestCheckpointBusyClone() {
+	s.cloning.setWrapper("testCloneID", &CloneWrapper{
+		Clone:   &models.Clone{ID: "testCloneID", Status: models.Status{Code: models.StatusOK}},
+		Session: &resources.Session{Pool: "dblab_pool", Port: 6000},
+	})
+
+	originalStatus, ok := s.cloning.setBusyStatus("testCloneID", models.Status{Code: models.StatusSnapshotting})
+	require.True(s.T(), ok)
+
+	_, err := s.cloning.CreateCheckpoint("testCloneID")
+	require.EqualError(s.T(), err, "clone is not ready to take a checkpoint")
+
+	err = s.cloning.setChangingStatus("testCloneID", models.Status{Code: models.StatusResetting})
+	require.EqualError(s.T(), err, "clone snapshot is being taken")
+
+	s.cloning.restoreBusyStatus("testCloneID", models.StatusSnapshotting, originalStatus)
+
+	require.NoError(s.T(), s.cloning.setChangingStatus("testCloneID", models.Status{Code: models.StatusResetting}))
+
+	_, err = s.cloning.CreateCheckpoint("testCloneID")
+	require.EqualError(s.T(), err, "clone is not ready to take a checkpoint")
+}
+
+func TestContainsCheckpoint(t *testing.T) {
+	checkpoints := []models.Checkpoint{{ID: "checkpoint_20200110000000"}, {ID: "checkpoint_20200111000000"}}
+
+	assert.True(t, containsCheckpoint(checkpoints, "checkpoint_20200111000000"))
+	assert.False(t, containsCheckpoint(checkpoints, "checkpoint_20200112000000"))
+	assert.False(t, containsCheckpoint(nil, "checkpoint_20200110000000"))
+}
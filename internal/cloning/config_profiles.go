@@ -0,0 +1,55 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"fmt"
+	"sort"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+// cloneExtraConf builds the Postgres configuration of a clone from the named profile and the requested overrides.
+func (c *Base) cloneExtraConf(profileName string, overrides map[string]string) (map[string]string, error) {
+	if profileName == "" {
+		return overrides, nil
+	}
+
+	profile, ok := c.config.ConfigProfiles[profileName]
+	if !ok {
+		return nil, models.New(models.ErrCodeBadRequest, fmt.Sprintf("config profile %q not found", profileName))
+	}
+
+	extraConf := make(map[string]string, len(profile)+len(overrides))
+
+	for name, value := range profile {
+		extraConf[name] = value
+	}
+
+	for name, value := range overrides {
+		extraConf[name] = value
+	}
+
+	return extraConf, nil
+}
+
+// AllowedSettings returns Postgres settings users are allowed to define for clones along with their allowed values.
+// An empty result means that settings are not restricted.
+func (c *Base) AllowedSettings() map[string][]string {
+	return c.config.AllowedSettings
+}
+
+// configProfileNames returns sorted names of the configured profiles.
+func (c *Base) configProfileNames() []string {
+	names := make([]string, 0, len(c.config.ConfigProfiles))
+
+	for name := range c.config.ConfigProfiles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
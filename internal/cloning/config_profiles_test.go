@@ -0,0 +1,44 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+func TestCloneExtraConf(t *testing.T) {
+	c := &Base{config: &Config{
+		ConfigProfiles: map[string]map[string]string{
+			"oltp-small": {"shared_buffers": "256MB", "work_mem": "4MB"},
+			"analytics":  {"work_mem": "256MB"},
+		},
+	}}
+
+	extraConf, err := c.cloneExtraConf("", map[string]string{"work_mem": "8MB"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"work_mem": "8MB"}, extraConf)
+
+	extraConf, err = c.cloneExtraConf("oltp-small", nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"shared_buffers": "256MB", "work_mem": "4MB"}, extraConf)
+
+	extraConf, err = c.cloneExtraConf("oltp-small", map[string]string{"work_mem": "16MB", "jit": "off"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"shared_buffers": "256MB", "work_mem": "16MB", "jit": "off"}, extraConf)
+
+	_, err = c.cloneExtraConf("unknown", nil)
+
+	var reqErr *models.Error
+
+	require.ErrorAs(t, err, &reqErr)
+	assert.Equal(t, models.ErrCodeBadRequest, reqErr.Code)
+
+	assert.Equal(t, []string{"analytics", "oltp-small"}, c.configProfileNames())
+}
@@ -0,0 +1,90 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+// AccessEndpoint defines a named host clones are reachable at, e.g. from a particular network.
+type AccessEndpoint struct {
+	Name string `yaml:"name"`
+	Host string `yaml:"host"`
+}
+
+// connectionInfo describes how to connect to the clone database through the host.
+type connectionInfo struct {
+	connStr string
+	uri     string
+	jdbcURL string
+}
+
+// setConnectionInfo fills connection parameters of the clone database for the access host and all access endpoints.
+func (c *Base) setConnectionInfo(db *models.Database, port string) {
+	dbName := db.DBName
+	if dbName == "" {
+		dbName = defaultDatabaseName
+	}
+
+	db.Port = port
+	db.Host = c.config.AccessHost
+
+	conn := buildConnectionInfo(db.Host, port, db.Username, dbName)
+	db.ConnStr = conn.connStr
+	db.URI = conn.uri
+	db.JDBCURL = conn.jdbcURL
+
+	db.Endpoints = nil
+
+	for _, endpoint := range c.config.AccessEndpoints {
+		conn := buildConnectionInfo(endpoint.Host, port, db.Username, dbName)
+
+		db.Endpoints = append(db.Endpoints, models.AccessEndpoint{
+			Name:    endpoint.Name,
+			Host:    endpoint.Host,
+			Port:    port,
+			ConnStr: conn.connStr,
+			URI:     conn.uri,
+			JDBCURL: conn.jdbcURL,
+		})
+	}
+}
+
+// refreshConnectionInfo updates connection parameters of running clones according to the current configuration.
+func (c *Base) refreshConnectionInfo() {
+	c.cloneMutex.Lock()
+	defer c.cloneMutex.Unlock()
+
+	for _, w := range c.clones {
+		if w.Clone == nil || w.Session == nil {
+			continue
+		}
+
+		c.setConnectionInfo(&w.Clone.DB, strconv.FormatUint(uint64(w.Session.Port), 10))
+	}
+}
+
+func buildConnectionInfo(host, port, username, dbName string) connectionInfo {
+	hostPort := net.JoinHostPort(host, port)
+	escapedDBName := url.PathEscape(dbName)
+
+	uri := url.URL{
+		Scheme: "postgresql",
+		User:   url.User(username),
+		Host:   hostPort,
+		Path:   "/" + dbName,
+	}
+
+	return connectionInfo{
+		connStr: fmt.Sprintf("host=%s port=%s user=%s dbname=%s", host, port, username, dbName),
+		uri:     uri.String(),
+		jdbcURL: fmt.Sprintf("jdbc:postgresql://%s/%s?user=%s", hostPort, escapedDBName, url.QueryEscape(username)),
+	}
+}
@@ -0,0 +1,65 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+func TestSetConnectionInfo(t *testing.T) {
+	c := &Base{config: &Config{
+		AccessHost: "localhost",
+		AccessEndpoints: []AccessEndpoint{
+			{Name: "vpn", Host: "dblab.vpn.example.com"},
+			{Name: "ci", Host: "fd00::10"},
+		},
+	}}
+
+	db := &models.Database{Username: "john@example", DBName: "test db"}
+
+	c.setConnectionInfo(db, "6000")
+
+	assert.Equal(t, "localhost", db.Host)
+	assert.Equal(t, "6000", db.Port)
+	assert.Equal(t, "host=localhost port=6000 user=john@example dbname=test db", db.ConnStr)
+	assert.Equal(t, "postgresql://john%40example@localhost:6000/test%20db", db.URI)
+	assert.Equal(t, "jdbc:postgresql://localhost:6000/test%20db?user=john%40example", db.JDBCURL)
+
+	assert.Equal(t, []models.AccessEndpoint{
+		{
+			Name:    "vpn",
+			Host:    "dblab.vpn.example.com",
+			Port:    "6000",
+			ConnStr: "host=dblab.vpn.example.com port=6000 user=john@example dbname=test db",
+			URI:     "postgresql://john%40example@dblab.vpn.example.com:6000/test%20db",
+			JDBCURL: "jdbc:postgresql://dblab.vpn.example.com:6000/test%20db?user=john%40example",
+		},
+		{
+			Name:    "ci",
+			Host:    "fd00::10",
+			Port:    "6000",
+			ConnStr: "host=fd00::10 port=6000 user=john@example dbname=test db",
+			URI:     "postgresql://john%40example@[fd00::10]:6000/test%20db",
+			JDBCURL: "jdbc:postgresql://[fd00::10]:6000/test%20db?user=john%40example",
+		},
+	}, db.Endpoints)
+}
+
+func TestSetConnectionInfoDefaultDatabase(t *testing.T) {
+	c := &Base{config: &Config{AccessHost: "localhost"}}
+
+	db := &models.Database{Username: "john"}
+
+	c.setConnectionInfo(db, "6001")
+
+	assert.Equal(t, "host=localhost port=6001 user=john dbname=postgres", db.ConnStr)
+	assert.Equal(t, "postgresql://john@localhost:6001/postgres", db.URI)
+	assert.Equal(t, "jdbc:postgresql://localhost:6001/postgres?user=john", db.JDBCURL)
+	assert.Empty(t, db.Endpoints)
+}
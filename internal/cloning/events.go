@@ -0,0 +1,63 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+// snapshotCheckInterval defines how often the list of snapshots is refreshed to detect snapshots taken by data retrieval.
+const snapshotCheckInterval = time.Minute
+
+func (c *Base) publishCloneStatus(cloneID string, status models.Status) {
+	c.broker.Publish(models.Event{
+		Type:    models.EventCloneStatus,
+		CloneID: cloneID,
+		Status:  string(status.Code),
+		Message: status.Message,
+	})
+}
+
+func (c *Base) publishCloneEvent(eventType models.EventType, cloneID, message string) {
+	c.broker.Publish(models.Event{
+		Type:    eventType,
+		CloneID: cloneID,
+		Message: message,
+	})
+}
+
+func (c *Base) publishSnapshotEvent(eventType models.EventType, snapshot *models.Snapshot) {
+	c.broker.Publish(models.Event{
+		Type:       eventType,
+		SnapshotID: snapshot.ID,
+		CloneID:    snapshot.CloneID,
+	})
+}
+
+// runSnapshotCheck periodically refreshes the list of snapshots, so subscribers are notified about new snapshots
+// and the warm pool switches to the latest snapshot.
+func (c *Base) runSnapshotCheck(ctx context.Context) {
+	ticker := time.NewTicker(snapshotCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.fetchSnapshots(); err != nil {
+				log.Err("Failed to fetch snapshots:", err)
+				continue
+			}
+
+			c.warmPool.requestRefill()
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
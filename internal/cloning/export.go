@@ -0,0 +1,458 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/util"
+)
+
+const (
+	exportsFilename = "exports.json"
+
+	customExportExtension = ".dump"
+)
+
+// ExportConfig defines the storage of clone exports.
+type ExportConfig struct {
+	// S3 defines an S3-compatible storage, which exports with the "s3" target are uploaded to.
+	S3 S3Storage `yaml:"s3"`
+}
+
+// S3Storage defines an S3-compatible storage.
+// Credentials are taken from the environment variables AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+type S3Storage struct {
+	Endpoint       string `yaml:"endpoint"`
+	Region         string `yaml:"region"`
+	Bucket         string `yaml:"bucket"`
+	Prefix         string `yaml:"prefix"`
+	ForcePathStyle bool   `yaml:"forcePathStyle"`
+}
+
+// isConfigured checks whether the storage is defined.
+func (s S3Storage) isConfigured() bool {
+	return s.Bucket != ""
+}
+
+// exportRecord describes a clone export along with the local path of its artifact.
+type exportRecord struct {
+	models.CloneExport
+	Path string `json:"path,omitempty"`
+}
+
+// exportRegistry keeps completed clone exports and persists them to disk.
+type exportRegistry struct {
+	mu      sync.Mutex
+	path    string
+	exports []*exportRecord
+}
+
+func newExportRegistry() *exportRegistry {
+	return &exportRegistry{exports: []*exportRecord{}}
+}
+
+// load restores exports from disk.
+func (r *exportRegistry) load(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.path = path
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to read exports data: %w", err)
+	}
+
+	var exports []*exportRecord
+
+	if err := json.Unmarshal(data, &exports); err != nil {
+		return fmt.Errorf("failed to decode exports data: %w", err)
+	}
+
+	r.exports = append(exports, r.exports...)
+
+	return nil
+}
+
+// add registers a completed export.
+func (r *exportRegistry) add(export *exportRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exports = append(r.exports, export)
+	r.persist()
+}
+
+// get returns the export of the clone by ID.
+func (r *exportRegistry) get(cloneID, exportID string) (exportRecord, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, export := range r.exports {
+		if export.ID == exportID && export.CloneID == cloneID {
+			return *export, true
+		}
+	}
+
+	return exportRecord{}, false
+}
+
+// remove unregisters the export of the clone and returns it.
+func (r *exportRegistry) remove(cloneID, exportID string) (exportRecord, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, export := range r.exports {
+		if export.ID == exportID && export.CloneID == cloneID {
+			r.exports = append(r.exports[:i], r.exports[i+1:]...)
+			r.persist()
+
+			return *export, true
+		}
+	}
+
+	return exportRecord{}, false
+}
+
+// removeClone unregisters all exports of the clone and returns them.
+func (r *exportRegistry) removeClone(cloneID string) []exportRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	removed := []exportRecord{}
+	exports := r.exports[:0]
+
+	for _, export := range r.exports {
+		if export.CloneID == cloneID {
+			removed = append(removed, *export)
+			continue
+		}
+
+		exports = append(exports, export)
+	}
+
+	r.exports = exports
+
+	if len(removed) > 0 {
+		r.persist()
+	}
+
+	return removed
+}
+
+// list returns exports of the clone starting with the most recent one.
+func (r *exportRegistry) list(cloneID string) []models.CloneExport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	exports := make([]models.CloneExport, 0, len(r.exports))
+
+	for i := len(r.exports) - 1; i >= 0; i-- {
+		if r.exports[i].CloneID == cloneID {
+			exports = append(exports, r.exports[i].CloneExport)
+		}
+	}
+
+	return exports
+}
+
+func (r *exportRegistry) persist() {
+	if r.path == "" {
+		return
+	}
+
+	data, err := json.Marshal(r.exports)
+	if err != nil {
+		log.Err("Failed to encode exports data:", err)
+		return
+	}
+
+	if err := os.WriteFile(r.path, data, 0600); err != nil {
+		log.Err("Failed to save exports data:", err)
+	}
+}
+
+// restoreExports loads clone exports from disk.
+func (c *Base) restoreExports() error {
+	exportsPath, err := util.GetMetaPath(exportsFilename)
+	if err != nil {
+		return fmt.Errorf("failed to get path of an exports file: %w", err)
+	}
+
+	return c.exports.load(exportsPath)
+}
+
+// finishInterruptedExports returns clones, which were being exported when the instance stopped, to the ready state.
+func (c *Base) finishInterruptedExports() {
+	c.cloneMutex.Lock()
+	defer c.cloneMutex.Unlock()
+
+	for _, w := range c.clones {
+		if w.Clone != nil && w.Clone.Status.Code == models.StatusExporting {
+			w.Clone.Status = models.Status{
+				Code:    models.StatusOK,
+				Message: models.CloneMessageOK,
+			}
+		}
+	}
+}
+
+// ExportClone dumps the clone database and returns the operation tracking the export.
+// The export gets the ID of the operation and becomes available when the operation is finished.
+func (c *Base) ExportClone(cloneID string, exportRequest types.CloneExportRequest) (*models.Operation, error) {
+	w, ok := c.findWrapper(cloneID)
+	if !ok {
+		return nil, models.New(models.ErrCodeNotFound, "clone not found")
+	}
+
+	c.cloneMutex.RLock()
+	isReady := w.isReady()
+	session := w.Session
+	dbName := w.Clone.DB.DBName
+	c.cloneMutex.RUnlock()
+
+	if !isReady {
+		return nil, models.New(models.ErrCodeBadRequest, "clone cannot be exported in the current status")
+	}
+
+	opts, target, err := c.exportOptions(exportRequest, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	exportDir, err := c.provision.ExportDir(session)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the export directory")
+	}
+
+	originalStatus, ok := c.setBusyStatus(cloneID, models.Status{
+		Code:    models.StatusExporting,
+		Message: models.CloneMessageExporting,
+	})
+	if !ok {
+		return nil, models.New(models.ErrCodeBadRequest, "clone cannot be exported in the current status")
+	}
+
+	operation := c.operations.start(models.OperationExportClone, cloneID, stepExporting)
+
+	go func() {
+		export := &exportRecord{
+			CloneExport: models.CloneExport{
+				ID:      operation.ID,
+				CloneID: cloneID,
+				Format:  string(opts.Format),
+				DBName:  opts.DBName,
+				Schemas: opts.Schemas,
+				Tables:  opts.Tables,
+				Target:  target,
+			},
+			Path: path.Join(exportDir, cloneID, exportArtifactName(operation.ID, opts.Format)),
+		}
+
+		err := c.exportClone(context.Background(), session, opts, export)
+		if err != nil {
+			log.Errf("Failed to export clone %q: %v", cloneID, err)
+		}
+
+		c.restoreBusyStatus(cloneID, models.StatusExporting, originalStatus)
+
+		c.operations.finish(operation.ID, err)
+	}()
+
+	return &operation, nil
+}
+
+// exportOptions validates the export request and fills in defaults.
+func (c *Base) exportOptions(exportRequest types.CloneExportRequest, dbName string) (provision.ExportOptions, models.ExportTarget, error) {
+	opts := provision.ExportOptions{
+		Format:  provision.ExportFormat(exportRequest.Format),
+		DBName:  exportRequest.DBName,
+		Schemas: exportRequest.Schemas,
+		Tables:  exportRequest.Tables,
+	}
+
+	switch opts.Format {
+	case "":
+		opts.Format = provision.CustomExportFormat
+
+	case provision.CustomExportFormat, provision.DirectoryExportFormat:
+
+	default:
+		return opts, "", models.New(models.ErrCodeBadRequest, fmt.Sprintf("unknown export format %q", opts.Format))
+	}
+
+	if opts.DBName == "" {
+		opts.DBName = dbName
+	}
+
+	if opts.DBName == "" {
+		opts.DBName = defaultDatabaseName
+	}
+
+	if strings.Contains(opts.DBName, "=") || strings.Contains(opts.DBName, "://") {
+		return opts, "", models.New(models.ErrCodeBadRequest, "database name must not be a connection string")
+	}
+
+	for _, patterns := range [][]string{opts.Schemas, opts.Tables} {
+		for _, pattern := range patterns {
+			if pattern == "" {
+				return opts, "", models.New(models.ErrCodeBadRequest, "schema and table patterns must not be empty")
+			}
+		}
+	}
+
+	target := models.ExportTarget(exportRequest.Target)
+
+	switch target {
+	case "":
+		target = models.ExportTargetPool
+
+	case models.ExportTargetPool:
+
+	case models.ExportTargetS3:
+		if !c.config.Export.S3.isConfigured() {
+			return opts, "", models.New(models.ErrCodeBadRequest, "S3 storage for exports is not configured")
+		}
+
+	default:
+		return opts, "", models.New(models.ErrCodeBadRequest, fmt.Sprintf("unknown export target %q", target))
+	}
+
+	return opts, target, nil
+}
+
+// exportClone dumps the clone database to the export path and uploads it to the export target.
+func (c *Base) exportClone(ctx context.Context, session *resources.Session, opts provision.ExportOptions, export *exportRecord) error {
+	if err := c.provision.ExportSession(ctx, session, opts, export.Path); err != nil {
+		return err
+	}
+
+	size, err := artifactSize(export.Path)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the export size")
+	}
+
+	export.Size = size
+	export.CreatedAt = time.Now()
+
+	if export.Target == models.ExportTargetS3 {
+		key := path.Join(c.config.Export.S3.Prefix, export.CloneID, path.Base(export.Path))
+
+		location, err := uploadExport(ctx, c.config.Export.S3, export.Path, key)
+
+		if removeErr := os.RemoveAll(export.Path); removeErr != nil {
+			log.Err("Failed to remove the local copy of the export:", removeErr)
+		}
+
+		if err != nil {
+			return errors.Wrap(err, "failed to upload the export")
+		}
+
+		export.Location = location
+		export.Path = ""
+	}
+
+	c.exports.add(export)
+
+	return nil
+}
+
+// GetCloneExports returns exports of the clone starting with the most recent one.
+func (c *Base) GetCloneExports(cloneID string) []models.CloneExport {
+	return c.exports.list(cloneID)
+}
+
+// GetExportArtifact returns the export of the clone and the local path of its artifact.
+func (c *Base) GetExportArtifact(cloneID, exportID string) (*models.CloneExport, string, error) {
+	export, ok := c.exports.get(cloneID, exportID)
+	if !ok {
+		return nil, "", models.New(models.ErrCodeNotFound, "export not found")
+	}
+
+	if export.Path == "" {
+		return nil, "", models.New(models.ErrCodeBadRequest, fmt.Sprintf("export is stored in %s", export.Location))
+	}
+
+	return &export.CloneExport, export.Path, nil
+}
+
+// DeleteExport removes the export of the clone. Artifacts uploaded to S3 are kept in the storage.
+func (c *Base) DeleteExport(cloneID, exportID string) error {
+	export, ok := c.exports.remove(cloneID, exportID)
+	if !ok {
+		return models.New(models.ErrCodeNotFound, "export not found")
+	}
+
+	if export.Path == "" {
+		return nil
+	}
+
+	if err := os.RemoveAll(export.Path); err != nil {
+		return errors.Wrap(err, "failed to remove the export artifact")
+	}
+
+	return nil
+}
+
+// removeCloneExports removes exports of the destroyed clone. Artifacts uploaded to S3 are kept in the storage.
+func (c *Base) removeCloneExports(cloneID string) {
+	for _, export := range c.exports.removeClone(cloneID) {
+		if export.Path == "" {
+			continue
+		}
+
+		if err := os.RemoveAll(export.Path); err != nil {
+			log.Errf("Failed to remove the artifact of export %s: %v", export.ID, err)
+		}
+	}
+}
+
+// exportArtifactName returns the name of the export file or directory.
+func exportArtifactName(exportID string, format provision.ExportFormat) string {
+	if format == provision.CustomExportFormat {
+		return exportID + customExportExtension
+	}
+
+	return exportID
+}
+
+// artifactSize returns the total size of files of the export artifact.
+func artifactSize(artifactPath string) (uint64, error) {
+	var size uint64
+
+	err := filepath.Walk(artifactPath, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			size += uint64(info.Size())
+		}
+
+		return nil
+	})
+
+	return size, err
+}
@@ -0,0 +1,81 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/pkg/errors"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
+)
+
+// uploadExport uploads files of the export artifact to the S3 storage under the key and returns the location of the export.
+// Files of directory exports keep their names under the key.
+func uploadExport(ctx context.Context, storage S3Storage, artifactPath, key string) (string, error) {
+	awsConfig := &aws.Config{
+		Region:           aws.String(storage.Region),
+		S3ForcePathStyle: aws.Bool(storage.ForcePathStyle),
+	}
+
+	if storage.Endpoint != "" {
+		awsConfig.Endpoint = aws.String(storage.Endpoint)
+	}
+
+	awsSession, err := session.NewSession(awsConfig)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to start AWS session")
+	}
+
+	uploader := s3manager.NewUploader(awsSession)
+
+	err = filepath.Walk(artifactPath, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(artifactPath, filePath)
+		if err != nil {
+			return err
+		}
+
+		return uploadFile(ctx, uploader, storage.Bucket, path.Join(key, filepath.ToSlash(relativePath)), filePath)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("s3://%s/%s", storage.Bucket, key), nil
+}
+
+func uploadFile(ctx context.Context, uploader *s3manager.Uploader, bucket, key, filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to open the export file")
+	}
+
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Err("Failed to close the export file:", err)
+		}
+	}()
+
+	if _, err := uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   file,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to upload %s", key)
+	}
+
+	return nil
+}
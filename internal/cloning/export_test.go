@@ -0,0 +1,192 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+func TestExportRegistry(t *testing.T) {
+	exportsPath := path.Join(t.TempDir(), exportsFilename)
+
+	registry := newExportRegistry()
+	require.NoError(t, registry.load(exportsPath))
+
+	registry.add(&exportRecord{CloneExport: models.CloneExport{ID: "export1", CloneID: "clone1"}, Path: "/exports/export1.dump"})
+	registry.add(&exportRecord{CloneExport: models.CloneExport{ID: "export2", CloneID: "clone1"}, Path: "/exports/export2"})
+	registry.add(&exportRecord{CloneExport: models.CloneExport{ID: "export3", CloneID: "clone2"}})
+
+	exports := registry.list("clone1")
+	require.Len(t, exports, 2)
+	assert.Equal(t, "export2", exports[0].ID)
+	assert.Equal(t, "export1", exports[1].ID)
+
+	export, ok := registry.get("clone1", "export1")
+	require.True(t, ok)
+	assert.Equal(t, "/exports/export1.dump", export.Path)
+
+	_, ok = registry.get("clone2", "export1")
+	assert.False(t, ok)
+
+	removed, ok := registry.remove("clone1", "export2")
+	require.True(t, ok)
+	assert.Equal(t, "/exports/export2", removed.Path)
+
+	_, ok = registry.remove("clone1", "export2")
+	assert.False(t, ok)
+
+	restored := newExportRegistry()
+	require.NoError(t, restored.load(exportsPath))
+
+	exports = restored.list("clone1")
+	require.Len(t, exports, 1)
+	assert.Equal(t, "export1", exports[0].ID)
+	assert.Len(t, restored.list("clone2"), 1)
+
+	removedExports := restored.removeClone("clone1")
+	require.Len(t, removedExports, 1)
+	assert.Equal(t, "export1", removedExports[0].ID)
+	assert.Empty(t, restored.list("clone1"))
+	assert.Len(t, restored.list("clone2"), 1)
+}
+
+func (s *BaseCloningSuite) TestExportingStatus() {
+	s.cloning.setWrapper("testCloneID", &CloneWrapper{
+		Clone:   &models.Clone{ID: "testCloneID", Status: models.Status{Code: models.StatusOK, Message: models.CloneMessageOK}},
+		Session: &resources.Session{Pool: "dblab_pool", Port: 6000},
+	})
+
+	originalStatus, ok := s.cloning.setBusyStatus("testCloneID", models.Status{Code: models.StatusExporting})
+	require.True(s.T(), ok)
+	assert.Equal(s.T(), models.StatusOK, originalStatus.Code)
+
+	_, ok = s.cloning.setBusyStatus("testCloneID", models.Status{Code: models.StatusExporting})
+	assert.False(s.T(), ok)
+
+	_, err := s.cloning.DestroyClone("testCloneID")
+	require.EqualError(s.T(), err, "clone is being exported")
+
+	_, err = s.cloning.ResetClone("testCloneID", types.ResetCloneRequest{Latest: true})
+	require.EqualError(s.T(), err, "clone is being exported")
+
+	_, err = s.cloning.SnapshotClone("testCloneID")
+	require.EqualError(s.T(), err, "clone is being exported")
+
+	s.cloning.restoreBusyStatus("testCloneID", models.StatusExporting, originalStatus)
+	assert.Equal(s.T(), models.StatusOK, s.cloning.clones["testCloneID"].Clone.Status.Code)
+
+	// The status changed during the export is kept.
+	_, ok = s.cloning.setBusyStatus("testCloneID", models.Status{Code: models.StatusExporting})
+	require.True(s.T(), ok)
+	require.NoError(s.T(), s.cloning.UpdateCloneStatus("testCloneID", models.Status{Code: models.StatusFatal}))
+
+	s.cloning.restoreBusyStatus("testCloneID", models.StatusExporting, originalStatus)
+	assert.Equal(s.T(), models.StatusFatal, s.cloning.clones["testCloneID"].Clone.Status.Code)
+}
+
+func (s *BaseCloningSuite) TestSnapshottingStatus() {
+	s.cloning.setWrapper("testCloneID", &CloneWrapper{
+		Clone:   &models.Clone{ID: "testCloneID", Status: models.Status{Code: models.StatusWarning}},
+		Session: &resources.Session{Pool: "dblab_pool", Port: 6000},
+	})
+
+	originalStatus, ok := s.cloning.setBusyStatus("testCloneID", models.Status{Code: models.StatusSnapshotting})
+	require.True(s.T(), ok)
+
+	_, err := s.cloning.SnapshotClone("testCloneID")
+	require.EqualError(s.T(), err, "clone is not ready to take a snapshot")
+
+	_, err = s.cloning.DestroyClone("testCloneID")
+	require.EqualError(s.T(), err, "clone snapshot is being taken")
+
+	s.cloning.restoreBusyStatus("testCloneID", models.StatusSnapshotting, originalStatus)
+	assert.Equal(s.T(), models.StatusWarning, s.cloning.clones["testCloneID"].Clone.Status.Code)
+}
+
+func (s *BaseCloningSuite) TestMarkCloneExporting() {
+	s.cloning.setWrapper("testCloneID", &CloneWrapper{
+		Clone:      &models.Clone{ID: "testCloneID", Status: models.Status{Code: models.StatusWarning}},
+		Session:    &resources.Session{Pool: "dblab_pool", Port: 6000},
+		IdleWarned: true,
+	})
+
+	originalStatus, err := s.cloning.MarkCloneExporting("testCloneID")
+	require.NoError(s.T(), err)
+
+	// Operations in progress are not interrupted.
+	_, err = s.cloning.MarkCloneExporting("testCloneID")
+	require.EqualError(s.T(), err, "clone has status EXPORTING, try again later")
+
+	s.cloning.RestoreExportedCloneStatus("testCloneID", originalStatus)
+	assert.Equal(s.T(), models.StatusWarning, s.cloning.clones["testCloneID"].Clone.Status.Code)
+	assert.True(s.T(), s.cloning.clones["testCloneID"].IdleWarned)
+
+	_, err = s.cloning.MarkCloneExporting("unknownCloneID")
+	require.EqualError(s.T(), err, "clone not found")
+}
+
+func TestExportOptions(t *testing.T) {
+	c := &Base{config: &Config{}}
+
+	opts, target, err := c.exportOptions(types.CloneExportRequest{}, "test")
+	require.NoError(t, err)
+	assert.Equal(t, provision.CustomExportFormat, opts.Format)
+	assert.Equal(t, "test", opts.DBName)
+	assert.Equal(t, models.ExportTargetPool, target)
+
+	opts, _, err = c.exportOptions(types.CloneExportRequest{Format: "directory", Tables: []string{"public.users"}}, "")
+	require.NoError(t, err)
+	assert.Equal(t, provision.DirectoryExportFormat, opts.Format)
+	assert.Equal(t, defaultDatabaseName, opts.DBName)
+	assert.Equal(t, []string{"public.users"}, opts.Tables)
+
+	invalidRequests := []types.CloneExportRequest{
+		{Format: "tar"},
+		{Target: "gcs"},
+		{Target: "s3"},
+		{Schemas: []string{""}},
+		{DBName: "host=/var/lib/dblab/dblab_pool/sockets/dblab_clone_6001 port=6001 dbname=postgres"},
+		{DBName: "postgresql://postgres@example.com/postgres"},
+	}
+
+	for _, request := range invalidRequests {
+		_, _, err := c.exportOptions(request, "test")
+		require.Error(t, err)
+
+		var reqErr *models.Error
+		require.ErrorAs(t, err, &reqErr)
+		assert.Equal(t, models.ErrCodeBadRequest, reqErr.Code)
+	}
+
+	c.config.Export.S3.Bucket = "exports"
+
+	_, target, err = c.exportOptions(types.CloneExportRequest{Target: "s3"}, "test")
+	require.NoError(t, err)
+	assert.Equal(t, models.ExportTargetS3, target)
+}
+
+func TestExportArtifact(t *testing.T) {
+	assert.Equal(t, "export1.dump", exportArtifactName("export1", provision.CustomExportFormat))
+	assert.Equal(t, "export1", exportArtifactName("export1", provision.DirectoryExportFormat))
+
+	exportDir := path.Join(t.TempDir(), "export1")
+	require.NoError(t, os.Mkdir(exportDir, 0700))
+	require.NoError(t, os.WriteFile(path.Join(exportDir, "toc.dat"), []byte("toc"), 0600))
+	require.NoError(t, os.WriteFile(path.Join(exportDir, "3000.dat.gz"), []byte("table data"), 0600))
+
+	size, err := artifactSize(exportDir)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(13), size)
+}
@@ -0,0 +1,234 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/util"
+)
+
+const (
+	healthCheckInterval   = time.Minute
+	cloneReadyTimeout     = time.Minute
+	cloneReadyCheckPeriod = time.Second
+
+	// cloneLogLines defines the number of the last container log lines attached to the status of a failed clone.
+	cloneLogLines = 20
+)
+
+// runHealthCheck periodically restarts clone containers that have stopped unexpectedly.
+func (c *Base) runHealthCheck(ctx context.Context) {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.checkCloneContainers(ctx)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkCloneContainers detects stopped containers of ready clones and starts them again.
+func (c *Base) checkCloneContainers(ctx context.Context) {
+	for _, w := range c.readyClones() {
+		cloneID := w.Clone.ID
+
+		if c.provision.IsCloneRunning(ctx, util.GetCloneName(w.Session.Port)) {
+			continue
+		}
+
+		log.Warn(fmt.Sprintf("Container of clone %q is not running. Restarting the container", cloneID))
+
+		// Another operation may have started on the clone since the check.
+		if !c.setStatusIf(cloneID, restartingStatus(), (*CloneWrapper).isReady) {
+			log.Dbg(fmt.Sprintf("Clone %q is not ready anymore. Skip restarting the container", cloneID))
+			continue
+		}
+
+		if err := c.recoverClone(ctx, w); err != nil {
+			log.Errf("Failed to recover clone %q: %v", cloneID, err)
+		}
+	}
+}
+
+// readyClones returns clones which are expected to accept connections.
+func (c *Base) readyClones() []*CloneWrapper {
+	c.cloneMutex.RLock()
+	defer c.cloneMutex.RUnlock()
+
+	clones := make([]*CloneWrapper, 0, len(c.clones))
+
+	for _, w := range c.clones {
+		if w.isReady() {
+			clones = append(clones, w)
+		}
+	}
+
+	return clones
+}
+
+// setStatusIf sets the clone status if the clone satisfies the condition.
+// The check and the change are made under the lock, so the status set by another operation in between is not overwritten.
+func (c *Base) setStatusIf(cloneID string, status models.Status, condition func(w *CloneWrapper) bool) bool {
+	c.cloneMutex.Lock()
+	defer c.cloneMutex.Unlock()
+
+	w, ok := c.clones[cloneID]
+	if !ok || !condition(w) {
+		return false
+	}
+
+	w.Clone.Status = status
+	w.IdleWarned = false
+
+	c.publishCloneStatus(cloneID, status)
+
+	return true
+}
+
+func restartingStatus() models.Status {
+	return models.Status{
+		Code:    models.StatusRestarting,
+		Message: models.CloneMessageRestarting,
+	}
+}
+
+// RestartClone restarts the clone container and returns the operation tracking the restart.
+func (c *Base) RestartClone(cloneID string) (*models.Operation, error) {
+	w, ok := c.findWrapper(cloneID)
+	if !ok {
+		return nil, models.New(models.ErrCodeNotFound, "clone not found")
+	}
+
+	if !c.setStatusIf(cloneID, restartingStatus(), (*CloneWrapper).isRestartable) {
+		return nil, models.New(models.ErrCodeBadRequest, "clone cannot be restarted in the current status")
+	}
+
+	operation := c.operations.start(models.OperationRestartClone, cloneID, stepRestarting)
+
+	go func() {
+		err := c.recoverClone(context.Background(), w)
+		if err != nil {
+			log.Errf("Failed to restart clone %q: %v", cloneID, err)
+		}
+
+		c.operations.finish(operation.ID, err)
+	}()
+
+	return &operation, nil
+}
+
+// recoverClone starts or restarts the clone container and waits for Postgres to accept connections.
+// If the recovery fails, the clone is flagged with the last lines of the container output.
+func (c *Base) recoverClone(ctx context.Context, w *CloneWrapper) error {
+	cloneID := w.Clone.ID
+	cloneName := util.GetCloneName(w.Session.Port)
+
+	var err error
+
+	if c.provision.IsCloneRunning(ctx, cloneName) {
+		err = c.provision.RestartCloneContainer(ctx, cloneName)
+	} else {
+		err = c.provision.StartCloneContainer(ctx, cloneName)
+	}
+
+	if err == nil {
+		err = waitSessionReady(ctx, w.Session)
+	}
+
+	status := models.Status{
+		Code:    models.StatusOK,
+		Message: models.CloneMessageOK,
+	}
+
+	if err != nil {
+		logs, logsErr := c.provision.CloneContainerLogs(ctx, cloneName, cloneLogLines)
+		if logsErr != nil {
+			log.Err("Failed to get clone container logs:", logsErr)
+		}
+
+		status = recoveryFailureStatus(c.provision.IsCloneRunning(ctx, cloneName), err, logs)
+	}
+
+	if updateErr := c.UpdateCloneStatus(cloneID, status); updateErr != nil {
+		log.Errf("Failed to update clone status: %v", updateErr)
+	}
+
+	c.SaveClonesState()
+
+	return err
+}
+
+// recoveryFailureStatus builds the status of a clone that failed to recover.
+// A clone with a running container may still become ready, so it is flagged with a warning.
+func recoveryFailureStatus(isRunning bool, err error, logs string) models.Status {
+	code := models.StatusFatal
+	if isRunning {
+		code = models.StatusWarning
+	}
+
+	message := fmt.Sprintf("Failed to recover clone container: %v.", err)
+
+	if logs = strings.TrimSpace(logs); logs != "" {
+		message += "\nLast container log lines:\n" + logs
+	}
+
+	return models.Status{Code: code, Message: message}
+}
+
+// waitSessionReady waits until Postgres of the session accepts connections.
+func waitSessionReady(ctx context.Context, session *resources.Session) error {
+	timeout := time.NewTimer(cloneReadyTimeout)
+	defer timeout.Stop()
+
+	ticker := time.NewTicker(cloneReadyCheckPeriod)
+	defer ticker.Stop()
+
+	for {
+		err := pingSession(ctx, session)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+
+		case <-timeout.C:
+			return errors.Wrap(err, "postgres is not ready to accept connections")
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func pingSession(ctx context.Context, session *resources.Session) error {
+	db, err := sql.Open(pgDriverName, getSocketConnStr(session))
+	if err != nil {
+		return errors.Wrap(err, "cannot connect to database")
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Err("Cannot close database connection.")
+		}
+	}()
+
+	return db.PingContext(ctx)
+}
@@ -0,0 +1,99 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+func TestRecoveryFailureStatus(t *testing.T) {
+	err := errors.New("postgres is not ready to accept connections")
+
+	status := recoveryFailureStatus(true, err, "LOG:  database system was interrupted\nLOG:  redo starts\n")
+	assert.Equal(t, models.StatusWarning, status.Code)
+	assert.Equal(t, "Failed to recover clone container: postgres is not ready to accept connections.\n"+
+		"Last container log lines:\nLOG:  database system was interrupted\nLOG:  redo starts", status.Message)
+
+	status = recoveryFailureStatus(false, err, "")
+	assert.Equal(t, models.StatusFatal, status.Code)
+	assert.Equal(t, "Failed to recover clone container: postgres is not ready to accept connections.", status.Message)
+}
+
+func TestRestartableClones(t *testing.T) {
+	testCases := []struct {
+		status      models.StatusCode
+		session     *resources.Session
+		ready       bool
+		restartable bool
+	}{
+		{status: models.StatusOK, session: &resources.Session{}, ready: true, restartable: true},
+		{status: models.StatusWarning, session: &resources.Session{}, ready: true, restartable: true},
+		{status: models.StatusFatal, session: &resources.Session{}, ready: false, restartable: true},
+		{status: models.StatusFatal, session: nil, ready: false, restartable: false},
+		{status: models.StatusResetting, session: &resources.Session{}, ready: false, restartable: false},
+		{status: models.StatusRestarting, session: &resources.Session{}, ready: false, restartable: false},
+	}
+
+	for _, tc := range testCases {
+		w := &CloneWrapper{
+			Clone:   &models.Clone{Status: models.Status{Code: tc.status}},
+			Session: tc.session,
+		}
+
+		assert.Equal(t, tc.ready, w.isReady(), tc.status)
+		assert.Equal(t, tc.restartable, w.isRestartable(), tc.status)
+	}
+}
+
+func TestRestartCloneRejected(t *testing.T) {
+	c := &Base{
+		clones: map[string]*CloneWrapper{
+			"creating": {Clone: &models.Clone{ID: "creating", Status: models.Status{Code: models.StatusCreating}}},
+		},
+		operations: newOperationHistory(),
+	}
+
+	_, err := c.RestartClone("unknown")
+
+	var reqErr *models.Error
+
+	assert.ErrorAs(t, err, &reqErr)
+	assert.Equal(t, models.ErrCodeNotFound, reqErr.Code)
+
+	_, err = c.RestartClone("creating")
+
+	assert.ErrorAs(t, err, &reqErr)
+	assert.Equal(t, models.ErrCodeBadRequest, reqErr.Code)
+	assert.Empty(t, c.GetOperations("creating"))
+}
+
+func TestSetStatusIf(t *testing.T) {
+	c := &Base{
+		clones: map[string]*CloneWrapper{
+			"ready": {
+				Clone:   &models.Clone{ID: "ready", Status: models.Status{Code: models.StatusOK}},
+				Session: &resources.Session{},
+			},
+		},
+	}
+
+	assert.True(t, c.setStatusIf("ready", restartingStatus(), (*CloneWrapper).isRestartable))
+	assert.Equal(t, models.StatusRestarting, c.clones["ready"].Clone.Status.Code)
+
+	// The second restart is refused because the clone is already restarting.
+	assert.False(t, c.setStatusIf("ready", restartingStatus(), (*CloneWrapper).isRestartable))
+	assert.False(t, c.setStatusIf("unknown", restartingStatus(), (*CloneWrapper).isReady))
+
+	c.clones["ready"].Clone.Status = models.Status{Code: models.StatusExporting}
+
+	assert.False(t, c.setStatusIf("ready", restartingStatus(), (*CloneWrapper).isReady))
+	assert.Equal(t, models.StatusExporting, c.clones["ready"].Clone.Status.Code)
+}
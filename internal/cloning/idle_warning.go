@@ -0,0 +1,121 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"fmt"
+	"time"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+// idleWarningDuration returns the idle time after which the clone is warned about the upcoming deletion.
+// It returns zero if warnings are disabled.
+func (c *Base) idleWarningDuration() time.Duration {
+	if c.config.IdleWarningMinutes == 0 || c.config.IdleWarningMinutes >= c.config.MaxIdleMinutes {
+		return 0
+	}
+
+	return time.Duration(c.config.MaxIdleMinutes-c.config.IdleWarningMinutes) * time.Minute
+}
+
+// checkIdleWarning marks the clone approaching the idle deletion with the warning status.
+// The warning is removed as soon as the clone is used again. Statuses set by other checks are kept.
+func (c *Base) checkIdleWarning(w *CloneWrapper) {
+	warningDuration := c.idleWarningDuration()
+	if warningDuration == 0 {
+		return
+	}
+
+	c.cloneMutex.RLock()
+	statusCode, isWarned := w.Clone.Status.Code, w.IdleWarned
+	c.cloneMutex.RUnlock()
+
+	if statusCode != models.StatusOK && !isWarned {
+		return
+	}
+
+	isIdle, err := c.isIdleClone(w, warningDuration)
+	if err != nil {
+		log.Errf("Failed to check the idleness of clone %s: %v.", w.Clone.ID, err)
+		return
+	}
+
+	if !isIdle {
+		if isWarned {
+			c.clearIdleWarning(w.Clone.ID)
+		}
+
+		return
+	}
+
+	if isWarned {
+		return
+	}
+
+	message := fmt.Sprintf(models.CloneMessageIdleWarning, c.config.IdleWarningMinutes)
+
+	if c.setIdleWarning(w.Clone.ID, message) {
+		c.publishCloneEvent(models.EventCloneIdleWarning, w.Clone.ID, message)
+	}
+}
+
+// setIdleWarning marks the clone with the idle warning if the clone status is still OK.
+func (c *Base) setIdleWarning(cloneID, message string) bool {
+	c.cloneMutex.Lock()
+	defer c.cloneMutex.Unlock()
+
+	w, ok := c.clones[cloneID]
+	if !ok || w.Clone.Status.Code != models.StatusOK {
+		return false
+	}
+
+	w.Clone.Status = models.Status{Code: models.StatusWarning, Message: message}
+	w.IdleWarned = true
+
+	c.publishCloneStatus(cloneID, w.Clone.Status)
+
+	return true
+}
+
+// clearIdleWarning removes the idle warning of the clone. Warnings set by other checks are kept.
+func (c *Base) clearIdleWarning(cloneID string) {
+	c.cloneMutex.Lock()
+	defer c.cloneMutex.Unlock()
+
+	// The flag is kept while an operation is in progress because the warning status is restored after the operation.
+	w, ok := c.clones[cloneID]
+	if !ok || !w.IdleWarned || w.Clone.Status.Code != models.StatusWarning {
+		return
+	}
+
+	w.IdleWarned = false
+	w.Clone.Status = models.Status{Code: models.StatusOK, Message: models.CloneMessageOK}
+
+	c.publishCloneStatus(cloneID, w.Clone.Status)
+}
+
+// TouchClone resets the idle timer of the clone and removes the idle warning.
+func (c *Base) TouchClone(cloneID string) (*models.Clone, error) {
+	w, ok := c.findWrapper(cloneID)
+	if !ok {
+		return nil, models.New(models.ErrCodeNotFound, "clone not found")
+	}
+
+	if !w.isReady() {
+		return nil, models.New(models.ErrCodeBadRequest, "clone is not ready")
+	}
+
+	c.cloneMutex.Lock()
+	w.TimeTouchedAt = time.Now()
+	c.cloneMutex.Unlock()
+
+	c.clearIdleWarning(cloneID)
+
+	c.SaveClonesState()
+
+	return w.Clone, nil
+}
@@ -0,0 +1,97 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/events"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+func TestIdleWarningDuration(t *testing.T) {
+	testCases := []struct {
+		maxIdleMinutes     uint
+		idleWarningMinutes uint
+		expected           time.Duration
+	}{
+		{maxIdleMinutes: 120, idleWarningMinutes: 0, expected: 0},
+		{maxIdleMinutes: 120, idleWarningMinutes: 30, expected: 90 * time.Minute},
+		{maxIdleMinutes: 120, idleWarningMinutes: 120, expected: 0},
+		{maxIdleMinutes: 0, idleWarningMinutes: 30, expected: 0},
+	}
+
+	for _, tc := range testCases {
+		c := &Base{config: &Config{MaxIdleMinutes: tc.maxIdleMinutes, IdleWarningMinutes: tc.idleWarningMinutes}}
+		assert.Equal(t, tc.expected, c.idleWarningDuration())
+	}
+}
+
+func TestTouchClone(t *testing.T) {
+	broker := events.NewBroker()
+
+	eventCh, cancel := broker.Subscribe()
+	defer cancel()
+
+	startedAt := time.Now().Add(-2 * time.Hour)
+
+	c := &Base{
+		config: &Config{MaxIdleMinutes: 120, IdleWarningMinutes: 30},
+		clones: map[string]*CloneWrapper{
+			"warned": {
+				Clone:         &models.Clone{ID: "warned", Status: models.Status{Code: models.StatusWarning}},
+				Session:       &resources.Session{},
+				TimeStartedAt: startedAt,
+				IdleWarned:    true,
+			},
+			"unhealthy": {
+				Clone: &models.Clone{ID: "unhealthy", Status: models.Status{
+					Code:    models.StatusWarning,
+					Message: "Failed to recover clone container: container is not ready.",
+				}},
+				Session:       &resources.Session{},
+				TimeStartedAt: startedAt,
+			},
+			"creating": {
+				Clone: &models.Clone{ID: "creating", Status: models.Status{Code: models.StatusCreating}},
+			},
+		},
+		broker: broker,
+	}
+
+	_, err := c.TouchClone("unknown")
+	require.EqualError(t, err, "clone not found")
+
+	_, err = c.TouchClone("creating")
+	require.EqualError(t, err, "clone is not ready")
+
+	clone, err := c.TouchClone("warned")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusOK, clone.Status.Code)
+	assert.True(t, c.clones["warned"].idleSince().After(startedAt))
+
+	event := <-eventCh
+	assert.Equal(t, models.EventCloneStatus, event.Type)
+	assert.Equal(t, string(models.StatusOK), event.Status)
+
+	// The touched clone is not idle, so the check keeps it untouched.
+	c.checkIdleWarning(c.clones["warned"])
+	assert.Equal(t, models.StatusOK, c.clones["warned"].Clone.Status.Code)
+	require.Len(t, eventCh, 0)
+
+	// Warnings not caused by the idleness are kept.
+	clone, err = c.TouchClone("unhealthy")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusWarning, clone.Status.Code)
+
+	c.checkIdleWarning(c.clones["unhealthy"])
+	assert.Equal(t, models.StatusWarning, c.clones["unhealthy"].Clone.Status.Code)
+	require.Len(t, eventCh, 0)
+}
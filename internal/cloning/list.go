@@ -0,0 +1,142 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"fmt"
+	"sort"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/util"
+)
+
+const (
+	defaultCloneListLimit = 100
+	maxCloneListLimit     = 1000
+)
+
+// ListClones returns a filtered and sorted page of the clone list.
+// Metadata is refreshed only for clones of the page, so sorting by size uses the last known sizes.
+func (c *Base) ListClones(listRequest types.CloneListRequest) (*models.ClonesPage, error) {
+	if err := normalizeListRequest(&listRequest); err != nil {
+		return nil, err
+	}
+
+	labelSelector, err := util.ParseLabelSelector(listRequest.Labels)
+	if err != nil {
+		return nil, models.New(models.ErrCodeBadRequest, err.Error())
+	}
+
+	clones := filterClones(c.getClones(false), listRequest, labelSelector)
+	sortClones(clones, listRequest.SortBy, listRequest.Order)
+
+	page := &models.ClonesPage{
+		Clones: []*models.Clone{},
+		Total:  len(clones),
+		Offset: listRequest.Offset,
+		Limit:  listRequest.Limit,
+	}
+
+	if listRequest.Offset < len(clones) {
+		end := listRequest.Offset + listRequest.Limit
+		if end > len(clones) {
+			end = len(clones)
+		}
+
+		page.Clones = clones[listRequest.Offset:end]
+	}
+
+	for _, clone := range page.Clones {
+		if w, ok := c.findWrapper(clone.ID); ok {
+			c.refreshCloneMetadata(w)
+		}
+	}
+
+	return page, nil
+}
+
+// normalizeListRequest validates the list request and sets default values.
+func normalizeListRequest(listRequest *types.CloneListRequest) error {
+	if listRequest.Offset < 0 {
+		return models.New(models.ErrCodeBadRequest, "offset must not be negative")
+	}
+
+	if listRequest.Limit < 0 || listRequest.Limit > maxCloneListLimit {
+		return models.New(models.ErrCodeBadRequest, fmt.Sprintf("limit must be between 0 and %d", maxCloneListLimit))
+	}
+
+	if listRequest.Limit == 0 {
+		listRequest.Limit = defaultCloneListLimit
+	}
+
+	switch listRequest.SortBy {
+	case "":
+		listRequest.SortBy = types.SortByCreatedAt
+	case types.SortByCreatedAt, types.SortByDiffSize, types.SortByStatus:
+	default:
+		return models.New(models.ErrCodeBadRequest, fmt.Sprintf("unknown sorting field %q", listRequest.SortBy))
+	}
+
+	switch listRequest.Order {
+	case "":
+		listRequest.Order = types.OrderDesc
+	case types.OrderAsc, types.OrderDesc:
+	default:
+		return models.New(models.ErrCodeBadRequest, fmt.Sprintf("unknown sorting order %q", listRequest.Order))
+	}
+
+	return nil
+}
+
+// filterClones returns clones matching the list request.
+func filterClones(clones []*models.Clone, listRequest types.CloneListRequest, labelSelector map[string]string) []*models.Clone {
+	filteredClones := make([]*models.Clone, 0, len(clones))
+
+	for _, clone := range clones {
+		if listRequest.Status != "" && string(clone.Status.Code) != listRequest.Status {
+			continue
+		}
+
+		if listRequest.SnapshotID != "" && (clone.Snapshot == nil || clone.Snapshot.ID != listRequest.SnapshotID) {
+			continue
+		}
+
+		if listRequest.Pool != "" && (clone.Snapshot == nil || clone.Snapshot.Pool != listRequest.Pool) {
+			continue
+		}
+
+		if !util.MatchLabels(clone.Labels, labelSelector) {
+			continue
+		}
+
+		filteredClones = append(filteredClones, clone)
+	}
+
+	return filteredClones
+}
+
+// sortClones sorts clones by the field. Clones with equal values keep the creation order.
+func sortClones(clones []*models.Clone, sortBy, order string) {
+	less := func(i, j int) bool {
+		switch sortBy {
+		case types.SortByDiffSize:
+			return clones[i].Metadata.CloneDiffSize < clones[j].Metadata.CloneDiffSize
+
+		case types.SortByStatus:
+			return clones[i].Status.Code < clones[j].Status.Code
+
+		default:
+			return clones[i].CreatedAt < clones[j].CreatedAt
+		}
+	}
+
+	if order == types.OrderDesc {
+		sort.SliceStable(clones, func(i, j int) bool { return less(j, i) })
+		return
+	}
+
+	sort.SliceStable(clones, less)
+}
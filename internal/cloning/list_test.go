@@ -0,0 +1,151 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+func testCloneList() *Base {
+	snapshotA := &models.Snapshot{ID: "poolA@snapshot_1", Pool: "poolA"}
+	snapshotB := &models.Snapshot{ID: "poolB@snapshot_2", Pool: "poolB"}
+
+	return &Base{
+		clones: map[string]*CloneWrapper{
+			"clone1": {Clone: &models.Clone{
+				ID: "clone1", CreatedAt: "2022-01-01 00:00:00 UTC", Snapshot: snapshotA,
+				Status:   models.Status{Code: models.StatusOK},
+				Metadata: models.CloneMetadata{CloneDiffSize: 300},
+				Labels:   map[string]string{"team": "analytics"},
+			}},
+			"clone2": {Clone: &models.Clone{
+				ID: "clone2", CreatedAt: "2022-01-02 00:00:00 UTC", Snapshot: snapshotB,
+				Status:   models.Status{Code: models.StatusFatal},
+				Metadata: models.CloneMetadata{CloneDiffSize: 100},
+			}},
+			"clone3": {Clone: &models.Clone{
+				ID: "clone3", CreatedAt: "2022-01-03 00:00:00 UTC", Snapshot: snapshotA,
+				Status:   models.Status{Code: models.StatusOK},
+				Metadata: models.CloneMetadata{CloneDiffSize: 200},
+				Labels:   map[string]string{"team": "backend"},
+			}},
+		},
+		snapshotBox: SnapshotBox{items: map[string]*models.Snapshot{snapshotA.ID: snapshotA, snapshotB.ID: snapshotB}},
+	}
+}
+
+func cloneIDs(clones []*models.Clone) []string {
+	ids := make([]string, 0, len(clones))
+
+	for _, clone := range clones {
+		ids = append(ids, clone.ID)
+	}
+
+	return ids
+}
+
+func TestListClones(t *testing.T) {
+	testCases := []struct {
+		name        string
+		listRequest types.CloneListRequest
+		expected    []string
+		total       int
+	}{
+		{
+			name:        "default",
+			listRequest: types.CloneListRequest{},
+			expected:    []string{"clone3", "clone2", "clone1"},
+			total:       3,
+		},
+		{
+			name:        "sort by creation time ascending",
+			listRequest: types.CloneListRequest{Order: types.OrderAsc},
+			expected:    []string{"clone1", "clone2", "clone3"},
+			total:       3,
+		},
+		{
+			name:        "sort by diff size",
+			listRequest: types.CloneListRequest{SortBy: types.SortByDiffSize},
+			expected:    []string{"clone1", "clone3", "clone2"},
+			total:       3,
+		},
+		{
+			name:        "sort by status",
+			listRequest: types.CloneListRequest{SortBy: types.SortByStatus, Order: types.OrderAsc},
+			expected:    []string{"clone2", "clone3", "clone1"},
+			total:       3,
+		},
+		{
+			name:        "filter by status",
+			listRequest: types.CloneListRequest{Status: string(models.StatusOK)},
+			expected:    []string{"clone3", "clone1"},
+			total:       2,
+		},
+		{
+			name:        "filter by snapshot",
+			listRequest: types.CloneListRequest{SnapshotID: "poolB@snapshot_2"},
+			expected:    []string{"clone2"},
+			total:       1,
+		},
+		{
+			name:        "filter by pool",
+			listRequest: types.CloneListRequest{Pool: "poolA"},
+			expected:    []string{"clone3", "clone1"},
+			total:       2,
+		},
+		{
+			name:        "filter by labels",
+			listRequest: types.CloneListRequest{Labels: []string{"team=analytics"}},
+			expected:    []string{"clone1"},
+			total:       1,
+		},
+		{
+			name:        "pagination",
+			listRequest: types.CloneListRequest{Offset: 1, Limit: 1},
+			expected:    []string{"clone2"},
+			total:       3,
+		},
+		{
+			name:        "offset out of range",
+			listRequest: types.CloneListRequest{Offset: 5},
+			expected:    []string{},
+			total:       3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			page, err := testCloneList().ListClones(tc.listRequest)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, cloneIDs(page.Clones))
+			assert.Equal(t, tc.total, page.Total)
+		})
+	}
+}
+
+func TestListClonesWithInvalidRequest(t *testing.T) {
+	testCases := []types.CloneListRequest{
+		{Offset: -1},
+		{Limit: maxCloneListLimit + 1},
+		{SortBy: "name"},
+		{Order: "random"},
+		{Labels: []string{"team"}},
+	}
+
+	for _, listRequest := range testCases {
+		_, err := testCloneList().ListClones(listRequest)
+
+		var reqErr *models.Error
+		require.ErrorAs(t, err, &reqErr)
+		assert.Equal(t, models.ErrCodeBadRequest, reqErr.Code)
+	}
+}
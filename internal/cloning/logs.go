@@ -0,0 +1,31 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"context"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+// StreamCloneLogs passes logs of the clone to writeLine line by line.
+func (c *Base) StreamCloneLogs(ctx context.Context, cloneID string, opts provision.LogOptions,
+	writeLine func(string) error) error {
+	w, ok := c.findWrapper(cloneID)
+	if !ok {
+		return models.New(models.ErrCodeNotFound, "clone not found")
+	}
+
+	c.cloneMutex.RLock()
+	session := w.Session
+	c.cloneMutex.RUnlock()
+
+	if session == nil {
+		return models.New(models.ErrCodeBadRequest, "clone is not started yet")
+	}
+
+	return c.provision.StreamCloneLogs(ctx, session, opts, writeLine)
+}
@@ -0,0 +1,228 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/xid"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/util"
+)
+
+const (
+	operationsFilename = "operations.json"
+
+	// maxOperations defines the number of clone operations kept in the history.
+	maxOperations = 200
+
+	// operationInterrupted describes operations which were in progress when the instance stopped.
+	operationInterrupted = "operation has been interrupted by the instance restart"
+)
+
+const (
+	stepStartingSession  = "starting clone session"
+	stepResettingSession = "resetting clone session"
+	stepRollingBack      = "rolling back clone to checkpoint"
+	stepStoppingSession  = "stopping clone session"
+	stepRestarting       = "restarting clone container"
+	stepExporting        = "exporting clone database"
+)
+
+// operationHistory keeps a bounded history of clone operations and persists it to disk.
+type operationHistory struct {
+	mu         sync.Mutex
+	path       string
+	operations []*models.Operation
+}
+
+func newOperationHistory() *operationHistory {
+	return &operationHistory{operations: []*models.Operation{}}
+}
+
+// load restores the history from disk. Operations that were in progress are marked as failed.
+func (h *operationHistory) load(path string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.path = path
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to read operations data: %w", err)
+	}
+
+	var operations []*models.Operation
+
+	if err := json.Unmarshal(data, &operations); err != nil {
+		return fmt.Errorf("failed to decode operations data: %w", err)
+	}
+
+	now := time.Now()
+
+	for _, operation := range operations {
+		if operation.Status == models.OperationRunning {
+			operation.Status = models.OperationFailed
+			operation.Error = operationInterrupted
+			operation.UpdatedAt = now
+			operation.FinishedAt = &now
+		}
+	}
+
+	h.operations = append(operations, h.operations...)
+	h.truncate()
+
+	return h.save()
+}
+
+// start registers a new running operation.
+func (h *operationHistory) start(opType models.OperationType, cloneID, step string) models.Operation {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+
+	operation := &models.Operation{
+		ID:        xid.New().String(),
+		Type:      opType,
+		CloneID:   cloneID,
+		Status:    models.OperationRunning,
+		Step:      step,
+		StartedAt: now,
+		UpdatedAt: now,
+	}
+
+	h.operations = append(h.operations, operation)
+	h.truncate()
+	h.persist()
+
+	return *operation
+}
+
+// finish completes the operation. The operation is marked as failed if opErr is not nil.
+func (h *operationHistory) finish(operationID string, opErr error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	operation := h.find(operationID)
+	if operation == nil {
+		return
+	}
+
+	now := time.Now()
+
+	operation.Status = models.OperationFinished
+	operation.UpdatedAt = now
+	operation.FinishedAt = &now
+
+	if opErr != nil {
+		operation.Status = models.OperationFailed
+		operation.Error = errors.Cause(opErr).Error()
+	}
+
+	h.persist()
+}
+
+// get returns the operation by ID.
+func (h *operationHistory) get(operationID string) (models.Operation, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	operation := h.find(operationID)
+	if operation == nil {
+		return models.Operation{}, false
+	}
+
+	return *operation, true
+}
+
+// list returns operations starting with the most recent one. Operations are filtered by clone if cloneID is not empty.
+func (h *operationHistory) list(cloneID string) []models.Operation {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	operations := make([]models.Operation, 0, len(h.operations))
+
+	for i := len(h.operations) - 1; i >= 0; i-- {
+		if cloneID != "" && h.operations[i].CloneID != cloneID {
+			continue
+		}
+
+		operations = append(operations, *h.operations[i])
+	}
+
+	return operations
+}
+
+func (h *operationHistory) find(operationID string) *models.Operation {
+	for _, operation := range h.operations {
+		if operation.ID == operationID {
+			return operation
+		}
+	}
+
+	return nil
+}
+
+func (h *operationHistory) truncate() {
+	if len(h.operations) > maxOperations {
+		h.operations = h.operations[len(h.operations)-maxOperations:]
+	}
+}
+
+func (h *operationHistory) persist() {
+	if err := h.save(); err != nil {
+		log.Err("Failed to save the operation history:", err)
+	}
+}
+
+func (h *operationHistory) save() error {
+	if h.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(h.operations)
+	if err != nil {
+		return fmt.Errorf("failed to encode operations data: %w", err)
+	}
+
+	return os.WriteFile(h.path, data, 0600)
+}
+
+// restoreOperations loads the operation history from disk.
+func (c *Base) restoreOperations() error {
+	operationsPath, err := util.GetMetaPath(operationsFilename)
+	if err != nil {
+		return fmt.Errorf("failed to get path of an operations file: %w", err)
+	}
+
+	return c.operations.load(operationsPath)
+}
+
+// GetOperation returns the clone operation by ID.
+func (c *Base) GetOperation(operationID string) (*models.Operation, error) {
+	operation, ok := c.operations.get(operationID)
+	if !ok {
+		return nil, models.New(models.ErrCodeNotFound, "operation not found")
+	}
+
+	return &operation, nil
+}
+
+// GetOperations returns the history of clone operations. The history is filtered by clone if cloneID is not empty.
+func (c *Base) GetOperations(cloneID string) []models.Operation {
+	return c.operations.list(cloneID)
+}
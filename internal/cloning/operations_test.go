@@ -0,0 +1,89 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"errors"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+func TestOperationHistory(t *testing.T) {
+	history := newOperationHistory()
+
+	created := history.start(models.OperationCreateClone, "clone1", stepStartingSession)
+	reset := history.start(models.OperationResetClone, "clone2", stepResettingSession)
+
+	history.finish(created.ID, nil)
+	history.finish(reset.ID, errors.New("failed to reset session"))
+
+	operation, ok := history.get(created.ID)
+	require.True(t, ok)
+	assert.Equal(t, models.OperationFinished, operation.Status)
+	assert.NotNil(t, operation.FinishedAt)
+	assert.Empty(t, operation.Error)
+
+	operation, ok = history.get(reset.ID)
+	require.True(t, ok)
+	assert.Equal(t, models.OperationFailed, operation.Status)
+	assert.Equal(t, "failed to reset session", operation.Error)
+
+	_, ok = history.get("unknown")
+	assert.False(t, ok)
+
+	operations := history.list("")
+	require.Len(t, operations, 2)
+	assert.Equal(t, reset.ID, operations[0].ID)
+	assert.Equal(t, created.ID, operations[1].ID)
+
+	operations = history.list("clone1")
+	require.Len(t, operations, 1)
+	assert.Equal(t, created.ID, operations[0].ID)
+}
+
+func TestOperationHistoryLimit(t *testing.T) {
+	history := newOperationHistory()
+
+	first := history.start(models.OperationDestroyClone, "clone", stepStoppingSession)
+
+	for i := 0; i < maxOperations; i++ {
+		history.start(models.OperationDestroyClone, "clone", stepStoppingSession)
+	}
+
+	assert.Len(t, history.list(""), maxOperations)
+
+	_, ok := history.get(first.ID)
+	assert.False(t, ok)
+}
+
+func TestOperationHistoryPersistence(t *testing.T) {
+	operationsPath := path.Join(t.TempDir(), operationsFilename)
+
+	history := newOperationHistory()
+	require.NoError(t, history.load(operationsPath))
+
+	finished := history.start(models.OperationCreateClone, "clone", stepStartingSession)
+	history.finish(finished.ID, nil)
+
+	running := history.start(models.OperationResetClone, "clone", stepResettingSession)
+
+	restored := newOperationHistory()
+	require.NoError(t, restored.load(operationsPath))
+
+	operation, ok := restored.get(finished.ID)
+	require.True(t, ok)
+	assert.Equal(t, models.OperationFinished, operation.Status)
+
+	operation, ok = restored.get(running.ID)
+	require.True(t, ok)
+	assert.Equal(t, models.OperationFailed, operation.Status)
+	assert.Equal(t, operationInterrupted, operation.Error)
+	assert.NotNil(t, operation.FinishedAt)
+}
@@ -0,0 +1,120 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"fmt"
+
+	"github.com/docker/go-units"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+// Quotas defines limits of clone resources. Zero values disable the corresponding limits.
+type Quotas struct {
+	MaxClones        uint   `yaml:"maxClones"`
+	MaxClonesPerUser uint   `yaml:"maxClonesPerUser"`
+	MaxTotalDiffSize string `yaml:"maxTotalDiffSize"`
+}
+
+// refreshQuotaMetadata refreshes the sizes of clones if the total size of clones is limited.
+// Getting the size queries the storage for every clone, so it must be invoked before locking the clones mutex.
+func (c *Base) refreshQuotaMetadata() {
+	if c.config.Quotas.MaxTotalDiffSize == "" {
+		return
+	}
+
+	c.cloneMutex.RLock()
+	wrappers := make([]*CloneWrapper, 0, len(c.clones))
+
+	for _, w := range c.clones {
+		wrappers = append(wrappers, w)
+	}
+	c.cloneMutex.RUnlock()
+
+	for _, w := range wrappers {
+		c.refreshCloneMetadata(w)
+	}
+}
+
+// checkQuotas checks if a new clone of the user fits the configured quotas.
+// Warm clones occupy ports and datasets as well, so they are counted towards the maximum number of clones.
+// The total size of clones is calculated from the metadata refreshed by refreshQuotaMetadata.
+// It's not safe to invoke without clones mutex locking.
+func (c *Base) checkQuotas(user string) error {
+	quotas := c.config.Quotas
+
+	maxTotalDiffSize, err := quotas.maxTotalDiffSize()
+	if err != nil {
+		return err
+	}
+
+	numClones := uint(c.warmPool.len())
+	numUserClones := uint(0)
+
+	var totalDiffSize uint64
+
+	for _, w := range c.clones {
+		if w.Clone == nil || w.Clone.Status.Code == models.StatusDeleting {
+			continue
+		}
+
+		numClones++
+
+		if quotaUser(w.Clone) == user {
+			numUserClones++
+		}
+
+		totalDiffSize += w.Clone.Metadata.CloneDiffSize
+	}
+
+	if quotas.MaxClones > 0 && numClones >= quotas.MaxClones {
+		return models.New(models.ErrCodeQuotaExceeded,
+			fmt.Sprintf("the maximum number of clones has been reached: %d", quotas.MaxClones))
+	}
+
+	if quotas.MaxClonesPerUser > 0 && numUserClones >= quotas.MaxClonesPerUser {
+		return models.New(models.ErrCodeQuotaExceeded,
+			fmt.Sprintf("the maximum number of clones of user %q has been reached: %d", user, quotas.MaxClonesPerUser))
+	}
+
+	if maxTotalDiffSize > 0 && totalDiffSize >= maxTotalDiffSize {
+		return models.New(models.ErrCodeQuotaExceeded,
+			fmt.Sprintf("the maximum total size of clones has been reached: %s", quotas.MaxTotalDiffSize))
+	}
+
+	return nil
+}
+
+// quotaUser returns the user whose clones are limited by the per-user quota.
+// The owner is used if it is known because the database username is chosen by the caller.
+func quotaUser(clone *models.Clone) string {
+	if clone.Owner != "" {
+		return clone.Owner
+	}
+
+	return clone.DB.Username
+}
+
+// validate checks if the quotas are correctly defined.
+func (q Quotas) validate() error {
+	_, err := q.maxTotalDiffSize()
+
+	return err
+}
+
+// maxTotalDiffSize parses a human-readable limit of the total clone size.
+func (q Quotas) maxTotalDiffSize() (uint64, error) {
+	if q.MaxTotalDiffSize == "" {
+		return 0, nil
+	}
+
+	size, err := units.RAMInBytes(q.MaxTotalDiffSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value of maxTotalDiffSize %q: %w", q.MaxTotalDiffSize, err)
+	}
+
+	return uint64(size), nil
+}
@@ -0,0 +1,98 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+func TestCheckQuotas(t *testing.T) {
+	testCases := []struct {
+		name     string
+		quotas   Quotas
+		username string
+		exceeded bool
+	}{
+		{name: "no quotas", quotas: Quotas{}, username: "john"},
+		{name: "total quota is not reached", quotas: Quotas{MaxClones: 6}, username: "john"},
+		{name: "total quota is reached", quotas: Quotas{MaxClones: 5}, username: "john", exceeded: true},
+		{name: "user quota is not reached", quotas: Quotas{MaxClonesPerUser: 2}, username: "alice"},
+		{name: "user quota is reached", quotas: Quotas{MaxClonesPerUser: 2}, username: "john", exceeded: true},
+		{name: "owner quota is not reached", quotas: Quotas{MaxClonesPerUser: 2}, username: "token:ci"},
+		{name: "owner quota is reached", quotas: Quotas{MaxClonesPerUser: 1}, username: "token:ci", exceeded: true},
+		{name: "size quota is not reached", quotas: Quotas{MaxTotalDiffSize: "1KiB"}, username: "john"},
+		{name: "size quota is reached", quotas: Quotas{MaxTotalDiffSize: "500"}, username: "john", exceeded: true},
+	}
+
+	clones := map[string]*CloneWrapper{
+		"clone1": {Clone: &models.Clone{
+			DB:       models.Database{Username: "john"},
+			Status:   models.Status{Code: models.StatusOK},
+			Metadata: models.CloneMetadata{CloneDiffSize: 300},
+		}},
+		"clone2": {Clone: &models.Clone{
+			DB:       models.Database{Username: "john"},
+			Status:   models.Status{Code: models.StatusOK},
+			Metadata: models.CloneMetadata{CloneDiffSize: 200},
+		}},
+		"clone3": {Clone: &models.Clone{
+			DB:     models.Database{Username: "alice"},
+			Status: models.Status{Code: models.StatusCreating},
+		}},
+		"clone4": {Clone: &models.Clone{
+			DB:       models.Database{Username: "john"},
+			Status:   models.Status{Code: models.StatusDeleting},
+			Metadata: models.CloneMetadata{CloneDiffSize: 1000},
+		}},
+		// Clones of owners are counted regardless of the database username.
+		"clone5": {Clone: &models.Clone{
+			DB:     models.Database{Username: "alice"},
+			Owner:  "token:ci",
+			Status: models.Status{Code: models.StatusOK},
+		}},
+	}
+
+	// Warm clones are counted towards the maximum number of clones.
+	warmPool := newWarmSessions()
+	warmPool.reset("dblab_pool@snapshot_20200110000000", nil, 1)
+	warmPool.add("dblab_pool@snapshot_20200110000000", &resources.Session{Port: 6000})
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Base{config: &Config{Quotas: tc.quotas}, clones: clones, warmPool: warmPool}
+
+			err := c.checkQuotas(tc.username)
+			if !tc.exceeded {
+				require.NoError(t, err)
+				return
+			}
+
+			var quotaErr *models.Error
+			require.ErrorAs(t, err, &quotaErr)
+			assert.Equal(t, models.ErrCodeQuotaExceeded, quotaErr.Code)
+		})
+	}
+}
+
+func TestCheckQuotasWithInvalidSize(t *testing.T) {
+	c := &Base{
+		config:   &Config{Quotas: Quotas{MaxTotalDiffSize: "ten gigabytes"}},
+		clones:   map[string]*CloneWrapper{},
+		warmPool: newWarmSessions(),
+	}
+
+	assert.Error(t, c.checkQuotas("john"))
+}
+
+func TestIsValidConfigWithInvalidSize(t *testing.T) {
+	assert.NoError(t, IsValidConfig(Config{Quotas: Quotas{MaxTotalDiffSize: "10GiB"}}))
+	assert.Error(t, IsValidConfig(Config{Quotas: Quotas{MaxTotalDiffSize: "ten gigabytes"}}))
+}
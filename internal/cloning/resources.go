@@ -0,0 +1,130 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"fmt"
+
+	"github.com/docker/go-units"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+// ResourceLimits defines the maximum resources of a clone container. Zero values disable the corresponding limits.
+type ResourceLimits struct {
+	CPUShares   uint64 `yaml:"cpuShares"`
+	CPUQuota    uint64 `yaml:"cpuQuota"`
+	Memory      string `yaml:"memory"`
+	BlkioWeight uint16 `yaml:"blkioWeight"`
+}
+
+// cloneResources checks the requested clone resources against the configured maximum ones.
+// Limits omitted in the request default to the maximum ones.
+func (c *Base) cloneResources(req *types.CloneResourcesRequest) (*resources.ResourceLimits, error) {
+	maxLimits, err := c.config.MaxResources.limits()
+	if err != nil {
+		return nil, err
+	}
+
+	if req == nil {
+		req = &types.CloneResourcesRequest{}
+	}
+
+	limits := &resources.ResourceLimits{
+		CPUShares:   req.CPUShares,
+		CPUQuota:    req.CPUQuota,
+		BlkioWeight: req.BlkioWeight,
+	}
+
+	if req.Memory != "" {
+		memory, err := units.RAMInBytes(req.Memory)
+		if err != nil || memory <= 0 {
+			return nil, models.New(models.ErrCodeBadRequest, fmt.Sprintf("invalid memory limit %q", req.Memory))
+		}
+
+		limits.Memory = uint64(memory)
+	}
+
+	if err := applyMaxLimit(&limits.CPUShares, maxLimits.CPUShares, "CPU shares"); err != nil {
+		return nil, err
+	}
+
+	if err := applyMaxLimit(&limits.CPUQuota, maxLimits.CPUQuota, "CPU quota"); err != nil {
+		return nil, err
+	}
+
+	if err := applyMaxLimit(&limits.Memory, maxLimits.Memory, "memory limit"); err != nil {
+		return nil, err
+	}
+
+	blkioWeight := uint64(limits.BlkioWeight)
+
+	if err := applyMaxLimit(&blkioWeight, uint64(maxLimits.BlkioWeight), "block IO weight"); err != nil {
+		return nil, err
+	}
+
+	limits.BlkioWeight = uint16(blkioWeight)
+
+	if limits.IsEmpty() {
+		return nil, nil
+	}
+
+	return limits, nil
+}
+
+// applyMaxLimit defaults the unset value to the maximum one and rejects values exceeding the maximum.
+func applyMaxLimit(value *uint64, maxValue uint64, name string) error {
+	if maxValue == 0 {
+		return nil
+	}
+
+	if *value == 0 {
+		*value = maxValue
+		return nil
+	}
+
+	if *value > maxValue {
+		return models.New(models.ErrCodeBadRequest,
+			fmt.Sprintf("requested %s exceeds the maximum allowed value: %d > %d", name, *value, maxValue))
+	}
+
+	return nil
+}
+
+// limits converts the configured maximum resources to container limits.
+func (r ResourceLimits) limits() (*resources.ResourceLimits, error) {
+	limits := &resources.ResourceLimits{
+		CPUShares:   r.CPUShares,
+		CPUQuota:    r.CPUQuota,
+		BlkioWeight: r.BlkioWeight,
+	}
+
+	if r.Memory != "" {
+		memory, err := units.RAMInBytes(r.Memory)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of maxResources.memory %q: %w", r.Memory, err)
+		}
+
+		limits.Memory = uint64(memory)
+	}
+
+	return limits, nil
+}
+
+// resourcesModel converts container limits to the clone model representation.
+func resourcesModel(limits *resources.ResourceLimits) *models.CloneResources {
+	if limits.IsEmpty() {
+		return nil
+	}
+
+	return &models.CloneResources{
+		CPUShares:   limits.CPUShares,
+		CPUQuota:    limits.CPUQuota,
+		Memory:      limits.Memory,
+		BlkioWeight: limits.BlkioWeight,
+	}
+}
@@ -0,0 +1,84 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+func TestCloneResources(t *testing.T) {
+	testCases := []struct {
+		name      string
+		maxLimits ResourceLimits
+		request   *types.CloneResourcesRequest
+		expected  *resources.ResourceLimits
+		rejected  bool
+	}{
+		{name: "no limits", maxLimits: ResourceLimits{}, request: nil, expected: nil},
+		{
+			name:      "requested limits without maximum ones",
+			maxLimits: ResourceLimits{},
+			request:   &types.CloneResourcesRequest{CPUShares: 512, Memory: "1g"},
+			expected:  &resources.ResourceLimits{CPUShares: 512, Memory: 1 << 30},
+		},
+		{
+			name:      "maximum limits are applied by default",
+			maxLimits: ResourceLimits{CPUQuota: 100000, Memory: "4g", BlkioWeight: 500},
+			request:   nil,
+			expected:  &resources.ResourceLimits{CPUQuota: 100000, Memory: 4 << 30, BlkioWeight: 500},
+		},
+		{
+			name:      "requested limits within maximum ones",
+			maxLimits: ResourceLimits{CPUQuota: 100000, Memory: "4g"},
+			request:   &types.CloneResourcesRequest{CPUQuota: 50000, Memory: "2g", BlkioWeight: 200},
+			expected:  &resources.ResourceLimits{CPUQuota: 50000, Memory: 2 << 30, BlkioWeight: 200},
+		},
+		{
+			name:      "memory exceeds maximum",
+			maxLimits: ResourceLimits{Memory: "4g"},
+			request:   &types.CloneResourcesRequest{Memory: "8g"},
+			rejected:  true,
+		},
+		{
+			name:      "block IO weight exceeds maximum",
+			maxLimits: ResourceLimits{BlkioWeight: 500},
+			request:   &types.CloneResourcesRequest{BlkioWeight: 600},
+			rejected:  true,
+		},
+		{
+			name:      "invalid memory",
+			maxLimits: ResourceLimits{},
+			request:   &types.CloneResourcesRequest{Memory: "lots"},
+			rejected:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Base{config: &Config{MaxResources: tc.maxLimits}}
+
+			limits, err := c.cloneResources(tc.request)
+
+			if tc.rejected {
+				var reqErr *models.Error
+
+				require.ErrorAs(t, err, &reqErr)
+				assert.Equal(t, models.ErrCodeBadRequest, reqErr.Code)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, limits)
+		})
+	}
+}
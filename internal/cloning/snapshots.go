@@ -5,11 +5,16 @@
 package cloning
 
 import (
+	"fmt"
 	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/util"
@@ -20,6 +25,9 @@ type SnapshotBox struct {
 	snapshotMutex  sync.RWMutex
 	items          map[string]*models.Snapshot
 	latestSnapshot *models.Snapshot
+	loaded         bool
+	// deleting contains IDs of snapshots being deleted. No clones are created from them.
+	deleting map[string]struct{}
 }
 
 func (c *Base) fetchSnapshots() error {
@@ -31,6 +39,7 @@ func (c *Base) fetchSnapshots() error {
 	var latestSnapshot *models.Snapshot
 
 	snapshots := make(map[string]*models.Snapshot, len(entries))
+	cloneDatasets := c.cloneDatasets()
 
 	for _, entry := range entries {
 		numClones := 0
@@ -43,13 +52,16 @@ func (c *Base) fetchSnapshots() error {
 		}
 
 		currentSnapshot := &models.Snapshot{
-			ID:           entry.ID,
-			CreatedAt:    util.FormatTime(entry.CreatedAt),
-			DataStateAt:  util.FormatTime(entry.DataStateAt),
-			PhysicalSize: entry.Used,
-			LogicalSize:  entry.LogicalReferenced,
-			Pool:         entry.Pool,
-			NumClones:    numClones,
+			ID:               entry.ID,
+			CreatedAt:        util.FormatTime(entry.CreatedAt),
+			DataStateAt:      util.FormatTime(entry.DataStateAt),
+			PhysicalSize:     entry.Used,
+			LogicalSize:      entry.LogicalReferenced,
+			Pool:             entry.Pool,
+			NumClones:        numClones,
+			CloneID:          cloneDatasets[snapshotDataset(entry.ID)],
+			Protected:        entry.Protected,
+			SnapshotMetadata: entry.Metadata,
 		}
 
 		snapshots[entry.ID] = currentSnapshot
@@ -62,26 +74,65 @@ func (c *Base) fetchSnapshots() error {
 
 	return nil
 }
+
+// resetSnapshots replaces the known snapshots. Subscribers are notified about appeared and disappeared snapshots
+// except for the initial load.
 func (c *Base) resetSnapshots(snapshotMap map[string]*models.Snapshot, latestSnapshot *models.Snapshot) {
 	c.snapshotBox.snapshotMutex.Lock()
+	defer c.snapshotBox.snapshotMutex.Unlock()
+
+	if c.snapshotBox.loaded {
+		for snapshotID, snapshot := range snapshotMap {
+			if _, ok := c.snapshotBox.items[snapshotID]; !ok {
+				c.publishSnapshotEvent(models.EventSnapshotCreated, snapshot)
+			}
+		}
+
+		for snapshotID, snapshot := range c.snapshotBox.items {
+			if _, ok := snapshotMap[snapshotID]; !ok {
+				c.publishSnapshotEvent(models.EventSnapshotDestroyed, snapshot)
+			}
+		}
+	}
 
 	c.snapshotBox.latestSnapshot = latestSnapshot
 	c.snapshotBox.items = snapshotMap
-
-	c.snapshotBox.snapshotMutex.Unlock()
+	c.snapshotBox.loaded = true
 }
 
 func (c *Base) addSnapshot(snapshot *models.Snapshot) {
 	c.snapshotBox.snapshotMutex.Lock()
 
+	if _, ok := c.snapshotBox.items[snapshot.ID]; !ok {
+		c.publishSnapshotEvent(models.EventSnapshotCreated, snapshot)
+	}
+
 	c.snapshotBox.items[snapshot.ID] = snapshot
 	c.snapshotBox.latestSnapshot = defineLatestSnapshot(c.snapshotBox.latestSnapshot, snapshot)
 
 	c.snapshotBox.snapshotMutex.Unlock()
 }
 
+// removeCloneSnapshots removes snapshots of the clone, which are destroyed along with the clone.
+func (c *Base) removeCloneSnapshots(cloneID string) {
+	c.snapshotBox.snapshotMutex.Lock()
+	defer c.snapshotBox.snapshotMutex.Unlock()
+
+	for snapshotID, snapshot := range c.snapshotBox.items {
+		if snapshot.CloneID == cloneID {
+			delete(c.snapshotBox.items, snapshotID)
+			c.publishSnapshotEvent(models.EventSnapshotDestroyed, snapshot)
+		}
+	}
+}
+
 // defineLatestSnapshot compares two snapshots and defines the latest one.
+// Snapshots of clones are never considered as the latest ones.
 func defineLatestSnapshot(latest, challenger *models.Snapshot) *models.Snapshot {
+	if challenger.CloneID != "" {
+		return latest
+	}
+
 	if latest == nil || latest.DataStateAt == "" || latest.DataStateAt < challenger.DataStateAt {
 		return challenger
 	}
@@ -101,6 +152,33 @@ func (c *Base) getLatestSnapshot() (*models.Snapshot, error) {
 	return nil, errors.New("no snapshot found")
 }
 
+// getSnapshotBefore returns the newest snapshot with the data state not later than the provided time.
+// Snapshots of clones are skipped because they cannot be used to replay archived WAL.
+func (c *Base) getSnapshotBefore(targetTime time.Time) (*models.Snapshot, error) {
+	c.snapshotBox.snapshotMutex.RLock()
+	defer c.snapshotBox.snapshotMutex.RUnlock()
+
+	target := util.FormatTime(targetTime)
+
+	var snapshot *models.Snapshot
+
+	for _, item := range c.snapshotBox.items {
+		if item.CloneID != "" || item.DataStateAt == "" || item.DataStateAt > target {
+			continue
+		}
+
+		if snapshot == nil || snapshot.DataStateAt < item.DataStateAt {
+			snapshot = item
+		}
+	}
+
+	if snapshot == nil {
+		return nil, models.New(models.ErrCodeNotFound, "no snapshot found before the recovery target time")
+	}
+
+	return snapshot, nil
+}
+
 // getSnapshotByID returns the snapshot by ID.
 func (c *Base) getSnapshotByID(snapshotID string) (*models.Snapshot, error) {
 	c.snapshotBox.snapshotMutex.RLock()
@@ -167,3 +245,399 @@ func (c *Base) getSnapshotList() []models.Snapshot {
 
 	return snapshots
 }
+
+// SnapshotClone creates a snapshot of the clone data, which can be used to create new clones.
+func (c *Base) SnapshotClone(cloneID string) (*models.Snapshot, error) {
+	w, ok := c.findWrapper(cloneID)
+	if !ok {
+		return nil, models.New(models.ErrCodeNotFound, "clone not found")
+	}
+
+	originalStatus, ok := c.setBusyStatus(cloneID, models.Status{
+		Code:    models.StatusSnapshotting,
+		Message: models.CloneMessageSnapshotting,
+	})
+	if !ok {
+		if originalStatus.Code == models.StatusExporting {
+			return nil, models.New(models.ErrCodeBadRequest, "clone is being exported")
+		}
+
+		return nil, models.New(models.ErrCodeBadRequest, "clone is not ready to take a snapshot")
+	}
+
+	defer c.restoreBusyStatus(cloneID, models.StatusSnapshotting, originalStatus)
+
+	// The session is not replaced while the clone is busy.
+	c.cloneMutex.RLock()
+	session := w.Session
+	c.cloneMutex.RUnlock()
+
+	entry, err := c.provision.SnapshotSession(session)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create a snapshot of the clone")
+	}
+
+	snapshot := &models.Snapshot{
+		ID:               entry.ID,
+		CreatedAt:        util.FormatTime(entry.CreatedAt),
+		DataStateAt:      util.FormatTime(entry.DataStateAt),
+		PhysicalSize:     entry.Used,
+		LogicalSize:      entry.LogicalReferenced,
+		Pool:             entry.Pool,
+		CloneID:          cloneID,
+		SnapshotMetadata: entry.Metadata,
+	}
+
+	c.addSnapshot(snapshot)
+
+	log.Dbg("clone snapshot:", *snapshot)
+
+	return snapshot, nil
+}
+
+// DestroySnapshot destroys the snapshot. Clones created from the snapshot are destroyed only if force is set.
+func (c *Base) DestroySnapshot(snapshotID string, force bool) error {
+	snapshot, err := c.getSnapshotByID(snapshotID)
+	if err != nil {
+		return models.New(models.ErrCodeNotFound, "snapshot not found")
+	}
+
+	if snapshot.Protected {
+		return models.New(models.ErrCodeBadRequest, "snapshot is protected")
+	}
+
+	if !c.markSnapshotDeleting(snapshotID) {
+		return models.New(models.ErrCodeBadRequest, "snapshot is being deleted")
+	}
+
+	defer c.unmarkSnapshotDeleting(snapshotID)
+
+	// Dependent clones are collected after marking the snapshot, so no clones can be created from it in the meantime.
+	dependentClones := c.snapshotDependentClones(snapshotID)
+
+	if (snapshot.NumClones > 0 || len(dependentClones) > 0) && !force {
+		return models.New(models.ErrCodeBadRequest, "snapshot has dependent clones, use force to destroy them along with the snapshot")
+	}
+
+	if err := c.checkDependentClones(dependentClones); err != nil {
+		return err
+	}
+
+	// Destroy clones in reverse order because descendants must be removed before their parents.
+	for i := len(dependentClones) - 1; i >= 0; i-- {
+		if err := c.destroyDependentClone(dependentClones[i]); err != nil {
+			return errors.Wrapf(err, "failed to destroy dependent clone %s", dependentClones[i])
+		}
+	}
+
+	c.releaseWarmSessions(snapshotID)
+
+	if err := c.provision.DestroySnapshot(snapshotID); err != nil {
+		return errors.Wrap(err, "failed to destroy snapshot")
+	}
+
+	c.removeSnapshot(snapshotID)
+	c.warmPool.requestRefill()
+
+	if len(dependentClones) > 0 {
+		c.SaveClonesState()
+	}
+
+	return nil
+}
+
+// markSnapshotDeleting marks the snapshot as being deleted. It returns false if the snapshot is already being deleted.
+// The mark is set under the clone lock, so clones registered before are found as dependent ones
+// and clones registered after are refused.
+func (c *Base) markSnapshotDeleting(snapshotID string) bool {
+	c.cloneMutex.Lock()
+	defer c.cloneMutex.Unlock()
+
+	c.snapshotBox.snapshotMutex.Lock()
+	defer c.snapshotBox.snapshotMutex.Unlock()
+
+	if _, ok := c.snapshotBox.deleting[snapshotID]; ok {
+		return false
+	}
+
+	if c.snapshotBox.deleting == nil {
+		c.snapshotBox.deleting = make(map[string]struct{})
+	}
+
+	c.snapshotBox.deleting[snapshotID] = struct{}{}
+
+	return true
+}
+
+// unmarkSnapshotDeleting removes the deletion mark of the snapshot.
+func (c *Base) unmarkSnapshotDeleting(snapshotID string) {
+	c.snapshotBox.snapshotMutex.Lock()
+	defer c.snapshotBox.snapshotMutex.Unlock()
+
+	delete(c.snapshotBox.deleting, snapshotID)
+}
+
+// isSnapshotDeleting checks if the snapshot is being deleted along with the snapshot of its clone.
+// The caller must hold the clone lock.
+func (c *Base) isSnapshotDeleting(snapshotID string) bool {
+	c.snapshotBox.snapshotMutex.RLock()
+	defer c.snapshotBox.snapshotMutex.RUnlock()
+
+	visited := make(map[string]struct{})
+
+	for {
+		if _, ok := c.snapshotBox.deleting[snapshotID]; ok {
+			return true
+		}
+
+		if _, ok := visited[snapshotID]; ok {
+			return false
+		}
+
+		visited[snapshotID] = struct{}{}
+
+		snapshot, ok := c.snapshotBox.items[snapshotID]
+		if !ok || snapshot.CloneID == "" {
+			return false
+		}
+
+		w, ok := c.clones[snapshot.CloneID]
+		if !ok || w.Clone == nil || w.Clone.Snapshot == nil {
+			return false
+		}
+
+		snapshotID = w.Clone.Snapshot.ID
+	}
+}
+
+// UpdateSnapshot updates snapshot.
+func (c *Base) UpdateSnapshot(snapshotID string, patch types.SnapshotUpdateRequest) (*models.Snapshot, error) {
+	snapshot, err := c.getSnapshotByID(snapshotID)
+	if err != nil {
+		return nil, models.New(models.ErrCodeNotFound, "snapshot not found")
+	}
+
+	c.snapshotBox.snapshotMutex.RLock()
+	protected := snapshot.Protected
+	metadata := snapshot.SnapshotMetadata
+	c.snapshotBox.snapshotMutex.RUnlock()
+
+	if patch.Protected != nil && *patch.Protected != protected {
+		if err := c.provision.ProtectSnapshot(snapshotID, *patch.Protected); err != nil {
+			return nil, errors.Wrap(err, "failed to update snapshot protection")
+		}
+
+		protected = *patch.Protected
+	}
+
+	if patch.Description != nil || patch.Labels != nil {
+		if patch.Description != nil {
+			metadata.Description = *patch.Description
+		}
+
+		if patch.Labels != nil {
+			metadata.Labels = patch.Labels
+		}
+
+		if err := c.provision.SetSnapshotMetadata(snapshotID, metadata); err != nil {
+			return nil, errors.Wrap(err, "failed to update snapshot metadata")
+		}
+	}
+
+	c.snapshotBox.snapshotMutex.Lock()
+	defer c.snapshotBox.snapshotMutex.Unlock()
+
+	snapshot.Protected = protected
+	snapshot.SnapshotMetadata = metadata
+
+	updatedSnapshot := *snapshot
+
+	return &updatedSnapshot, nil
+}
+
+// removeSnapshot removes the snapshot from the list and redefines the latest snapshot.
+func (c *Base) removeSnapshot(snapshotID string) {
+	c.snapshotBox.snapshotMutex.Lock()
+	defer c.snapshotBox.snapshotMutex.Unlock()
+
+	if snapshot, ok := c.snapshotBox.items[snapshotID]; ok {
+		delete(c.snapshotBox.items, snapshotID)
+		c.publishSnapshotEvent(models.EventSnapshotDestroyed, snapshot)
+	}
+
+	if c.snapshotBox.latestSnapshot == nil || c.snapshotBox.latestSnapshot.ID != snapshotID {
+		return
+	}
+
+	var latestSnapshot *models.Snapshot
+
+	for _, snapshot := range c.snapshotBox.items {
+		latestSnapshot = defineLatestSnapshot(latestSnapshot, snapshot)
+	}
+
+	c.snapshotBox.latestSnapshot = latestSnapshot
+}
+
+// GetSnapshotDependentClones returns clones destroyed along with the snapshot if the deletion is forced.
+func (c *Base) GetSnapshotDependentClones(snapshotID string) []*models.Clone {
+	dependentClones := c.snapshotDependentClones(snapshotID)
+	clones := make([]*models.Clone, 0, len(dependentClones))
+
+	c.cloneMutex.RLock()
+	defer c.cloneMutex.RUnlock()
+
+	for _, cloneID := range dependentClones {
+		if w, ok := c.clones[cloneID]; ok && w.Clone != nil {
+			clones = append(clones, w.Clone)
+		}
+	}
+
+	return clones
+}
+
+// snapshotDependentClones returns IDs of clones created from the snapshot
+// including clones created from snapshots of these clones.
+// Parents always precede their descendants in the resulting list.
+func (c *Base) snapshotDependentClones(snapshotID string) []string {
+	c.cloneMutex.RLock()
+	defer c.cloneMutex.RUnlock()
+
+	dependentClones := []string{}
+	visited := make(map[string]struct{})
+
+	appendDependent := func(isDependent func(snapshotID string) bool) {
+		for cloneID, w := range c.clones {
+			if _, ok := visited[cloneID]; ok || w.Clone == nil || w.Clone.Snapshot == nil {
+				continue
+			}
+
+			if isDependent(w.Clone.Snapshot.ID) {
+				visited[cloneID] = struct{}{}
+				dependentClones = append(dependentClones, cloneID)
+			}
+		}
+	}
+
+	appendDependent(func(id string) bool { return id == snapshotID })
+
+	for i := 0; i < len(dependentClones); i++ {
+		parent := c.clones[dependentClones[i]]
+		if parent.Session == nil {
+			continue
+		}
+
+		dataset := cloneDataset(parent.Session)
+
+		appendDependent(func(id string) bool { return snapshotDataset(id) == dataset })
+	}
+
+	return dependentClones
+}
+
+// checkDependentClones checks if the clones can be destroyed along with the snapshot.
+// Protected clones and clones with operations in progress are never destroyed by force.
+func (c *Base) checkDependentClones(cloneIDs []string) error {
+	c.cloneMutex.RLock()
+	defer c.cloneMutex.RUnlock()
+
+	for _, cloneID := range cloneIDs {
+		w, ok := c.clones[cloneID]
+		if !ok {
+			continue
+		}
+
+		if w.IsProtected() {
+			return models.New(models.ErrCodeBadRequest, fmt.Sprintf("dependent clone %s is protected", cloneID))
+		}
+
+		if w.isBusy() {
+			return models.New(models.ErrCodeBadRequest,
+				fmt.Sprintf("dependent clone %s has status %s, try again later", cloneID, w.Clone.Status.Code))
+		}
+	}
+
+	return nil
+}
+
+// destroyDependentClone synchronously destroys the clone that blocks the snapshot deletion.
+func (c *Base) destroyDependentClone(cloneID string) error {
+	w, ok := c.findWrapper(cloneID)
+	if !ok {
+		return nil
+	}
+
+	if w.Session != nil {
+		if err := c.provision.StopSession(w.Session); err != nil {
+			return err
+		}
+	}
+
+	c.deleteClone(cloneID)
+	c.removeCloneSnapshots(cloneID)
+	c.removeCloneExports(cloneID)
+
+	if w.Clone.Snapshot != nil {
+		c.decrementCloneNumber(w.Clone.Snapshot.ID)
+	}
+
+	if w.Session != nil {
+		c.observingCh <- cloneID
+	}
+
+	return nil
+}
+
+// hasDependentClones checks if there are clones created from snapshots of the clone.
+func (c *Base) hasDependentClones(cloneID string) bool {
+	c.cloneMutex.RLock()
+	defer c.cloneMutex.RUnlock()
+
+	w, ok := c.clones[cloneID]
+	if !ok || w.Session == nil {
+		return false
+	}
+
+	dataset := cloneDataset(w.Session)
+
+	for id, dependent := range c.clones {
+		if id == cloneID || dependent.Clone == nil || dependent.Clone.Snapshot == nil {
+			continue
+		}
+
+		if snapshotDataset(dependent.Clone.Snapshot.ID) == dataset {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cloneDatasets returns a mapping of clone datasets and clone IDs.
+func (c *Base) cloneDatasets() map[string]string {
+	c.cloneMutex.RLock()
+	defer c.cloneMutex.RUnlock()
+
+	datasets := make(map[string]string, len(c.clones))
+
+	for cloneID, w := range c.clones {
+		if w.Session != nil {
+			datasets[cloneDataset(w.Session)] = cloneID
+		}
+	}
+
+	return datasets
+}
+
+// cloneDataset returns the name of the dataset that stores the clone data.
+func cloneDataset(session *resources.Session) string {
+	return session.Pool + "/" + util.GetCloneName(session.Port)
+}
+
+// snapshotDataset extracts the dataset name from the snapshot ID.
+func snapshotDataset(snapshotID string) string {
+	if idx := strings.Index(snapshotID, "@"); idx != -1 {
+		return snapshotID[:idx]
+	}
+
+	return snapshotID
+}
@@ -6,9 +6,13 @@ package cloning
 
 import (
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"gitlab.com/postgres-ai/database-lab/v3/internal/events"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
 )
 
@@ -160,3 +164,236 @@ func TestLatestSnapshots(t *testing.T) {
 		require.Equal(t, tc.result, defineLatestSnapshot(tc.latest, tc.challenger))
 	}
 }
+
+func (s *BaseCloningSuite) TestCloneSnapshotIsNotLatest() {
+	s.cloning.resetSnapshots(make(map[string]*models.Snapshot), nil)
+
+	snapshot := &models.Snapshot{
+		ID:          "dblab_pool@snapshot_20200220000000",
+		DataStateAt: "2020-02-20 00:00:00",
+	}
+
+	cloneSnapshot := &models.Snapshot{
+		ID:          "dblab_pool/dblab_clone_6000@snapshot_20200221000000",
+		DataStateAt: "2020-02-21 00:00:00",
+		CloneID:     "parentClone",
+	}
+
+	s.cloning.addSnapshot(snapshot)
+	s.cloning.addSnapshot(cloneSnapshot)
+
+	latestSnapshot, err := s.cloning.getLatestSnapshot()
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), snapshot, latestSnapshot)
+
+	s.cloning.removeCloneSnapshots("parentClone")
+
+	require.Equal(s.T(), 1, len(s.cloning.snapshotBox.items))
+	_, err = s.cloning.getSnapshotByID(cloneSnapshot.ID)
+	require.EqualError(s.T(), err, "no snapshot found")
+}
+
+func (s *BaseCloningSuite) TestDependentClones() {
+	s.cloning.setWrapper("parentClone", &CloneWrapper{
+		Clone:   &models.Clone{ID: "parentClone", Snapshot: &models.Snapshot{ID: "dblab_pool@snapshot_20200220000000"}},
+		Session: &resources.Session{Pool: "dblab_pool", Port: 6000},
+	})
+
+	s.cloning.setWrapper("siblingClone", &CloneWrapper{
+		Clone:   &models.Clone{ID: "siblingClone", Snapshot: &models.Snapshot{ID: "dblab_pool@snapshot_20200220000000"}},
+		Session: &resources.Session{Pool: "dblab_pool", Port: 6001},
+	})
+
+	require.False(s.T(), s.cloning.hasDependentClones("parentClone"))
+	require.False(s.T(), s.cloning.hasDependentClones("unknownClone"))
+
+	s.cloning.setWrapper("childClone", &CloneWrapper{
+		Clone:   &models.Clone{ID: "childClone", Snapshot: &models.Snapshot{ID: "dblab_pool/dblab_clone_6000@snapshot_20200221000000"}},
+		Session: &resources.Session{Pool: "dblab_pool", Port: 6002},
+	})
+
+	require.True(s.T(), s.cloning.hasDependentClones("parentClone"))
+	require.False(s.T(), s.cloning.hasDependentClones("siblingClone"))
+	require.False(s.T(), s.cloning.hasDependentClones("childClone"))
+
+	_, err := s.cloning.DestroyClone("parentClone")
+	require.EqualError(s.T(), err, "clone has dependent clones created from its snapshots")
+}
+
+func TestSnapshotDataset(t *testing.T) {
+	require.Equal(t, "dblab_pool/dblab_clone_6000", snapshotDataset("dblab_pool/dblab_clone_6000@snapshot_20200221000000"))
+	require.Equal(t, "dblab_pool", snapshotDataset("dblab_pool"))
+	require.Equal(t, "dblab_pool/dblab_clone_6000", cloneDataset(&resources.Session{Pool: "dblab_pool", Port: 6000}))
+}
+
+func (s *BaseCloningSuite) TestSnapshotDependentClones() {
+	const snapshotID = "dblab_pool@snapshot_20200220000000"
+
+	s.cloning.setWrapper("parentClone", &CloneWrapper{
+		Clone:   &models.Clone{ID: "parentClone", Snapshot: &models.Snapshot{ID: snapshotID}},
+		Session: &resources.Session{Pool: "dblab_pool", Port: 6000},
+	})
+
+	s.cloning.setWrapper("childClone", &CloneWrapper{
+		Clone:   &models.Clone{ID: "childClone", Snapshot: &models.Snapshot{ID: "dblab_pool/dblab_clone_6000@snapshot_20200221000000"}},
+		Session: &resources.Session{Pool: "dblab_pool", Port: 6001},
+	})
+
+	s.cloning.setWrapper("otherClone", &CloneWrapper{
+		Clone:   &models.Clone{ID: "otherClone", Snapshot: &models.Snapshot{ID: "dblab_pool@snapshot_20200222000000"}},
+		Session: &resources.Session{Pool: "dblab_pool", Port: 6002},
+	})
+
+	require.Equal(s.T(), []string{"parentClone", "childClone"}, s.cloning.snapshotDependentClones(snapshotID))
+
+	dependentClones := s.cloning.GetSnapshotDependentClones(snapshotID)
+	require.Len(s.T(), dependentClones, 2)
+	assert.Equal(s.T(), "childClone", dependentClones[1].ID)
+	require.Empty(s.T(), s.cloning.snapshotDependentClones("dblab_pool@snapshot_20200223000000"))
+}
+
+func (s *BaseCloningSuite) TestDestroySnapshotRestrictions() {
+	s.cloning.addSnapshot(&models.Snapshot{ID: "dblab_pool@snapshot_20200220000000", NumClones: 1})
+	s.cloning.addSnapshot(&models.Snapshot{ID: "dblab_pool@snapshot_20200221000000", Protected: true})
+
+	err := s.cloning.DestroySnapshot("dblab_pool@snapshot_20200222000000", false)
+	require.EqualError(s.T(), err, "snapshot not found")
+
+	err = s.cloning.DestroySnapshot("dblab_pool@snapshot_20200220000000", false)
+	require.EqualError(s.T(), err, "snapshot has dependent clones, use force to destroy them along with the snapshot")
+
+	err = s.cloning.DestroySnapshot("dblab_pool@snapshot_20200221000000", true)
+	require.EqualError(s.T(), err, "snapshot is protected")
+
+	s.cloning.setWrapper("protectedClone", &CloneWrapper{
+		Clone:   &models.Clone{ID: "protectedClone", Protected: true, Snapshot: &models.Snapshot{ID: "dblab_pool@snapshot_20200220000000"}},
+		Session: &resources.Session{Pool: "dblab_pool", Port: 6000},
+	})
+
+	err = s.cloning.DestroySnapshot("dblab_pool@snapshot_20200220000000", true)
+	require.EqualError(s.T(), err, "dependent clone protectedClone is protected")
+
+	s.cloning.setWrapper("protectedClone", &CloneWrapper{
+		Clone: &models.Clone{
+			ID:       "protectedClone",
+			Snapshot: &models.Snapshot{ID: "dblab_pool@snapshot_20200220000000"},
+			Status:   models.Status{Code: models.StatusCreating},
+		},
+	})
+
+	err = s.cloning.DestroySnapshot("dblab_pool@snapshot_20200220000000", true)
+	require.EqualError(s.T(), err, "dependent clone protectedClone has status CREATING, try again later")
+}
+
+func (s *BaseCloningSuite) TestSnapshotDeleting() {
+	const (
+		snapshotID      = "dblab_pool@snapshot_20200220000000"
+		cloneSnapshotID = "dblab_pool/dblab_clone_6000@snapshot_20200221000000"
+	)
+
+	s.cloning.addSnapshot(&models.Snapshot{ID: snapshotID})
+	s.cloning.addSnapshot(&models.Snapshot{ID: cloneSnapshotID, CloneID: "parentClone"})
+	s.cloning.setWrapper("parentClone", &CloneWrapper{
+		Clone:   &models.Clone{ID: "parentClone", Snapshot: &models.Snapshot{ID: snapshotID}},
+		Session: &resources.Session{Pool: "dblab_pool", Port: 6000},
+	})
+
+	require.False(s.T(), s.cloning.isSnapshotDeleting(cloneSnapshotID))
+
+	require.True(s.T(), s.cloning.markSnapshotDeleting(snapshotID))
+	require.False(s.T(), s.cloning.markSnapshotDeleting(snapshotID))
+
+	// Snapshots of clones are deleted along with the snapshot of their clones.
+	assert.True(s.T(), s.cloning.isSnapshotDeleting(snapshotID))
+	assert.True(s.T(), s.cloning.isSnapshotDeleting(cloneSnapshotID))
+
+	err := s.cloning.DestroySnapshot(snapshotID, true)
+	require.EqualError(s.T(), err, "snapshot is being deleted")
+
+	s.cloning.unmarkSnapshotDeleting(snapshotID)
+	assert.False(s.T(), s.cloning.isSnapshotDeleting(cloneSnapshotID))
+
+	// The mark is removed if the deletion fails.
+	s.cloning.setWrapper("parentClone", &CloneWrapper{
+		Clone:   &models.Clone{ID: "parentClone", Protected: true, Snapshot: &models.Snapshot{ID: snapshotID}},
+		Session: &resources.Session{Pool: "dblab_pool", Port: 6000},
+	})
+
+	err = s.cloning.DestroySnapshot(snapshotID, true)
+	require.EqualError(s.T(), err, "dependent clone parentClone is protected")
+	assert.False(s.T(), s.cloning.isSnapshotDeleting(snapshotID))
+}
+
+func (s *BaseCloningSuite) TestRemoveLatestSnapshot() {
+	snapshot := &models.Snapshot{ID: "dblab_pool@snapshot_20200220000000", DataStateAt: "2020-02-20 00:00:00"}
+	latestSnapshot := &models.Snapshot{ID: "dblab_pool@snapshot_20200221000000", DataStateAt: "2020-02-21 00:00:00"}
+
+	s.cloning.addSnapshot(snapshot)
+	s.cloning.addSnapshot(latestSnapshot)
+
+	s.cloning.removeSnapshot(latestSnapshot.ID)
+
+	require.Equal(s.T(), 1, len(s.cloning.snapshotBox.items))
+	require.Equal(s.T(), snapshot, s.cloning.snapshotBox.latestSnapshot)
+}
+
+func (s *BaseCloningSuite) TestSnapshotBefore() {
+	s.cloning.addSnapshot(&models.Snapshot{ID: "dblab_pool@snapshot_20200220000000", DataStateAt: "2020-02-20 00:00:00 UTC"})
+	s.cloning.addSnapshot(&models.Snapshot{ID: "dblab_pool@snapshot_20200221000000", DataStateAt: "2020-02-21 00:00:00 UTC"})
+	s.cloning.addSnapshot(&models.Snapshot{ID: "dblab_pool@snapshot_20200222000000", DataStateAt: "2020-02-22 00:00:00 UTC"})
+	s.cloning.addSnapshot(&models.Snapshot{
+		ID:          "dblab_pool/dblab_clone_6000@snapshot_20200221120000",
+		DataStateAt: "2020-02-21 12:00:00 UTC",
+		CloneID:     "c5bfuhsn6fqjv1p8bf1g",
+	})
+
+	snapshot, err := s.cloning.getSnapshotBefore(time.Date(2020, 2, 21, 14, 32, 0, 0, time.UTC))
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "dblab_pool@snapshot_20200221000000", snapshot.ID)
+
+	snapshot, err = s.cloning.getSnapshotBefore(time.Date(2020, 2, 22, 0, 0, 0, 0, time.UTC))
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "dblab_pool@snapshot_20200222000000", snapshot.ID)
+
+	_, err = s.cloning.getSnapshotBefore(time.Date(2020, 2, 19, 0, 0, 0, 0, time.UTC))
+	require.EqualError(s.T(), err, "no snapshot found before the recovery target time")
+}
+
+func TestSnapshotEvents(t *testing.T) {
+	broker := events.NewBroker()
+
+	eventCh, cancel := broker.Subscribe()
+	defer cancel()
+
+	c := &Base{broker: broker}
+	c.snapshotBox.items = make(map[string]*models.Snapshot)
+
+	initialSnapshot := &models.Snapshot{ID: "dblab_pool@snapshot_20200110000000"}
+	newSnapshot := &models.Snapshot{ID: "dblab_pool@snapshot_20200111000000"}
+
+	// The initial load does not produce events.
+	c.resetSnapshots(map[string]*models.Snapshot{initialSnapshot.ID: initialSnapshot}, initialSnapshot)
+	require.Len(t, eventCh, 0)
+
+	c.resetSnapshots(map[string]*models.Snapshot{newSnapshot.ID: newSnapshot}, newSnapshot)
+	require.Len(t, eventCh, 2)
+
+	received := map[models.EventType]string{}
+
+	for i := 0; i < 2; i++ {
+		event := <-eventCh
+		received[event.Type] = event.SnapshotID
+	}
+
+	assert.Equal(t, map[models.EventType]string{
+		models.EventSnapshotCreated:   newSnapshot.ID,
+		models.EventSnapshotDestroyed: initialSnapshot.ID,
+	}, received)
+
+	c.removeSnapshot(newSnapshot.ID)
+	c.removeSnapshot(newSnapshot.ID)
+	require.Len(t, eventCh, 1)
+
+	event := <-eventCh
+	assert.Equal(t, models.EventSnapshotDestroyed, event.Type)
+}
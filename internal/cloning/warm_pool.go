@@ -0,0 +1,256 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
+)
+
+const warmPoolCheckInterval = time.Minute
+
+// WarmPool defines the pool of clones started in advance for the latest snapshot.
+type WarmPool struct {
+	// Size defines the number of ready clones kept in the pool. The pool is disabled if Size is zero.
+	Size uint `yaml:"size"`
+}
+
+// warmSessions contains sessions started in advance and not assigned to any clone.
+// All sessions are started for the same snapshot with the same resource limits.
+type warmSessions struct {
+	mu         sync.Mutex
+	snapshotID string
+	limits     *resources.ResourceLimits
+	sessions   []*resources.Session
+	refillCh   chan struct{}
+}
+
+func newWarmSessions() *warmSessions {
+	return &warmSessions{
+		sessions: []*resources.Session{},
+		refillCh: make(chan struct{}, 1),
+	}
+}
+
+// take hands out a warm session of the snapshot with the resource limits.
+// It returns nil if there are no such warm sessions.
+func (ws *warmSessions) take(snapshotID string, limits *resources.ResourceLimits) *resources.Session {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.snapshotID != snapshotID || !ws.limits.Equal(limits) || len(ws.sessions) == 0 {
+		return nil
+	}
+
+	session := ws.sessions[0]
+	ws.sessions = ws.sessions[1:]
+
+	return session
+}
+
+// add adds a warm session of the snapshot.
+// It returns false if the pool has switched to another snapshot or resource limits.
+func (ws *warmSessions) add(snapshotID string, session *resources.Session) bool {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.snapshotID != snapshotID || !ws.limits.Equal(session.Resources) {
+		return false
+	}
+
+	ws.sessions = append(ws.sessions, session)
+
+	return true
+}
+
+// reset switches the pool to the snapshot and the resource limits and returns sessions to discard.
+// Sessions exceeding the pool size are discarded as well.
+func (ws *warmSessions) reset(snapshotID string, limits *resources.ResourceLimits, size int) []*resources.Session {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.snapshotID != snapshotID || !ws.limits.Equal(limits) {
+		discarded := ws.sessions
+
+		ws.snapshotID = snapshotID
+		ws.limits = limits
+		ws.sessions = []*resources.Session{}
+
+		return discarded
+	}
+
+	if len(ws.sessions) <= size {
+		return nil
+	}
+
+	discarded := ws.sessions[size:]
+	ws.sessions = ws.sessions[:size]
+
+	return discarded
+}
+
+// release detaches the pool from the snapshot and returns its sessions to discard.
+func (ws *warmSessions) release(snapshotID string) []*resources.Session {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.snapshotID != snapshotID {
+		return nil
+	}
+
+	discarded := ws.sessions
+
+	ws.snapshotID = ""
+	ws.sessions = []*resources.Session{}
+
+	return discarded
+}
+
+// len returns the number of warm sessions.
+func (ws *warmSessions) len() int {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	return len(ws.sessions)
+}
+
+// requestRefill schedules the pool refill without blocking.
+func (ws *warmSessions) requestRefill() {
+	select {
+	case ws.refillCh <- struct{}{}:
+	default:
+	}
+}
+
+// runWarmPool keeps the configured number of warm clones for the latest snapshot.
+// Warm clones are not stored in the clone state, so they are cleaned up as invalid ones after restart.
+func (c *Base) runWarmPool(ctx context.Context) {
+	ticker := time.NewTicker(warmPoolCheckInterval)
+	defer ticker.Stop()
+
+	c.refillWarmPool(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			c.refillWarmPool(ctx)
+
+		case <-c.warmPool.refillCh:
+			c.refillWarmPool(ctx)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// refillWarmPool discards warm clones of outdated snapshots and starts missing ones.
+// Warm clones are started with the default resource limits, i.e. the configured maximum ones.
+func (c *Base) refillWarmPool(ctx context.Context) {
+	size := int(c.config.WarmPool.Size)
+
+	var (
+		snapshotID string
+		limits     *resources.ResourceLimits
+	)
+
+	if size > 0 {
+		snapshot, err := c.getLatestSnapshot()
+		if err != nil {
+			log.Dbg("Warm pool is not filled: ", err)
+			return
+		}
+
+		snapshotID = snapshot.ID
+
+		c.cloneMutex.RLock()
+		deleting := c.isSnapshotDeleting(snapshotID)
+		c.cloneMutex.RUnlock()
+
+		if deleting {
+			log.Dbg("Warm pool is not filled: the latest snapshot is being deleted")
+			return
+		}
+
+		limits, err = c.cloneResources(nil)
+		if err != nil {
+			log.Err("Warm pool is not filled:", err)
+			return
+		}
+	}
+
+	for _, session := range c.warmPool.reset(snapshotID, limits, size) {
+		c.discardWarmSession(session)
+	}
+
+	for c.warmPool.len() < size {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		session, err := c.provision.StartWarmSession(snapshotID, limits)
+		if err != nil {
+			log.Err("Failed to start a warm clone:", err)
+			return
+		}
+
+		if !c.warmPool.add(snapshotID, session) {
+			c.discardWarmSession(session)
+			return
+		}
+
+		log.Dbg("Warm clone has been started:", session.Port)
+	}
+}
+
+// takeWarmSession hands out a warm session of the snapshot with the resource limits and schedules the pool refill.
+func (c *Base) takeWarmSession(snapshotID string, limits *resources.ResourceLimits) *resources.Session {
+	if c.config.WarmPool.Size == 0 {
+		return nil
+	}
+
+	session := c.warmPool.take(snapshotID, limits)
+
+	c.warmPool.requestRefill()
+
+	return session
+}
+
+// assignWarmSession assigns a warm session with the resource limits to the user.
+// It returns nil if no warm session is available.
+func (c *Base) assignWarmSession(snapshotID string, user resources.EphemeralUser, limits *resources.ResourceLimits) *resources.Session {
+	session := c.takeWarmSession(snapshotID, limits)
+	if session == nil {
+		return nil
+	}
+
+	if err := c.provision.AssignSession(session, user); err != nil {
+		log.Err("Failed to assign a warm clone:", err)
+		c.discardWarmSession(session)
+
+		return nil
+	}
+
+	return session
+}
+
+// releaseWarmSessions discards warm clones of the snapshot, e.g. before the snapshot is destroyed.
+func (c *Base) releaseWarmSessions(snapshotID string) {
+	for _, session := range c.warmPool.release(snapshotID) {
+		c.discardWarmSession(session)
+	}
+}
+
+func (c *Base) discardWarmSession(session *resources.Session) {
+	if err := c.provision.StopSession(session); err != nil {
+		log.Err("Failed to discard a warm clone:", err)
+	}
+}
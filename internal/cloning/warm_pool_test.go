@@ -0,0 +1,71 @@
+/*
+2022 © Postgres.ai
+*/
+
+package cloning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+)
+
+func TestWarmSessions(t *testing.T) {
+	ws := newWarmSessions()
+
+	const (
+		snapshotID       = "dblab_pool@snapshot_20200110000000"
+		latestSnapshotID = "dblab_pool@snapshot_20200111000000"
+	)
+
+	require.Empty(t, ws.reset(snapshotID, nil, 2))
+
+	assert.True(t, ws.add(snapshotID, &resources.Session{Port: 6000}))
+	assert.True(t, ws.add(snapshotID, &resources.Session{Port: 6001}))
+	assert.True(t, ws.add(snapshotID, &resources.Session{Port: 6002}))
+	assert.False(t, ws.add(latestSnapshotID, &resources.Session{Port: 6003}))
+
+	// Sessions exceeding the pool size are discarded.
+	assert.Equal(t, []*resources.Session{{Port: 6002}}, ws.reset(snapshotID, nil, 2))
+	assert.Equal(t, 2, ws.len())
+
+	assert.Nil(t, ws.take(latestSnapshotID, nil))
+	assert.Equal(t, &resources.Session{Port: 6000}, ws.take(snapshotID, nil))
+	assert.Equal(t, 1, ws.len())
+
+	// Sessions of an outdated snapshot are discarded.
+	assert.Equal(t, []*resources.Session{{Port: 6001}}, ws.reset(latestSnapshotID, nil, 2))
+	assert.Nil(t, ws.take(snapshotID, nil))
+
+	assert.True(t, ws.add(latestSnapshotID, &resources.Session{Port: 6004}))
+	assert.Empty(t, ws.release(snapshotID))
+	assert.Equal(t, []*resources.Session{{Port: 6004}}, ws.release(latestSnapshotID))
+	assert.Equal(t, 0, ws.len())
+}
+
+func TestWarmSessionsWithLimits(t *testing.T) {
+	ws := newWarmSessions()
+
+	const snapshotID = "dblab_pool@snapshot_20200110000000"
+
+	limits := &resources.ResourceLimits{CPUShares: 512, Memory: 1 << 30}
+
+	require.Empty(t, ws.reset(snapshotID, limits, 2))
+
+	// Sessions started with outdated limits are not added.
+	assert.False(t, ws.add(snapshotID, &resources.Session{Port: 6000}))
+	assert.True(t, ws.add(snapshotID, &resources.Session{Port: 6001, Resources: &resources.ResourceLimits{CPUShares: 512, Memory: 1 << 30}}))
+
+	// Only requests with the same limits take warm sessions.
+	assert.Nil(t, ws.take(snapshotID, nil))
+	assert.Nil(t, ws.take(snapshotID, &resources.ResourceLimits{CPUShares: 256, Memory: 1 << 30}))
+	assert.Equal(t, 6001, int(ws.take(snapshotID, &resources.ResourceLimits{CPUShares: 512, Memory: 1 << 30}).Port))
+
+	// Sessions are discarded when the limits change.
+	assert.True(t, ws.add(snapshotID, &resources.Session{Port: 6002, Resources: limits}))
+	assert.Len(t, ws.reset(snapshotID, nil, 2), 1)
+	assert.Equal(t, 0, ws.len())
+}
@@ -11,6 +11,7 @@ import (
 	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
 
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/util"
 )
 
 // CloneWrapper represents a cloning service wrapper.
@@ -20,6 +21,11 @@ type CloneWrapper struct {
 
 	TimeCreatedAt time.Time `json:"time_created_at"`
 	TimeStartedAt time.Time `json:"time_started_at"`
+	TimeDeleteAt  time.Time `json:"time_delete_at"`
+	TimeTouchedAt time.Time `json:"time_touched_at"`
+
+	// IdleWarned is set if the warning status of the clone is caused by the upcoming idle deletion.
+	IdleWarned bool `json:"idle_warned"`
 }
 
 // NewCloneWrapper constructs a new CloneWrapper.
@@ -32,6 +38,76 @@ func NewCloneWrapper(clone *models.Clone, createdAt time.Time) *CloneWrapper {
 	return w
 }
 
+// setDeleteAt sets the expiration time of the clone. The zero time means that the clone never expires.
+func (cw *CloneWrapper) setDeleteAt(deleteAt time.Time) {
+	cw.TimeDeleteAt = deleteAt
+
+	if cw.Clone == nil {
+		return
+	}
+
+	cw.Clone.DeleteAt = ""
+
+	if !deleteAt.IsZero() {
+		cw.Clone.DeleteAt = util.FormatTime(deleteAt)
+	}
+}
+
+// idleSince returns the time the idle period of the clone is counted from.
+func (cw *CloneWrapper) idleSince() time.Time {
+	if cw.TimeTouchedAt.After(cw.TimeStartedAt) {
+		return cw.TimeTouchedAt
+	}
+
+	return cw.TimeStartedAt
+}
+
+// isReady checks if the clone is started and accepts connections.
+func (cw *CloneWrapper) isReady() bool {
+	if cw.Session == nil || cw.Clone == nil {
+		return false
+	}
+
+	return cw.Clone.Status.Code == models.StatusOK || cw.Clone.Status.Code == models.StatusWarning
+}
+
+// isBusy checks if an operation changing the clone is in progress.
+func (cw *CloneWrapper) isBusy() bool {
+	if cw.Clone == nil {
+		return false
+	}
+
+	switch cw.Clone.Status.Code {
+	case models.StatusCreating, models.StatusResetting, models.StatusDeleting, models.StatusExporting, models.StatusSnapshotting,
+		models.StatusRestarting:
+		return true
+	}
+
+	return false
+}
+
+// isRestartable checks whether the clone container can be restarted. Failed clones can be restarted if they have a session.
+func (cw *CloneWrapper) isRestartable() bool {
+	return cw.isReady() || (cw.Session != nil && cw.Clone != nil && cw.Clone.Status.Code == models.StatusFatal)
+}
+
+// operationInProgressError returns the error refusing to change the clone while it is being exported or snapshotted.
+func (cw *CloneWrapper) operationInProgressError() error {
+	if cw.Clone == nil {
+		return nil
+	}
+
+	switch cw.Clone.Status.Code {
+	case models.StatusExporting:
+		return models.New(models.ErrCodeBadRequest, "clone is being exported")
+
+	case models.StatusSnapshotting:
+		return models.New(models.ErrCodeBadRequest, "clone snapshot is being taken")
+	}
+
+	return nil
+}
+
 // IsProtected checks if clone is protected.
 func (cw CloneWrapper) IsProtected() bool {
 	return cw.Clone != nil && cw.Clone.Protected
@@ -0,0 +1,76 @@
+/*
+2022 © Postgres.ai
+*/
+
+// Package events delivers engine events to subscribers.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+// subscriberBufferSize defines the number of events queued for a subscriber.
+// Events are dropped for subscribers that do not keep up.
+const subscriberBufferSize = 64
+
+// Broker fans out engine events to subscribers.
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[chan models.Event]struct{}
+}
+
+// NewBroker creates a new event broker.
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[chan models.Event]struct{}),
+	}
+}
+
+// Publish sends the event to all subscribers without blocking. Publishing to a nil broker is a no-op.
+func (b *Broker) Publish(event models.Event) {
+	if b == nil {
+		return
+	}
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			log.Dbg("Event subscriber is not ready, the event is dropped:", event.Type)
+		}
+	}
+}
+
+// Subscribe registers a new subscriber. The returned function cancels the subscription and closes the channel.
+func (b *Broker) Subscribe() (<-chan models.Event, func()) {
+	ch := make(chan models.Event, subscriberBufferSize)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+
+			close(ch)
+		})
+	}
+
+	return ch, cancel
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+func TestBroker(t *testing.T) {
+	broker := NewBroker()
+
+	first, cancelFirst := broker.Subscribe()
+	second, cancelSecond := broker.Subscribe()
+
+	defer cancelSecond()
+
+	broker.Publish(models.Event{Type: models.EventCloneStatus, CloneID: "clone", Status: string(models.StatusOK)})
+
+	for _, ch := range []<-chan models.Event{first, second} {
+		event := <-ch
+		assert.Equal(t, models.EventCloneStatus, event.Type)
+		assert.Equal(t, "clone", event.CloneID)
+		assert.False(t, event.CreatedAt.IsZero())
+	}
+
+	cancelFirst()
+	cancelFirst()
+
+	_, ok := <-first
+	assert.False(t, ok)
+
+	broker.Publish(models.Event{Type: models.EventSnapshotCreated})
+
+	event := <-second
+	assert.Equal(t, models.EventSnapshotCreated, event.Type)
+}
+
+func TestBrokerSlowSubscriber(t *testing.T) {
+	broker := NewBroker()
+
+	ch, cancel := broker.Subscribe()
+	defer cancel()
+
+	for i := 0; i < subscriberBufferSize+1; i++ {
+		broker.Publish(models.Event{Type: models.EventAlert})
+	}
+
+	require.Len(t, ch, subscriberBufferSize)
+}
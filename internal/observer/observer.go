@@ -196,6 +196,15 @@ func (o *Observer) maskLogs(entry []string, maskedFieldIndexes []int) {
 	}
 }
 
+// MaskText applies the replacement rules to the text, e.g. to hide sensitive data in clone logs.
+func (o *Observer) MaskText(text string) string {
+	for _, rule := range o.replacementRules {
+		text = rule.re.ReplaceAllString(text, rule.replace)
+	}
+
+	return text
+}
+
 // AddObservingClone adds a new observing session to storage.
 func (o *Observer) AddObservingClone(cloneID string, port uint, session *ObservingClone) {
 	o.sessionMu.Lock()
@@ -45,3 +45,18 @@ func TestMaskingField(t *testing.T) {
 		assert.Equal(t, tc.expectedResult, testLogEntry)
 	}
 }
+
+func TestMaskText(t *testing.T) {
+	o := Observer{
+		replacementRules: []ReplacementRule{
+			{
+				re:      regexp.MustCompile(`[a-z0-9._%+\-]+(@[a-z0-9.\-]+\.[a-z]{2,4})`),
+				replace: "xxx$1",
+			},
+		},
+	}
+
+	assert.Equal(t, `LOG:  statement: select * from users where email = 'xxx@example.com';`,
+		o.MaskText(`LOG:  statement: select * from users where email = 'abc@example.com';`))
+	assert.Equal(t, "LOG:  checkpoint starting: time", o.MaskText("LOG:  checkpoint starting: time"))
+}
@@ -7,6 +7,8 @@ package platform
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 
 	"github.com/pkg/errors"
 
@@ -14,10 +16,18 @@ import (
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
 )
 
+const (
+	// personalTokenIdentityPrefix defines the prefix of identities of Platform Personal Token holders.
+	personalTokenIdentityPrefix = "platform:"
+
+	personalTokenFingerprintLength = 16
+)
+
 // PersonalTokenVerifier declares an interface of a struct for Platform Personal Token verification.
 type PersonalTokenVerifier interface {
 	IsAllowedToken(ctx context.Context, token string) bool
 	IsPersonalTokenEnabled() bool
+	TokenIdentity(token string) string
 }
 
 // Config provides configuration for the Platform service.
@@ -95,6 +105,14 @@ func (s *Service) IsPersonalTokenEnabled() bool {
 	return s.cfg.EnablePersonalToken
 }
 
+// TokenIdentity returns the identity of the Platform Personal Token holder.
+// Personal tokens belong to a single user, so the identity is derived from the token fingerprint.
+func (s *Service) TokenIdentity(personalToken string) string {
+	fingerprint := sha256.Sum256([]byte(personalToken))
+
+	return personalTokenIdentityPrefix + hex.EncodeToString(fingerprint[:])[:personalTokenFingerprintLength]
+}
+
 // isAllowedOrganization checks if organization is associated to the current Platform service.
 func (s *Service) isAllowedOrganization(organizationID uint) bool {
 	return organizationID != 0 && organizationID == s.organizationID
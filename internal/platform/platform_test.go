@@ -26,3 +26,13 @@ func TestIfOrganizationIsAllowed(t *testing.T) {
 	assert.Equal(t, s.isAllowedOrganization(0), false)
 	assert.Equal(t, s.isAllowedOrganization(1), true)
 }
+
+func TestTokenIdentity(t *testing.T) {
+	s := Service{}
+
+	identity := s.TokenIdentity("PersonalToken")
+	assert.Equal(t, "platform:", identity[:9])
+	assert.Len(t, identity, 25)
+	assert.Equal(t, identity, s.TokenIdentity("PersonalToken"))
+	assert.NotEqual(t, identity, s.TokenIdentity("AnotherToken"))
+}
@@ -49,6 +49,11 @@ const (
 	// recoverySignal defines the name of the file which means that recovery is initialized for Postgres (>=12).
 	recoverySignal = "recovery.signal"
 
+	restoreCommandOption       = "restore_command"
+	standbyModeOption          = "standby_mode"
+	recoveryTargetActionOption = "recovery_target_action"
+	promoteTargetAction        = "promote"
+
 	// Database Lab configuration files.
 	// configPrefix defines a file prefix for Database Lab configuration files.
 	configPrefix = "postgresql.dblab."
@@ -339,6 +344,41 @@ func (m *Manager) ApplyRecovery(cfg map[string]string) error {
 	return nil
 }
 
+// ApplyRecoveryTarget switches the instance from the standby mode to the targeted recovery.
+// Postgres replays archived WAL using the existing restore_command until the target is reached and then promotes.
+func (m *Manager) ApplyRecoveryTarget(target map[string]string) error {
+	recoveryCfg, err := m.ReadRecoveryConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to read recovery configuration")
+	}
+
+	if recoveryCfg[restoreCommandOption] == "" {
+		return errors.New("restore_command is not configured, archived WAL cannot be replayed")
+	}
+
+	delete(recoveryCfg, standbyModeOption)
+
+	for option, value := range target {
+		recoveryCfg[option] = value
+	}
+
+	recoveryCfg[recoveryTargetActionOption] = promoteTargetAction
+
+	if err := m.rewriteConfig(m.recoveryPath(), recoveryCfg); err != nil {
+		return err
+	}
+
+	if m.pgVersion < defaults.PGVersion12 {
+		return nil
+	}
+
+	if err := m.removeOptionally(m.standbySignalPath()); err != nil {
+		return err
+	}
+
+	return tools.TouchFile(m.recoverySignalPath())
+}
+
 // ReadRecoveryConfig reads a recovery configuration file.
 func (m *Manager) ReadRecoveryConfig() (map[string]string, error) {
 	return readConfig(m.recoveryPath())
@@ -2,6 +2,7 @@ package pgconfig
 
 import (
 	"os"
+	"path"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -35,3 +36,26 @@ recovery_target_timeline = latest
 	assert.Equal(t, expected["standby_mode"], fileConfig["standby_mode"])
 	assert.Equal(t, expected["recovery_target_timeline"], fileConfig["recovery_target_timeline"])
 }
+
+func TestApplyRecoveryTarget(t *testing.T) {
+	dataDir := t.TempDir()
+	m := Manager{dataDir: dataDir, pgVersion: 14}
+
+	require.EqualError(t, m.ApplyRecoveryTarget(map[string]string{"recovery_target_lsn": "16/B374D848"}),
+		"restore_command is not configured, archived WAL cannot be replayed")
+
+	require.NoError(t, m.ApplyRecovery(map[string]string{"restore_command": "wal-g wal-fetch %f %p"}))
+	require.NoError(t, m.ApplyRecoveryTarget(map[string]string{"recovery_target_lsn": "16/B374D848"}))
+
+	recoveryConfig, err := m.ReadRecoveryConfig()
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"restore_command":        "wal-g wal-fetch %f %p",
+		"recovery_target_lsn":    "16/B374D848",
+		"recovery_target_action": "promote",
+	}, recoveryConfig)
+
+	assert.NoFileExists(t, path.Join(dataDir, standbySignal))
+	assert.FileExists(t, path.Join(dataDir, recoverySignal))
+}
@@ -29,6 +29,12 @@ const (
 	// waitPostgresStartTimeout defines timeout to wait for Postgres start.
 	waitPostgresStartTimeout = 360
 
+	// waitPostgresRecoveryTimeout defines timeout to wait for Postgres to replay archived WAL up to the recovery target.
+	waitPostgresRecoveryTimeout = 7200
+
+	// recoveryTargetTimeFormat defines the format of the recovery target time passed to Postgres.
+	recoveryTargetTimeFormat = "2006-01-02 15:04:05.999999-07:00"
+
 	// checkPostgresStatusPeriod defines period to check Postgres status.
 	checkPostgresStatusPeriod = 500
 
@@ -51,15 +57,31 @@ func Start(r runners.Runner, c *resources.AppConfig) error {
 		}
 	}
 
+	if c.RecoveryTarget != nil {
+		configManager, err := pgconfig.NewCorrector(c.DataDir())
+		if err != nil {
+			return errors.Wrap(err, "failed to create a config manager")
+		}
+
+		if err := configManager.ApplyRecoveryTarget(recoveryTargetConfig(c.RecoveryTarget)); err != nil {
+			return errors.Wrap(err, "cannot apply recovery target")
+		}
+	}
+
 	if err := docker.RunContainer(r, c); err != nil {
 		return errors.Wrap(err, "failed to run container")
 	}
 
 	// Waiting for server to become ready and promote if needed.
-	first := true
+	// A recovering instance promotes itself once the recovery target is reached.
+	first := c.RecoveryTarget == nil
 	cnt := 0
 	waitPostgresTimeout := waitPostgresConnectionTimeout
 
+	if c.RecoveryTarget != nil {
+		waitPostgresTimeout = waitPostgresRecoveryTimeout
+	}
+
 	for {
 		logs, err := docker.GetLogs(r, c, logsMinuteWindow)
 		if err != nil {
@@ -67,7 +89,8 @@ func Start(r runners.Runner, c *resources.AppConfig) error {
 		}
 
 		fatalCount := strings.Count(logs, "FATAL")
-		startingUpCount := strings.Count(logs, "FATAL: the database system is starting up")
+		startingUpCount := strings.Count(logs, "FATAL: the database system is starting up") +
+			strings.Count(logs, "the database system is not yet accepting connections")
 
 		if fatalCount > 0 && startingUpCount != fatalCount {
 			return errors.Wrap(fmt.Errorf("postgres fatal error"), "cannot start Postgres")
@@ -119,6 +142,15 @@ func Start(r runners.Runner, c *resources.AppConfig) error {
 	return nil
 }
 
+// recoveryTargetConfig builds recovery parameters for the recovery target.
+func recoveryTargetConfig(target *resources.RecoveryTarget) map[string]string {
+	if target.Time != nil {
+		return map[string]string{"recovery_target_time": target.Time.UTC().Format(recoveryTargetTimeFormat)}
+	}
+
+	return map[string]string{"recovery_target_lsn": target.LSN}
+}
+
 // Stop stops Postgres instance.
 func Stop(r runners.Runner, p *resources.Pool, name string) error {
 	log.Dbg("Stopping Postgres container...")
@@ -145,6 +177,15 @@ func List(r runners.Runner, label string) ([]string, error) {
 	return docker.ListContainers(r, label)
 }
 
+// Checkpoint runs a checkpoint to flush the Postgres data to disk.
+func Checkpoint(c *resources.AppConfig) error {
+	if _, err := runSimpleSQL("checkpoint", getPgConnStr(c.Host, c.DB.DBName, c.DB.Username, c.Port)); err != nil {
+		return errors.Wrap(err, "failed to run checkpoint")
+	}
+
+	return nil
+}
+
 func pgctlPromote(r runners.Runner, c *resources.AppConfig) (string, error) {
 	promoteCmd := `pg_ctl --pgdata ` + c.DataDir() + ` ` +
 		`-W ` + // No wait.
@@ -3,6 +3,7 @@ package postgres
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -72,3 +73,12 @@ func TestRemoveContainers(t *testing.T) {
 		assert.Equal(t, tc.err, errors.Cause(err))
 	}
 }
+
+func TestRecoveryTargetConfig(t *testing.T) {
+	targetTime := time.Date(2021, 8, 1, 14, 32, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	assert.Equal(t, map[string]string{"recovery_target_time": "2021-08-01 12:32:00+00:00"},
+		recoveryTargetConfig(&resources.RecoveryTarget{Time: &targetTime}))
+	assert.Equal(t, map[string]string{"recovery_target_lsn": "16/B374D848"},
+		recoveryTargetConfig(&resources.RecoveryTarget{LSN: "16/B374D848"}))
+}
@@ -60,6 +60,9 @@ func RunContainer(r runners.Runner, c *resources.AppConfig) error {
 		containerFlags = append(containerFlags, fmt.Sprintf("--%s=%s", flagName, flagValue))
 	}
 
+	// Clone limits go after the common container flags to take precedence over them.
+	containerFlags = append(containerFlags, resourceFlags(c.Resources)...)
+
 	// TODO (akartasov): use Docker client instead of command execution.
 	instancePort := strconv.Itoa(int(c.Port))
 	dockerRunCmd := strings.Join([]string{
@@ -90,6 +93,33 @@ func RunContainer(r runners.Runner, c *resources.AppConfig) error {
 	return nil
 }
 
+// resourceFlags builds flags limiting resources of the clone container.
+func resourceFlags(limits *resources.ResourceLimits) []string {
+	if limits.IsEmpty() {
+		return nil
+	}
+
+	flags := []string{}
+
+	if limits.CPUShares > 0 {
+		flags = append(flags, fmt.Sprintf("--cpu-shares=%d", limits.CPUShares))
+	}
+
+	if limits.CPUQuota > 0 {
+		flags = append(flags, fmt.Sprintf("--cpu-quota=%d", limits.CPUQuota))
+	}
+
+	if limits.Memory > 0 {
+		flags = append(flags, fmt.Sprintf("--memory=%d", limits.Memory))
+	}
+
+	if limits.BlkioWeight > 0 {
+		flags = append(flags, fmt.Sprintf("--blkio-weight=%d", limits.BlkioWeight))
+	}
+
+	return flags
+}
+
 func getMountVolumes(r runners.Runner, c *resources.AppConfig, containerID string) ([]string, error) {
 	inspectCmd := "docker inspect -f '{{ json .Mounts }}' " + containerID
 
@@ -71,3 +71,30 @@ func TestVolumesBuilding(t *testing.T) {
 		assert.Equal(t, tc.expectedVolumes, volumes)
 	}
 }
+
+func TestResourceFlags(t *testing.T) {
+	testCases := []struct {
+		limits        *resources.ResourceLimits
+		expectedFlags []string
+	}{
+		{limits: nil, expectedFlags: nil},
+		{limits: &resources.ResourceLimits{}, expectedFlags: nil},
+		{
+			limits:        &resources.ResourceLimits{Memory: 2 << 30},
+			expectedFlags: []string{"--memory=2147483648"},
+		},
+		{
+			limits: &resources.ResourceLimits{CPUShares: 512, CPUQuota: 50000, Memory: 1 << 30, BlkioWeight: 300},
+			expectedFlags: []string{
+				"--cpu-shares=512",
+				"--cpu-quota=50000",
+				"--memory=1073741824",
+				"--blkio-weight=300",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedFlags, resourceFlags(tc.limits))
+	}
+}
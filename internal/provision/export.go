@@ -0,0 +1,159 @@
+/*
+2022 © Postgres.ai
+*/
+
+package provision
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/pkg/errors"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/retrieval/engine/postgres/tools"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/retrieval/engine/postgres/tools/cont"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/util"
+)
+
+const (
+	// dblabExportLabel defines a label value for export containers.
+	dblabExportLabel = "dblab_export"
+
+	exportContainerPrefix = "dblab_export_"
+)
+
+// ExportFormat defines the pg_dump output format of a clone export.
+type ExportFormat string
+
+const (
+	// CustomExportFormat defines a single archive file, which can be restored by pg_restore.
+	CustomExportFormat ExportFormat = "custom"
+	// DirectoryExportFormat defines a directory with a file per table, which can be restored by pg_restore.
+	DirectoryExportFormat ExportFormat = "directory"
+)
+
+// ExportOptions defines the content of a clone export.
+type ExportOptions struct {
+	Format ExportFormat
+	DBName string
+	// Schemas and Tables limit the export to the matching objects. Empty lists export the whole database.
+	Schemas []string
+	Tables  []string
+}
+
+// ExportDir returns the directory of clone exports in the pool of the session.
+func (p *Provisioner) ExportDir(session *resources.Session) (string, error) {
+	fsm, err := p.pm.GetFSManager(session.Pool)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find a filesystem manager of this session")
+	}
+
+	return fsm.Pool().ExportDir(), nil
+}
+
+// ExportSession dumps the database of the clone session to the artifact path.
+// pg_dump runs in a satellite container connected to the clone through its Unix socket.
+func (p *Provisioner) ExportSession(ctx context.Context, session *resources.Session, opts ExportOptions, artifactPath string) error {
+	fsm, err := p.pm.GetFSManager(session.Pool)
+	if err != nil {
+		return errors.Wrap(err, "failed to find a filesystem manager of this session")
+	}
+
+	if err := os.MkdirAll(path.Dir(artifactPath), 0755); err != nil {
+		return errors.Wrap(err, "failed to create the export directory")
+	}
+
+	if err := tools.PullImage(ctx, p.dockerClient, p.config.DockerImage); err != nil {
+		return errors.Wrap(err, "failed to pull image")
+	}
+
+	hostConfig := &container.HostConfig{}
+
+	// Both the clone socket directory and the export directory are located in the pool directory.
+	poolDir := path.Join(fsm.Pool().MountDir, fsm.Pool().PoolDirName)
+
+	if err := tools.AddVolumesToHostConfig(ctx, p.dockerClient, hostConfig, poolDir); err != nil {
+		return errors.Wrap(err, "failed to mount the pool directory")
+	}
+
+	containerName := exportContainerPrefix + util.GetCloneName(session.Port)
+
+	exportCont, err := p.dockerClient.ContainerCreate(ctx,
+		&container.Config{
+			Labels: map[string]string{
+				cont.DBLabSatelliteLabel:  dblabExportLabel,
+				cont.DBLabInstanceIDLabel: p.instanceID,
+			},
+			Image:      p.config.DockerImage,
+			Entrypoint: []string{"sleep", "infinity"},
+		},
+		hostConfig,
+		&network.NetworkingConfig{},
+		nil,
+		containerName,
+	)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create container %q", containerName)
+	}
+
+	defer tools.RemoveContainer(ctx, p.dockerClient, exportCont.ID, cont.StopTimeout)
+
+	if err := p.dockerClient.ContainerStart(ctx, exportCont.ID, types.ContainerStartOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to start container %q", containerName)
+	}
+
+	log.Msg(fmt.Sprintf("Exporting clone %s to %s", util.GetCloneName(session.Port), artifactPath))
+
+	out, err := tools.ExecCommandWithOutput(ctx, p.dockerClient, exportCont.ID, types.ExecConfig{
+		Cmd: exportCommand(session, opts, artifactPath),
+	})
+	if err != nil {
+		log.Dbg(out)
+
+		if removeErr := os.RemoveAll(artifactPath); removeErr != nil {
+			log.Err("Failed to remove an incomplete export:", removeErr)
+		}
+
+		return errors.Wrap(err, "failed to dump the clone database")
+	}
+
+	return nil
+}
+
+// exportCommand builds the pg_dump command exporting the clone database.
+// The connection is passed as a connection string with the quoted database name
+// and the clone host and port placed last, so that they cannot be overridden by the database name.
+func exportCommand(session *resources.Session, opts ExportOptions, artifactPath string) []string {
+	connInfo := fmt.Sprintf("dbname=%s user=%s host=%s port=%d",
+		quoteConnInfoValue(opts.DBName), quoteConnInfoValue(session.User), quoteConnInfoValue(session.SocketHost), session.Port)
+
+	cmd := []string{"pg_dump",
+		"--dbname", connInfo,
+		"--format", string(opts.Format),
+		"--file", artifactPath,
+		"--no-password",
+	}
+
+	for _, schema := range opts.Schemas {
+		cmd = append(cmd, "--schema", schema)
+	}
+
+	for _, table := range opts.Tables {
+		cmd = append(cmd, "--table", table)
+	}
+
+	return cmd
+}
+
+// quoteConnInfoValue quotes the value of a libpq connection string keyword.
+func quoteConnInfoValue(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
@@ -0,0 +1,52 @@
+package provision
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+)
+
+func TestExportCommand(t *testing.T) {
+	session := &resources.Session{
+		Port:       6000,
+		User:       "postgres",
+		SocketHost: "/var/lib/dblab/dblab_pool/sockets/dblab_clone_6000",
+	}
+
+	testCases := []struct {
+		opts     ExportOptions
+		expected []string
+	}{
+		{
+			opts: ExportOptions{Format: CustomExportFormat, DBName: "test"},
+			expected: []string{"pg_dump",
+				"--dbname", "dbname='test' user='postgres' host='/var/lib/dblab/dblab_pool/sockets/dblab_clone_6000' port=6000",
+				"--format", "custom", "--file", "/exports/export.dump", "--no-password"},
+		},
+		{
+			opts: ExportOptions{
+				Format:  DirectoryExportFormat,
+				DBName:  "test",
+				Schemas: []string{"public", "billing"},
+				Tables:  []string{"public.users"},
+			},
+			expected: []string{"pg_dump",
+				"--dbname", "dbname='test' user='postgres' host='/var/lib/dblab/dblab_pool/sockets/dblab_clone_6000' port=6000",
+				"--format", "directory", "--file", "/exports/export.dump", "--no-password",
+				"--schema", "public", "--schema", "billing", "--table", "public.users"},
+		},
+		{
+			// The database name cannot override the host and port of the clone.
+			opts: ExportOptions{Format: CustomExportFormat, DBName: `host=/tmp port=6001 dbname='postgres\`},
+			expected: []string{"pg_dump",
+				"--dbname", `dbname='host=/tmp port=6001 dbname=\'postgres\\' user='postgres' host='/var/lib/dblab/dblab_pool/sockets/dblab_clone_6000' port=6000`,
+				"--format", "custom", "--file", "/exports/export.dump", "--no-password"},
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, exportCommand(session, tc.opts, "/exports/export.dump"))
+	}
+}
@@ -0,0 +1,342 @@
+/*
+2022 © Postgres.ai
+*/
+
+package provision
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/pkg/errors"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/util"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/util/pglog"
+)
+
+const (
+	logFollowInterval = time.Second
+
+	// csvLogTimeLayout defines the format of the log time, which is the first field of a CSV log entry.
+	csvLogTimeLayout = "2006-01-02 15:04:05.999 MST"
+)
+
+// LogSource defines the source of clone logs.
+type LogSource string
+
+const (
+	// ContainerLogSource defines the output of the clone container.
+	ContainerLogSource LogSource = "container"
+	// CSVLogSource defines Postgres CSV log files of the clone.
+	CSVLogSource LogSource = "csv"
+)
+
+// LogOptions defines the part of clone logs to read.
+type LogOptions struct {
+	Source LogSource
+	// Since skips log lines written before the time. Zero value reads logs from the beginning.
+	Since time.Time
+	// Tail limits the number of the last lines read. Zero value reads all lines.
+	Tail int
+	// Follow waits for new log lines until the context is canceled.
+	Follow bool
+}
+
+// StreamCloneLogs passes logs of the clone session to writeLine line by line.
+func (p *Provisioner) StreamCloneLogs(ctx context.Context, session *resources.Session, opts LogOptions,
+	writeLine func(string) error) error {
+	switch opts.Source {
+	case CSVLogSource:
+		fsm, err := p.pm.GetFSManager(session.Pool)
+		if err != nil {
+			return errors.Wrap(err, "failed to find a filesystem manager of this session")
+		}
+
+		return streamCSVLogs(ctx, fsm.Pool().ClonePath(session.Port), opts, writeLine)
+
+	case ContainerLogSource, "":
+		return p.streamContainerLogs(ctx, util.GetCloneName(session.Port), opts, writeLine)
+
+	default:
+		return errors.Errorf("unknown log source %q", opts.Source)
+	}
+}
+
+// CloneContainerLogs returns the last lines of the clone container output.
+func (p *Provisioner) CloneContainerLogs(ctx context.Context, containerName string, lines int) (string, error) {
+	var output strings.Builder
+
+	if err := p.streamContainerLogs(ctx, containerName, LogOptions{Tail: lines}, func(line string) error {
+		output.WriteString(line + "\n")
+		return nil
+	}); err != nil {
+		return "", err
+	}
+
+	return output.String(), nil
+}
+
+func (p *Provisioner) streamContainerLogs(ctx context.Context, containerName string, opts LogOptions,
+	writeLine func(string) error) error {
+	logOptions := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     opts.Follow,
+		Tail:       "all",
+	}
+
+	if !opts.Since.IsZero() {
+		logOptions.Since = strconv.FormatInt(opts.Since.Unix(), 10)
+	}
+
+	if opts.Tail > 0 {
+		logOptions.Tail = strconv.Itoa(opts.Tail)
+	}
+
+	logs, err := p.dockerClient.ContainerLogs(ctx, containerName, logOptions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get logs of container %s", containerName)
+	}
+
+	defer func() { _ = logs.Close() }()
+
+	reader, writer := io.Pipe()
+
+	// Clone containers run without TTY, so the output streams are multiplexed.
+	go func() {
+		_, err := stdcopy.StdCopy(writer, writer, logs)
+		_ = writer.CloseWithError(err)
+	}()
+
+	defer func() { _ = reader.Close() }()
+
+	scanner := bufio.NewScanner(reader)
+
+	for scanner.Scan() {
+		if err := writeLine(scanner.Text()); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return errors.Wrapf(err, "failed to read logs of container %s", containerName)
+	}
+
+	return nil
+}
+
+// streamCSVLogs passes lines of Postgres CSV log files located in the clone directory to writeLine.
+func streamCSVLogs(ctx context.Context, cloneDir string, opts LogOptions, writeLine func(string) error) error {
+	filter := &sinceFilter{since: opts.Since}
+	tail := &lineTail{size: opts.Tail}
+
+	followLine := func(line string) error {
+		if !filter.match(line) {
+			return nil
+		}
+
+		return writeLine(line)
+	}
+
+	selector := pglog.NewSelector(cloneDir)
+	selector.SetMinimumTime(opts.Since)
+
+	if err := selector.DiscoverLogDir(); err != nil {
+		if !opts.Follow {
+			return errors.Wrap(err, "failed to discover CSV log files")
+		}
+
+		// Postgres may not have created the log directory or the first log file yet.
+		log.Dbg("Waiting for CSV log files:", err)
+
+		return followCSVLogs(ctx, cloneDir, "", 0, followLine)
+	}
+
+	selector.FilterOldFilesInList()
+
+	emit := func(line string) error {
+		if !filter.match(line) {
+			return nil
+		}
+
+		if opts.Tail > 0 {
+			tail.add(line)
+			return nil
+		}
+
+		return writeLine(line)
+	}
+
+	var (
+		filename string
+		offset   int64
+	)
+
+	for {
+		nextFilename, err := selector.Next()
+		if err != nil {
+			if err == pglog.ErrLastFile {
+				break
+			}
+
+			return errors.Wrap(err, "failed to get a CSV log filename")
+		}
+
+		filename = nextFilename
+
+		if offset, err = readLogLines(filename, 0, emit); err != nil {
+			return err
+		}
+	}
+
+	for _, line := range tail.lines {
+		if err := writeLine(line); err != nil {
+			return err
+		}
+	}
+
+	if !opts.Follow {
+		return nil
+	}
+
+	return followCSVLogs(ctx, cloneDir, filename, offset, followLine)
+}
+
+// followCSVLogs passes lines appended to the current log file and lines of newer log files until the context is canceled.
+// If filename is empty, it waits for the first log file to appear.
+func followCSVLogs(ctx context.Context, cloneDir, filename string, offset int64, writeLine func(string) error) error {
+	ticker := time.NewTicker(logFollowInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+
+		case <-ctx.Done():
+			return nil
+		}
+
+		var err error
+
+		if filename != "" {
+			if offset, err = readLogLines(filename, offset, writeLine); err != nil {
+				return err
+			}
+		}
+
+		for _, newerFilename := range newerLogFiles(cloneDir, filename) {
+			filename = newerFilename
+
+			if offset, err = readLogLines(filename, 0, writeLine); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// newerLogFiles returns log files created after the given one.
+func newerLogFiles(cloneDir, filename string) []string {
+	selector := pglog.NewSelector(cloneDir)
+
+	if err := selector.DiscoverLogDir(); err != nil {
+		log.Dbg("Failed to discover CSV log files:", err)
+		return nil
+	}
+
+	newerFilenames := []string{}
+
+	for {
+		nextFilename, err := selector.Next()
+		if err != nil {
+			break
+		}
+
+		if nextFilename > filename {
+			newerFilenames = append(newerFilenames, nextFilename)
+		}
+	}
+
+	return newerFilenames
+}
+
+// readLogLines reads complete lines of the file starting from the offset and returns the offset of the first unread byte.
+// A trailing line without the line break is left unread because it may still be being written.
+func readLogLines(filename string, offset int64, writeLine func(string) error) (int64, error) {
+	logFile, err := os.Open(filename)
+	if err != nil {
+		return offset, errors.Wrap(err, "failed to open a CSV log file")
+	}
+
+	defer func() {
+		if err := logFile.Close(); err != nil {
+			log.Errf("Failed to close a CSV log file: %s", err.Error())
+		}
+	}()
+
+	if _, err := logFile.Seek(offset, io.SeekStart); err != nil {
+		return offset, errors.Wrap(err, "failed to seek a CSV log file")
+	}
+
+	reader := bufio.NewReader(logFile)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				return offset, nil
+			}
+
+			return offset, errors.Wrap(err, "failed to read a CSV log file")
+		}
+
+		offset += int64(len(line))
+
+		if err := writeLine(strings.TrimSuffix(line, "\n")); err != nil {
+			return offset, err
+		}
+	}
+}
+
+// sinceFilter skips log entries written before the time.
+// Lines without the log time continue a multi-line entry and share its time.
+type sinceFilter struct {
+	since     time.Time
+	entryTime time.Time
+}
+
+func (f *sinceFilter) match(line string) bool {
+	if f.since.IsZero() {
+		return true
+	}
+
+	if idx := strings.IndexByte(line, ','); idx > 0 {
+		if entryTime, err := time.Parse(csvLogTimeLayout, line[:idx]); err == nil {
+			f.entryTime = entryTime
+		}
+	}
+
+	return !f.entryTime.Before(f.since)
+}
+
+// lineTail keeps the last lines.
+type lineTail struct {
+	size  int
+	lines []string
+}
+
+func (t *lineTail) add(line string) {
+	t.lines = append(t.lines, line)
+
+	if len(t.lines) > t.size {
+		t.lines = t.lines[len(t.lines)-t.size:]
+	}
+}
@@ -0,0 +1,129 @@
+package provision
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	firstLogFile = `2021-01-10 10:00:00.000 UTC,"john","test",1,"[local]",,1,,,0,LOG,00000,"statement: select 1;",,,,,,,,,"psql"
+2021-01-10 10:05:00.000 UTC,"john","test",1,"[local]",,2,,,0,ERROR,42601,"syntax error at or near ""selec""",,,,,,"selec
+1",,,"psql"
+`
+	secondLogFile = `2021-01-10 11:00:00.000 UTC,"john","test",1,"[local]",,3,,,0,LOG,00000,"statement: select 2;",,,,,,,,,"psql"
+2021-01-10 11:00:01.000 UTC,"john","test",1,"[local]",,4,,,0,LOG,00000,"statement: select 3;",,,,,,,,,"psql"
+2021-01-10 11:00:02.000 UTC,"john","test",1,"[local]",,5,,,0,LOG,00000,"statement: sel`
+)
+
+func prepareLogDir(t *testing.T) string {
+	cloneDir := t.TempDir()
+	logDir := path.Join(cloneDir, "log")
+
+	require.NoError(t, os.Mkdir(logDir, 0700))
+	require.NoError(t, os.WriteFile(path.Join(logDir, "postgresql-2021-01-10_100000.csv"), []byte(firstLogFile), 0600))
+	require.NoError(t, os.WriteFile(path.Join(logDir, "postgresql-2021-01-10_110000.csv"), []byte(secondLogFile), 0600))
+
+	return cloneDir
+}
+
+func collectCSVLogs(t *testing.T, cloneDir string, opts LogOptions) []string {
+	lines := []string{}
+
+	err := streamCSVLogs(context.Background(), cloneDir, opts, func(line string) error {
+		lines = append(lines, line)
+		return nil
+	})
+	require.NoError(t, err)
+
+	return lines
+}
+
+func TestStreamCSVLogs(t *testing.T) {
+	cloneDir := prepareLogDir(t)
+
+	lines := collectCSVLogs(t, cloneDir, LogOptions{Source: CSVLogSource})
+	require.Len(t, lines, 5)
+	assert.Contains(t, lines[0], "select 1;")
+	assert.Equal(t, `1",,,"psql"`, lines[2])
+	assert.Contains(t, lines[4], "select 3;")
+
+	lines = collectCSVLogs(t, cloneDir, LogOptions{Source: CSVLogSource, Tail: 2})
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "select 2;")
+	assert.Contains(t, lines[1], "select 3;")
+
+	// Continuation lines of multi-line entries share the entry time.
+	lines = collectCSVLogs(t, cloneDir, LogOptions{
+		Source: CSVLogSource,
+		Since:  time.Date(2021, 1, 10, 10, 1, 0, 0, time.UTC),
+	})
+	require.Len(t, lines, 4)
+	assert.Contains(t, lines[0], "syntax error")
+	assert.Equal(t, `1",,,"psql"`, lines[1])
+}
+
+func TestFollowCSVLogs(t *testing.T) {
+	cloneDir := prepareLogDir(t)
+	linesCh := make(chan string, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		_ = streamCSVLogs(ctx, cloneDir, LogOptions{Source: CSVLogSource, Tail: 1, Follow: true}, func(line string) error {
+			linesCh <- line
+			return nil
+		})
+	}()
+
+	assert.Contains(t, <-linesCh, "select 3;")
+
+	logFile, err := os.OpenFile(path.Join(cloneDir, "log", "postgresql-2021-01-10_110000.csv"), os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+
+	_, err = logFile.WriteString("ect 4;\",,,,,,,,,\"psql\"\n")
+	require.NoError(t, err)
+	require.NoError(t, logFile.Close())
+
+	select {
+	case line := <-linesCh:
+		assert.Contains(t, line, "select 4;")
+
+	case <-time.After(5 * time.Second):
+		t.Fatal("appended log line has not been read")
+	}
+}
+
+func TestFollowCSVLogsWithoutLogFiles(t *testing.T) {
+	cloneDir := t.TempDir()
+	linesCh := make(chan string, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		_ = streamCSVLogs(ctx, cloneDir, LogOptions{Source: CSVLogSource, Follow: true}, func(line string) error {
+			linesCh <- line
+			return nil
+		})
+	}()
+
+	logDir := path.Join(cloneDir, "log")
+
+	require.NoError(t, os.Mkdir(logDir, 0700))
+	require.NoError(t, os.WriteFile(path.Join(logDir, "postgresql-2021-01-10_100000.csv"), []byte(firstLogFile), 0600))
+
+	select {
+	case line := <-linesCh:
+		assert.Contains(t, line, "select 1;")
+
+	case <-time.After(5 * time.Second):
+		t.Fatal("lines of the new log file have not been read")
+	}
+}
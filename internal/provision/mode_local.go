@@ -149,7 +149,43 @@ func (p *Provisioner) ContainerOptions() models.ContainerOptions {
 
 // StartSession starts a new session.
 func (p *Provisioner) StartSession(snapshotID string, user resources.EphemeralUser,
-	extraConfig map[string]string) (*resources.Session, error) {
+	extraConfig map[string]string, limits *resources.ResourceLimits) (*resources.Session, error) {
+	return p.startSession(snapshotID, &user, extraConfig, limits, nil)
+}
+
+// StartRecoverySession starts a new session from the pre-snapshot of the snapshot
+// and replays archived WAL up to the recovery target before handing the session out.
+func (p *Provisioner) StartRecoverySession(snapshotID string, user resources.EphemeralUser,
+	extraConfig map[string]string, limits *resources.ResourceLimits,
+	recoveryTarget *resources.RecoveryTarget) (*resources.Session, error) {
+	return p.startSession(snapshotID, &user, extraConfig, limits, recoveryTarget)
+}
+
+// StartWarmSession starts a new session without an ephemeral user. The user is created when the session is assigned.
+func (p *Provisioner) StartWarmSession(snapshotID string, limits *resources.ResourceLimits) (*resources.Session, error) {
+	return p.startSession(snapshotID, nil, nil, limits, nil)
+}
+
+// AssignSession creates the ephemeral user in a running warm session.
+func (p *Provisioner) AssignSession(session *resources.Session, user resources.EphemeralUser) error {
+	fsm, err := p.pm.GetFSManager(session.Pool)
+	if err != nil {
+		return errors.Wrap(err, "failed to find a filesystem manager of this session")
+	}
+
+	appConfig := p.getAppConfig(fsm.Pool(), util.GetCloneName(session.Port), session.Port)
+
+	if err := postgres.CreateUser(appConfig, user); err != nil {
+		return errors.Wrap(err, "failed to create user")
+	}
+
+	session.EphemeralUser = user
+
+	return nil
+}
+
+func (p *Provisioner) startSession(snapshotID string, user *resources.EphemeralUser, extraConfig map[string]string,
+	limits *resources.ResourceLimits, recoveryTarget *resources.RecoveryTarget) (*resources.Session, error) {
 	snapshot, err := p.getSnapshot(snapshotID)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get snapshots")
@@ -179,31 +215,51 @@ func (p *Provisioner) StartSession(snapshotID string, user resources.EphemeralUs
 		}
 	}()
 
-	if err = fsm.CreateClone(name, snapshot.ID); err != nil {
+	cloneSource := snapshot.ID
+
+	if recoveryTarget != nil {
+		if cloneSource, err = fsm.GetPreSnapshot(snapshot.ID); err != nil {
+			return nil, errors.Wrap(err, "point-in-time recovery is available only for snapshots of physical mode")
+		}
+	}
+
+	if err = fsm.CreateClone(name, cloneSource); err != nil {
 		return nil, errors.Wrap(err, "failed to create clone")
 	}
 
 	appConfig := p.getAppConfig(fsm.Pool(), name, port)
 	appConfig.SetExtraConf(extraConfig)
+	appConfig.RecoveryTarget = recoveryTarget
+	appConfig.Resources = limits
 
 	if err = postgres.Start(p.runner, appConfig); err != nil {
 		return nil, errors.Wrap(err, "failed to start a container")
 	}
 
-	if err = p.prepareDB(appConfig, user); err != nil {
+	if user == nil {
+		err = p.resetPasswords(appConfig)
+	} else {
+		err = p.prepareDB(appConfig, *user)
+	}
+
+	if err != nil {
 		return nil, errors.Wrap(err, "failed to prepare a database")
 	}
 
 	atomic.AddUint32(&p.sessionCounter, 1)
 
 	session := &resources.Session{
-		ID:            strconv.FormatUint(uint64(p.sessionCounter), 10),
-		Pool:          fsm.Pool().Name,
-		Port:          port,
-		User:          appConfig.DB.Username,
-		SocketHost:    appConfig.Host,
-		EphemeralUser: user,
-		ExtraConfig:   extraConfig,
+		ID:          strconv.FormatUint(uint64(p.sessionCounter), 10),
+		Pool:        fsm.Pool().Name,
+		Port:        port,
+		User:        appConfig.DB.Username,
+		SocketHost:  appConfig.Host,
+		ExtraConfig: extraConfig,
+		Resources:   limits,
+	}
+
+	if user != nil {
+		session.EphemeralUser = *user
 	}
 
 	return session, nil
@@ -281,6 +337,7 @@ func (p *Provisioner) ResetSession(session *resources.Session, snapshotID string
 
 	appConfig := p.getAppConfig(newFSManager.Pool(), name, session.Port)
 	appConfig.SetExtraConf(session.ExtraConfig)
+	appConfig.Resources = session.Resources
 
 	if err = postgres.Start(p.runner, appConfig); err != nil {
 		return nil, errors.Wrap(err, "failed to start container")
@@ -299,6 +356,147 @@ func (p *Provisioner) ResetSession(session *resources.Session, snapshotID string
 	return snapshotModel, nil
 }
 
+// SnapshotSession creates a snapshot of the session data, which can be used to create new clones.
+func (p *Provisioner) SnapshotSession(session *resources.Session) (*resources.Snapshot, error) {
+	fsm, err := p.pm.GetFSManager(session.Pool)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find a filesystem manager of this session")
+	}
+
+	name := util.GetCloneName(session.Port)
+	appConfig := p.getAppConfig(fsm.Pool(), name, session.Port)
+
+	if err := postgres.Checkpoint(appConfig); err != nil {
+		return nil, errors.Wrap(err, "failed to make a checkpoint")
+	}
+
+	snapshotName, err := fsm.CreateSnapshot(name, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create snapshot")
+	}
+
+	return p.getSnapshot(snapshotName)
+}
+
+// CreateCheckpoint takes a checkpoint of the session data, which the session can be rolled back to.
+func (p *Provisioner) CreateCheckpoint(session *resources.Session) (*resources.Checkpoint, error) {
+	fsm, err := p.pm.GetFSManager(session.Pool)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find a filesystem manager of this session")
+	}
+
+	name := util.GetCloneName(session.Port)
+	appConfig := p.getAppConfig(fsm.Pool(), name, session.Port)
+
+	if err := postgres.Checkpoint(appConfig); err != nil {
+		return nil, errors.Wrap(err, "failed to make a checkpoint")
+	}
+
+	createdAt := time.Now()
+
+	checkpointID, err := fsm.CreateCheckpoint(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create checkpoint")
+	}
+
+	return &resources.Checkpoint{ID: checkpointID, CreatedAt: createdAt}, nil
+}
+
+// ListCheckpoints returns checkpoints of the session.
+func (p *Provisioner) ListCheckpoints(session *resources.Session) ([]resources.Checkpoint, error) {
+	fsm, err := p.pm.GetFSManager(session.Pool)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find a filesystem manager of this session")
+	}
+
+	return fsm.ListCheckpoints(util.GetCloneName(session.Port))
+}
+
+// RollbackSession rolls the session data back to the checkpoint and restarts the container.
+func (p *Provisioner) RollbackSession(session *resources.Session, checkpointID string) error {
+	fsm, err := p.pm.GetFSManager(session.Pool)
+	if err != nil {
+		return errors.Wrap(err, "failed to find a filesystem manager of this session")
+	}
+
+	name := util.GetCloneName(session.Port)
+
+	if err := postgres.Stop(p.runner, fsm.Pool(), name); err != nil {
+		return errors.Wrap(err, "failed to stop container")
+	}
+
+	if err := fsm.RollbackCheckpoint(name, checkpointID); err != nil {
+		return errors.Wrap(err, "failed to roll back to checkpoint")
+	}
+
+	appConfig := p.getAppConfig(fsm.Pool(), name, session.Port)
+	appConfig.SetExtraConf(session.ExtraConfig)
+	appConfig.Resources = session.Resources
+
+	if err := postgres.Start(p.runner, appConfig); err != nil {
+		return errors.Wrap(err, "failed to start container")
+	}
+
+	return nil
+}
+
+// DestroySnapshot destroys the snapshot along with all its descendants.
+func (p *Provisioner) DestroySnapshot(snapshotID string) error {
+	fsm, err := p.snapshotFSManager(snapshotID)
+	if err != nil {
+		return err
+	}
+
+	if err := fsm.DestroySnapshot(snapshotID); err != nil {
+		return errors.Wrap(err, "failed to destroy snapshot")
+	}
+
+	return nil
+}
+
+// ProtectSnapshot sets or removes the protection of the snapshot against the retention cleanup.
+func (p *Provisioner) ProtectSnapshot(snapshotID string, protected bool) error {
+	fsm, err := p.snapshotFSManager(snapshotID)
+	if err != nil {
+		return err
+	}
+
+	if err := fsm.ProtectSnapshot(snapshotID, protected); err != nil {
+		return errors.Wrap(err, "failed to protect snapshot")
+	}
+
+	return nil
+}
+
+// SetSnapshotMetadata stores the metadata of the snapshot.
+func (p *Provisioner) SetSnapshotMetadata(snapshotID string, metadata resources.SnapshotMetadata) error {
+	fsm, err := p.snapshotFSManager(snapshotID)
+	if err != nil {
+		return err
+	}
+
+	if err := fsm.SetSnapshotMetadata(snapshotID, metadata); err != nil {
+		return errors.Wrap(err, "failed to set snapshot metadata")
+	}
+
+	return nil
+}
+
+// snapshotFSManager returns a filesystem manager of the pool containing the snapshot.
+func (p *Provisioner) snapshotFSManager(snapshotID string) (pool.FSManager, error) {
+	snapshot, err := p.getSnapshot(snapshotID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get snapshot")
+	}
+
+	fsm, err := p.pm.GetFSManager(snapshot.Pool)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find a filesystem manager of this snapshot")
+	}
+
+	return fsm, nil
+}
+
 // GetSnapshots provides a snapshot list from active pools.
 func (p *Provisioner) GetSnapshots() ([]resources.Snapshot, error) {
 	snapshots := []resources.Snapshot{}
@@ -687,12 +885,8 @@ func (p *Provisioner) scanCSVLogFile(ctx context.Context, filename string, avail
 }
 
 func (p *Provisioner) prepareDB(pgConf *resources.AppConfig, user resources.EphemeralUser) error {
-	if !p.config.KeepUserPasswords {
-		whitelist := []string{p.dbCfg.Username}
-
-		if err := postgres.ResetAllPasswords(pgConf, whitelist); err != nil {
-			return errors.Wrap(err, "failed to reset all passwords")
-		}
+	if err := p.resetPasswords(pgConf); err != nil {
+		return err
 	}
 
 	if err := postgres.CreateUser(pgConf, user); err != nil {
@@ -702,6 +896,20 @@ func (p *Provisioner) prepareDB(pgConf *resources.AppConfig, user resources.Ephe
 	return nil
 }
 
+func (p *Provisioner) resetPasswords(pgConf *resources.AppConfig) error {
+	if p.config.KeepUserPasswords {
+		return nil
+	}
+
+	whitelist := []string{p.dbCfg.Username}
+
+	if err := postgres.ResetAllPasswords(pgConf, whitelist); err != nil {
+		return errors.Wrap(err, "failed to reset all passwords")
+	}
+
+	return nil
+}
+
 // IsCloneRunning checks if clone is running.
 func (p *Provisioner) IsCloneRunning(ctx context.Context, cloneName string) bool {
 	isRunning, err := docker.IsContainerRunning(ctx, p.dockerClient, cloneName)
@@ -722,6 +930,11 @@ func (p *Provisioner) StartCloneContainer(ctx context.Context, containerName str
 	return p.dockerClient.ContainerStart(ctx, containerName, types.ContainerStartOptions{})
 }
 
+// RestartCloneContainer restarts clone container.
+func (p *Provisioner) RestartCloneContainer(ctx context.Context, containerName string) error {
+	return p.dockerClient.ContainerRestart(ctx, containerName, nil)
+}
+
 // DetectDBVersion detects version of the database.
 func (p *Provisioner) DetectDBVersion() string {
 	fsManager := p.pm.First()
@@ -80,6 +80,30 @@ func (m mockFSManager) DestroySnapshot(snapshotName string) (err error) {
 	return nil
 }
 
+func (m mockFSManager) ProtectSnapshot(snapshotName string, protected bool) error {
+	return nil
+}
+
+func (m mockFSManager) SetSnapshotMetadata(snapshotName string, metadata resources.SnapshotMetadata) error {
+	return nil
+}
+
+func (m mockFSManager) GetPreSnapshot(snapshotName string) (string, error) {
+	return "", nil
+}
+
+func (m mockFSManager) CreateCheckpoint(cloneName string) (string, error) {
+	return "", nil
+}
+
+func (m mockFSManager) ListCheckpoints(cloneName string) ([]resources.Checkpoint, error) {
+	return nil, nil
+}
+
+func (m mockFSManager) RollbackCheckpoint(cloneName, checkpointID string) error {
+	return nil
+}
+
 func (m mockFSManager) CleanupSnapshots(retentionLimit int) ([]string, error) {
 	return nil, nil
 }
@@ -23,6 +23,7 @@ import (
 type FSManager interface {
 	Cloner
 	Snapshotter
+	Checkpointer
 	StateReporter
 	Pooler
 }
@@ -44,10 +45,20 @@ type StateReporter interface {
 type Snapshotter interface {
 	CreateSnapshot(poolSuffix, dataStateAt string) (snapshotName string, err error)
 	DestroySnapshot(snapshotName string) (err error)
+	ProtectSnapshot(snapshotName string, protected bool) error
+	SetSnapshotMetadata(snapshotName string, metadata resources.SnapshotMetadata) error
+	GetPreSnapshot(snapshotName string) (string, error)
 	CleanupSnapshots(retentionLimit int) ([]string, error)
 	GetSnapshots() ([]resources.Snapshot, error)
 }
 
+// Checkpointer describes methods of clone checkpoint management.
+type Checkpointer interface {
+	CreateCheckpoint(cloneName string) (checkpointID string, err error)
+	ListCheckpoints(cloneName string) ([]resources.Checkpoint, error)
+	RollbackCheckpoint(cloneName, checkpointID string) error
+}
+
 // Pooler describes methods for Pool providing.
 type Pooler interface {
 	Pool() *resources.Pool
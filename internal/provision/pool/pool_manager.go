@@ -27,6 +27,9 @@ import (
 const (
 	// ext4 defines the ext4 filesystem name.
 	ext4 = "ext4"
+
+	// defaultExportSubDir defines the directory of clone exports if it is not configured.
+	defaultExportSubDir = "exports"
 )
 
 // Manager describes a pool manager.
@@ -46,6 +49,7 @@ type Config struct {
 	DataSubDir        string `yaml:"dataSubDir"`
 	SocketSubDir      string `yaml:"socketSubDir"`
 	ObserverSubDir    string `yaml:"observerSubDir"`
+	ExportSubDir      string `yaml:"exportSubDir"`
 	PreSnapshotSuffix string `yaml:"preSnapshotSuffix"`
 	SelectedPool      string `yaml:"selectedPool"`
 }
@@ -293,6 +297,7 @@ func (pm *Manager) examineEntries(entries []os.DirEntry) (map[string]FSManager,
 			DataSubDir:     pm.cfg.DataSubDir,
 			SocketSubDir:   pm.cfg.SocketSubDir,
 			ObserverSubDir: pm.cfg.ObserverSubDir,
+			ExportSubDir:   pm.exportSubDir(),
 		}
 		pool.SetStatus(resources.EmptyPool)
 
@@ -397,3 +402,11 @@ func (pm *Manager) describeAvailablePools() []string {
 
 	return availablePools
 }
+
+func (pm *Manager) exportSubDir() string {
+	if pm.cfg.ExportSubDir == "" {
+		return defaultExportSubDir
+	}
+
+	return pm.cfg.ExportSubDir
+}
@@ -6,6 +6,7 @@ package resources
 
 import (
 	"path"
+	"time"
 )
 
 // AppConfig currently stores Postgres configuration (other application in the future too).
@@ -18,10 +19,22 @@ type AppConfig struct {
 	DB          *DB
 	NetworkID   string
 
+	// RecoveryTarget defines the point up to which archived WAL is replayed before the clone is handed out.
+	RecoveryTarget *RecoveryTarget
+
+	// Resources defines limits of the clone container resources.
+	Resources *ResourceLimits
+
 	ContainerConf map[string]string
 	pgExtraConf   map[string]string
 }
 
+// RecoveryTarget describes the point in WAL history to recover data to. Either Time or LSN is set.
+type RecoveryTarget struct {
+	Time *time.Time
+	LSN  string
+}
+
 // DB describes a default database configuration.
 type DB struct {
 	Username string
@@ -35,6 +35,7 @@ type Pool struct {
 	DataSubDir     string
 	SocketSubDir   string
 	ObserverSubDir string
+	ExportSubDir   string
 	mu             sync.RWMutex
 	status         PoolStatus
 }
@@ -69,6 +70,11 @@ func (p *Pool) ObserverDir(port uint) string {
 	return path.Join(p.ClonePath(port), p.ObserverSubDir)
 }
 
+// ExportDir returns a path to the directory of clone exports of the storage pool.
+func (p *Pool) ExportDir() string {
+	return path.Join(p.MountDir, p.PoolDirName, p.ExportSubDir)
+}
+
 // ClonesDir returns a path to the clones directory of the storage pool.
 func (p *Pool) ClonesDir() string {
 	return path.Join(p.MountDir, p.PoolDirName, p.CloneSubDir)
@@ -20,6 +20,29 @@ type Session struct {
 	SocketHost    string            `json:"socketHost"`
 	EphemeralUser EphemeralUser     `json:"ephemeralUser"`
 	ExtraConfig   map[string]string `json:"extraConfig"`
+	Resources     *ResourceLimits   `json:"resources,omitempty"`
+}
+
+// ResourceLimits defines limits of clone container resources. Zero values disable the corresponding limits.
+type ResourceLimits struct {
+	CPUShares   uint64 `json:"cpuShares,omitempty"`
+	CPUQuota    uint64 `json:"cpuQuota,omitempty"` // Microseconds of CPU time per 100ms period.
+	Memory      uint64 `json:"memory,omitempty"`   // Bytes.
+	BlkioWeight uint16 `json:"blkioWeight,omitempty"`
+}
+
+// IsEmpty checks whether no limits are defined.
+func (l *ResourceLimits) IsEmpty() bool {
+	return l == nil || *l == ResourceLimits{}
+}
+
+// Equal checks whether the limits are the same. Nil limits are equal to empty ones.
+func (l *ResourceLimits) Equal(other *ResourceLimits) bool {
+	if l.IsEmpty() || other.IsEmpty() {
+		return l.IsEmpty() && other.IsEmpty()
+	}
+
+	return *l == *other
 }
 
 // EphemeralUser describes an ephemeral database user defined by Database Lab users.
@@ -39,6 +62,25 @@ type Snapshot struct {
 	Used              uint64
 	LogicalReferenced uint64
 	Pool              string
+	Protected         bool
+	Metadata          SnapshotMetadata
+}
+
+// SnapshotMetadata describes the origin and the purpose of a snapshot.
+type SnapshotMetadata struct {
+	Description       string            `json:"description,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	RetrievalMode     string            `json:"retrievalMode,omitempty"`
+	Source            string            `json:"source,omitempty"`
+	PreprocessingHash string            `json:"preprocessingHash,omitempty"`
+	PostgresVersion   string            `json:"postgresVersion,omitempty"`
+}
+
+// Checkpoint defines a restore point taken inside a clone.
+type Checkpoint struct {
+	ID        string
+	CreatedAt time.Time
+	Used      uint64
 }
 
 // SessionState defines current state of a Session.
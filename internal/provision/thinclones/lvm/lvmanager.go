@@ -99,6 +99,40 @@ func (m *LVManager) DestroySnapshot(_ string) error {
 	return nil
 }
 
+// ProtectSnapshot is not supported in LVM mode.
+func (m *LVManager) ProtectSnapshot(_ string, _ bool) error {
+	log.Msg("Protecting a snapshot is not supported in LVM mode. Skip the operation.")
+
+	return nil
+}
+
+// SetSnapshotMetadata is not supported in LVM mode.
+func (m *LVManager) SetSnapshotMetadata(_ string, _ resources.SnapshotMetadata) error {
+	log.Msg("Snapshot metadata is not supported in LVM mode. Skip the operation.")
+
+	return nil
+}
+
+// GetPreSnapshot is not supported in LVM mode.
+func (m *LVManager) GetPreSnapshot(_ string) (string, error) {
+	return "", errors.New("pre-snapshots are not supported in LVM mode")
+}
+
+// CreateCheckpoint is not supported in LVM mode.
+func (m *LVManager) CreateCheckpoint(_ string) (string, error) {
+	return "", errors.New("checkpoints are not supported in LVM mode")
+}
+
+// ListCheckpoints is not supported in LVM mode.
+func (m *LVManager) ListCheckpoints(_ string) ([]resources.Checkpoint, error) {
+	return []resources.Checkpoint{}, nil
+}
+
+// RollbackCheckpoint is not supported in LVM mode.
+func (m *LVManager) RollbackCheckpoint(_, _ string) error {
+	return errors.New("checkpoints are not supported in LVM mode")
+}
+
 // CleanupSnapshots is not supported in LVM mode.
 func (m *LVManager) CleanupSnapshots(_ int) ([]string, error) {
 	log.Msg("Cleanup snapshots is not supported in LVM mode. Skip the operation.")
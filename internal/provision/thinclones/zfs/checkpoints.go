@@ -0,0 +1,103 @@
+/*
+2022 © Postgres.ai
+*/
+
+package zfs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/util"
+)
+
+const (
+	// checkpointPrefix defines the prefix of clone checkpoint names.
+	checkpointPrefix = "checkpoint_"
+
+	// checkpointIDFormat defines the time format of checkpoint IDs.
+	// Microseconds keep IDs of checkpoints taken within the same second unique.
+	checkpointIDFormat = util.DataStateAtFormat + ".000000"
+)
+
+// CreateCheckpoint takes a snapshot of the clone dataset, which the clone can be rolled back to.
+func (m *Manager) CreateCheckpoint(cloneName string) (string, error) {
+	checkpointID := newCheckpointID(time.Now())
+
+	cmd := fmt.Sprintf("zfs snapshot %s", m.checkpointName(cloneName, checkpointID))
+
+	if _, err := m.runner.Run(cmd, true); err != nil {
+		return "", errors.Wrap(err, "failed to create checkpoint")
+	}
+
+	return checkpointID, nil
+}
+
+// ListCheckpoints returns checkpoints of the clone sorted by creation time.
+// Checkpoints created within the same second are sorted by IDs, which include microseconds.
+func (m *Manager) ListCheckpoints(cloneName string) ([]resources.Checkpoint, error) {
+	cmd := fmt.Sprintf("zfs list -t snapshot -H -p -o name,creation,used -s creation -s name -d 1 %s/%s", m.config.Pool.Name, cloneName)
+
+	out, err := m.runner.Run(cmd, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list checkpoints")
+	}
+
+	return parseCheckpoints(out)
+}
+
+// RollbackCheckpoint rolls the clone back to the checkpoint. Checkpoints taken after it are destroyed.
+func (m *Manager) RollbackCheckpoint(cloneName, checkpointID string) error {
+	return RollbackSnapshot(m.runner, m.config.Pool.Name, m.checkpointName(cloneName, checkpointID))
+}
+
+// newCheckpointID generates the ID of the checkpoint taken at the time.
+func newCheckpointID(takenAt time.Time) string {
+	return checkpointPrefix + takenAt.Format(checkpointIDFormat)
+}
+
+func (m *Manager) checkpointName(cloneName, checkpointID string) string {
+	return fmt.Sprintf("%s/%s@%s", m.config.Pool.Name, cloneName, checkpointID)
+}
+
+// isCheckpoint checks whether the snapshot is a clone checkpoint.
+func isCheckpoint(snapshotName string) bool {
+	return strings.Contains(snapshotName, "@"+checkpointPrefix)
+}
+
+func parseCheckpoints(out string) ([]resources.Checkpoint, error) {
+	const checkpointFields = 3
+
+	checkpoints := []resources.Checkpoint{}
+
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, "\t")
+
+		if len(fields) != checkpointFields || !isCheckpoint(fields[0]) {
+			continue
+		}
+
+		creation, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse creation time of checkpoint %s", fields[0])
+		}
+
+		used, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse used space of checkpoint %s", fields[0])
+		}
+
+		checkpoints = append(checkpoints, resources.Checkpoint{
+			ID:        fields[0][strings.Index(fields[0], "@")+1:],
+			CreatedAt: time.Unix(creation, 0),
+			Used:      used,
+		})
+	}
+
+	return checkpoints, nil
+}
@@ -6,6 +6,7 @@
 package zfs
 
 import (
+	"encoding/json"
 	"fmt"
 	"path"
 	"strconv"
@@ -27,6 +28,15 @@ const (
 	headerOffset        = 1
 	dataStateAtLabel    = "dblab:datastateat"
 	isRoughStateAtLabel = "dblab:isroughdsa"
+	protectedLabel      = "dblab:protected"
+
+	// Snapshot metadata labels.
+	descriptionLabel       = "dblab:description"
+	labelsLabel            = "dblab:labels"
+	retrievalModeLabel     = "dblab:retrievalmode"
+	sourceLabel            = "dblab:source"
+	preprocessingHashLabel = "dblab:preprocessinghash"
+	pgVersionLabel         = "dblab:pgversion"
 
 	// PoolMode defines the zfs filesystem name.
 	PoolMode = "zfs"
@@ -338,6 +348,30 @@ func (m *Manager) DestroySnapshot(snapshotName string) error {
 	return nil
 }
 
+// GetPreSnapshot returns the pre-snapshot the snapshot has been promoted from.
+// Unlike the snapshot, the pre-snapshot keeps the recovery configuration, so archived WAL can be replayed on top of it.
+func (m *Manager) GetPreSnapshot(snapshotName string) (string, error) {
+	const snapshotParts = 2
+
+	splitName := strings.SplitN(snapshotName, "@", snapshotParts)
+	if len(splitName) < snapshotParts {
+		return "", errors.Errorf("invalid snapshot name: %s", snapshotName)
+	}
+
+	out, err := m.runner.Run(buildOriginCommand(splitName[0]), false)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the snapshot origin")
+	}
+
+	preSnapshot := strings.TrimSpace(out)
+
+	if m.config.PreSnapshotSuffix == "" || !strings.HasSuffix(preSnapshot, m.config.PreSnapshotSuffix) {
+		return "", errors.Errorf("snapshot %s has no pre-snapshot", snapshotName)
+	}
+
+	return preSnapshot, nil
+}
+
 // CleanupSnapshots destroys old snapshots considering retention limit and related clones.
 func (m *Manager) CleanupSnapshots(retentionLimit int) ([]string, error) {
 	clonesCmd := fmt.Sprintf("zfs list -S clones -o name,origin -H -r %s", m.config.Pool.Name)
@@ -349,6 +383,14 @@ func (m *Manager) CleanupSnapshots(retentionLimit int) ([]string, error) {
 
 	busySnapshots := m.getBusySnapshotList(clonesOutput)
 
+	protectedSnapshots, err := m.listProtectedSnapshots()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list protected snapshots")
+	}
+
+	// Protected snapshots are kept regardless of the retention limit.
+	busySnapshots = append(busySnapshots, protectedSnapshots...)
+
 	cleanupCmd := fmt.Sprintf(
 		"zfs list -t snapshot -H -o name -s %s -s creation -r %s | grep -v clone | head -n -%d %s"+
 			"| xargs -n1 --no-run-if-empty zfs destroy -R ",
@@ -366,6 +408,7 @@ func (m *Manager) CleanupSnapshots(retentionLimit int) ([]string, error) {
 
 func (m *Manager) getBusySnapshotList(clonesOutput string) []string {
 	systemClones, userClones := make(map[string]string), make(map[string]struct{})
+	preSnapshots := []string{}
 
 	userClonePrefix := m.config.Pool.Name + "/" + util.ClonePrefix
 
@@ -379,6 +422,12 @@ func (m *Manager) getBusySnapshotList(clonesOutput string) []string {
 		if strings.HasPrefix(cloneLine[0], userClonePrefix) {
 			origin := cloneLine[1]
 
+			// Point-in-time clones are created directly from pre-snapshots.
+			if m.config.PreSnapshotSuffix != "" && strings.HasSuffix(origin, m.config.PreSnapshotSuffix) {
+				preSnapshots = append(preSnapshots, origin)
+				continue
+			}
+
 			if idx := strings.Index(origin, "@"); idx != -1 {
 				origin = origin[:idx]
 			}
@@ -391,15 +440,175 @@ func (m *Manager) getBusySnapshotList(clonesOutput string) []string {
 		systemClones[cloneLine[0]] = cloneLine[1]
 	}
 
-	busySnapshots := make([]string, 0, len(userClones))
+	busySnapshots := make([]string, 0, len(userClones)+len(preSnapshots))
+	busySnapshots = append(busySnapshots, preSnapshots...)
 
 	for userClone := range userClones {
-		busySnapshots = append(busySnapshots, systemClones[userClone])
+		// User clones might be created from snapshots of other user clones, which have no system origin.
+		systemSnapshot, ok := systemClones[userClone]
+		if !ok {
+			continue
+		}
+
+		busySnapshots = append(busySnapshots, systemSnapshot)
 	}
 
 	return busySnapshots
 }
 
+// ProtectSnapshot sets or removes the protection of the snapshot against the retention cleanup.
+func (m *Manager) ProtectSnapshot(snapshotName string, protected bool) error {
+	cmd := fmt.Sprintf("zfs inherit %s %s", protectedLabel, snapshotName)
+
+	if protected {
+		cmd = fmt.Sprintf("zfs set %s=%q %s", protectedLabel, "1", snapshotName)
+	}
+
+	if _, err := m.runner.Run(cmd, true); err != nil {
+		return errors.Wrap(err, "failed to change the snapshot protection")
+	}
+
+	return nil
+}
+
+// listProtectedSnapshots returns names of snapshots protected against the retention cleanup.
+func (m *Manager) listProtectedSnapshots() ([]string, error) {
+	cmd := fmt.Sprintf("zfs get -H -o name,value -t snapshot -r %s %s", protectedLabel, m.config.Pool.Name)
+
+	out, err := m.runner.Run(cmd, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the snapshot protection")
+	}
+
+	return parseProtectedSnapshots(out), nil
+}
+
+func parseProtectedSnapshots(output string) []string {
+	protectedSnapshots := []string{}
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+
+		const propertyFieldsNum = 2
+
+		if len(fields) != propertyFieldsNum || fields[1] != "1" {
+			continue
+		}
+
+		protectedSnapshots = append(protectedSnapshots, fields[0])
+	}
+
+	return protectedSnapshots
+}
+
+// SetSnapshotMetadata stores the snapshot metadata as user properties. Empty values are removed.
+func (m *Manager) SetSnapshotMetadata(snapshotName string, metadata resources.SnapshotMetadata) error {
+	labels := ""
+
+	if len(metadata.Labels) > 0 {
+		encodedLabels, err := json.Marshal(metadata.Labels)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode snapshot labels")
+		}
+
+		labels = string(encodedLabels)
+	}
+
+	properties := []struct{ name, value string }{
+		{name: descriptionLabel, value: metadata.Description},
+		{name: labelsLabel, value: labels},
+		{name: retrievalModeLabel, value: metadata.RetrievalMode},
+		{name: sourceLabel, value: metadata.Source},
+		{name: preprocessingHashLabel, value: metadata.PreprocessingHash},
+		{name: pgVersionLabel, value: metadata.PostgresVersion},
+	}
+
+	setValues := make([]string, 0, len(properties))
+
+	for _, property := range properties {
+		if property.value != "" {
+			setValues = append(setValues, property.name+"="+shellQuote(property.value))
+			continue
+		}
+
+		if _, err := m.runner.Run(fmt.Sprintf("zfs inherit %s %s", property.name, snapshotName), true); err != nil {
+			return errors.Wrapf(err, "failed to remove the %s property of snapshot", property.name)
+		}
+	}
+
+	if len(setValues) == 0 {
+		return nil
+	}
+
+	cmd := fmt.Sprintf("zfs set %s %s", strings.Join(setValues, " "), snapshotName)
+
+	if _, err := m.runner.Run(cmd, true); err != nil {
+		return errors.Wrap(err, "failed to set snapshot metadata")
+	}
+
+	return nil
+}
+
+// getSnapshotProperties returns values of user properties of snapshots in the pool.
+func (m *Manager) getSnapshotProperties(properties ...string) (map[string]map[string]string, error) {
+	cmd := fmt.Sprintf("zfs get -H -o name,property,value -t snapshot -r %s %s",
+		strings.Join(properties, ","), m.config.Pool.Name)
+
+	out, err := m.runner.Run(cmd, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get snapshot properties")
+	}
+
+	return parseSnapshotProperties(out), nil
+}
+
+// parseSnapshotProperties parses the tab-separated output of the "zfs get" command. Unset properties are skipped.
+func parseSnapshotProperties(output string) map[string]map[string]string {
+	snapshotProperties := make(map[string]map[string]string)
+
+	for _, line := range strings.Split(output, "\n") {
+		const propertyFieldsNum = 3
+
+		fields := strings.SplitN(line, "\t", propertyFieldsNum)
+
+		if len(fields) != propertyFieldsNum || fields[2] == "" || fields[2] == "-" {
+			continue
+		}
+
+		if snapshotProperties[fields[0]] == nil {
+			snapshotProperties[fields[0]] = make(map[string]string)
+		}
+
+		snapshotProperties[fields[0]][fields[1]] = fields[2]
+	}
+
+	return snapshotProperties
+}
+
+// buildSnapshotMetadata builds the snapshot metadata from user properties.
+func buildSnapshotMetadata(properties map[string]string) resources.SnapshotMetadata {
+	metadata := resources.SnapshotMetadata{
+		Description:       properties[descriptionLabel],
+		RetrievalMode:     properties[retrievalModeLabel],
+		Source:            properties[sourceLabel],
+		PreprocessingHash: properties[preprocessingHashLabel],
+		PostgresVersion:   properties[pgVersionLabel],
+	}
+
+	if labels := properties[labelsLabel]; labels != "" {
+		if err := json.Unmarshal([]byte(labels), &metadata.Labels); err != nil {
+			log.Err("failed to decode snapshot labels:", err)
+		}
+	}
+
+	return metadata
+}
+
+// shellQuote quotes the value to pass it to a shell command as a single argument.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'"'"'`) + "'"
+}
+
 // excludeBusySnapshots excludes snapshots that match a pattern by name.
 // The exclusion logic relies on the fact that snapshots have unique substrings (timestamps).
 func excludeBusySnapshots(busySnapshots []string) string {
@@ -495,6 +704,12 @@ func (m *Manager) GetSnapshots() ([]resources.Snapshot, error) {
 		return nil, fmt.Errorf("failed to list snapshots: %w", err)
 	}
 
+	snapshotProperties, err := m.getSnapshotProperties(protectedLabel, descriptionLabel, labelsLabel, retrievalModeLabel,
+		sourceLabel, preprocessingHashLabel, pgVersionLabel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot metadata: %w", err)
+	}
+
 	snapshots := make([]resources.Snapshot, 0, len(entries))
 
 	for _, entry := range entries {
@@ -503,6 +718,13 @@ func (m *Manager) GetSnapshots() ([]resources.Snapshot, error) {
 			continue
 		}
 
+		// Filter checkpoints, they are private restore points of clones.
+		if isCheckpoint(entry.Name) {
+			continue
+		}
+
+		properties := snapshotProperties[entry.Name]
+
 		snapshot := resources.Snapshot{
 			ID:                entry.Name,
 			CreatedAt:         entry.Creation,
@@ -510,6 +732,8 @@ func (m *Manager) GetSnapshots() ([]resources.Snapshot, error) {
 			Used:              entry.Used,
 			LogicalReferenced: entry.LogicalReferenced,
 			Pool:              m.config.Pool.Name,
+			Protected:         properties[protectedLabel] == "1",
+			Metadata:          buildSnapshotMetadata(properties),
 		}
 
 		snapshots = append(snapshots, snapshot)
@@ -3,6 +3,7 @@ package zfs
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -127,6 +128,8 @@ dblab_pool/clone_pre_20210127133000	dblab_pool@snapshot_20210127133000_pre
 dblab_pool/clone_pre_20210127140000	dblab_pool@snapshot_20210127140000_pre
 dblab_pool/dblab_clone_6000	dblab_pool/clone_pre_20210127133000@snapshot_20210127133008
 dblab_pool/dblab_clone_6001	dblab_pool/clone_pre_20210127123000@snapshot_20210127133008
+dblab_pool/dblab_clone_6002	dblab_pool/dblab_clone_6001@snapshot_20210127150000
+dblab_pool/dblab_clone_6003	dblab_pool@snapshot_20210127105215_pre
 `
 	expected := []string{"dblab_pool@snapshot_20210127133000_pre", "dblab_pool@snapshot_20210127123000_pre"}
 
@@ -134,6 +137,66 @@ dblab_pool/dblab_clone_6001	dblab_pool/clone_pre_20210127123000@snapshot_2021012
 	require.Equal(t, 2, len(list))
 	assert.Contains(t, list, expected[0])
 	assert.Contains(t, list, expected[1])
+
+	m.config.PreSnapshotSuffix = "_pre"
+
+	list = m.getBusySnapshotList(out)
+	require.Equal(t, 3, len(list))
+	assert.Contains(t, list, "dblab_pool@snapshot_20210127105215_pre")
+}
+
+func TestGetPreSnapshot(t *testing.T) {
+	m := Manager{
+		runner: runnerMock{cmdOutput: "dblab_pool@snapshot_20210127105215_pre\n"},
+		config: Config{Pool: &resources.Pool{Name: "dblab_pool"}, PreSnapshotSuffix: "_pre"},
+	}
+
+	preSnapshot, err := m.GetPreSnapshot("dblab_pool/clone_pre_20210127105215@snapshot_20210127105220")
+	require.NoError(t, err)
+	assert.Equal(t, "dblab_pool@snapshot_20210127105215_pre", preSnapshot)
+
+	m.runner = runnerMock{cmdOutput: "-"}
+
+	_, err = m.GetPreSnapshot("dblab_pool@snapshot_20210127105220")
+	assert.EqualError(t, err, "snapshot dblab_pool@snapshot_20210127105220 has no pre-snapshot")
+}
+
+func TestProtectedSnapshotList(t *testing.T) {
+	out := `dblab_pool@snapshot_20210127105215_pre	-
+dblab_pool@snapshot_20210127113000	1
+dblab_pool/dblab_clone_6000@snapshot_20210127133008	1
+dblab_pool@snapshot_20210127120000	0
+`
+	expected := []string{"dblab_pool@snapshot_20210127113000", "dblab_pool/dblab_clone_6000@snapshot_20210127133008"}
+
+	require.Equal(t, expected, parseProtectedSnapshots(out))
+	require.Empty(t, parseProtectedSnapshots(""))
+}
+
+func TestSnapshotMetadata(t *testing.T) {
+	out := "dblab_pool@snapshot_20210127113000\tdblab:description\tpre-release data\n" +
+		"dblab_pool@snapshot_20210127113000\tdblab:labels\t{\"stage\":\"pre-release\"}\n" +
+		"dblab_pool@snapshot_20210127113000\tdblab:retrievalmode\tphysical\n" +
+		"dblab_pool@snapshot_20210127113000\tdblab:pgversion\t14\n" +
+		"dblab_pool@snapshot_20210127120000\tdblab:description\t-\n"
+
+	properties := parseSnapshotProperties(out)
+	require.Len(t, properties, 1)
+
+	expected := resources.SnapshotMetadata{
+		Description:     "pre-release data",
+		Labels:          map[string]string{"stage": "pre-release"},
+		RetrievalMode:   "physical",
+		PostgresVersion: "14",
+	}
+
+	require.Equal(t, expected, buildSnapshotMetadata(properties["dblab_pool@snapshot_20210127113000"]))
+	require.Equal(t, resources.SnapshotMetadata{}, buildSnapshotMetadata(properties["dblab_pool@snapshot_20210127120000"]))
+}
+
+func TestShellQuote(t *testing.T) {
+	require.Equal(t, `'pre-release data'`, shellQuote("pre-release data"))
+	require.Equal(t, `'it'"'"'s $(data)'`, shellQuote("it's $(data)"))
 }
 
 func TestExcludingBusySnapshots(t *testing.T) {
@@ -196,3 +259,28 @@ func TestBuildingCommands(t *testing.T) {
 		require.Equal(t, "zfs get -H -p -o value used testSnapshot", command)
 	})
 }
+
+func TestCheckpointList(t *testing.T) {
+	out := "dblab_pool/dblab_clone_6000@checkpoint_20210127105215\t1611744735\t1048576\n" +
+		"dblab_pool/dblab_clone_6000@snapshot_20210127110000\t1611745200\t2048\n" +
+		"dblab_pool/dblab_clone_6000@checkpoint_20210127113000\t1611747000\t0\n"
+
+	checkpoints, err := parseCheckpoints(out)
+	require.NoError(t, err)
+
+	assert.Equal(t, []resources.Checkpoint{
+		{ID: "checkpoint_20210127105215", CreatedAt: time.Unix(1611744735, 0), Used: 1048576},
+		{ID: "checkpoint_20210127113000", CreatedAt: time.Unix(1611747000, 0), Used: 0},
+	}, checkpoints)
+
+	checkpoints, err = parseCheckpoints("")
+	require.NoError(t, err)
+	assert.Empty(t, checkpoints)
+}
+
+func TestCheckpointID(t *testing.T) {
+	takenAt := time.Date(2021, 1, 27, 10, 52, 15, 120000000, time.UTC)
+
+	assert.Equal(t, "checkpoint_20210127105215.120000", newCheckpointID(takenAt))
+	assert.NotEqual(t, newCheckpointID(takenAt), newCheckpointID(takenAt.Add(time.Millisecond)))
+}
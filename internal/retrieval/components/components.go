@@ -28,3 +28,15 @@ type JobRunner interface {
 	// Run starts a job.
 	Run(ctx context.Context) error
 }
+
+// SnapshotCreator creates snapshots on demand.
+type SnapshotCreator interface {
+	// CreateSnapshot runs a snapshot pipeline and returns the name of the created snapshot.
+	CreateSnapshot() (string, error)
+}
+
+// SourceDescriber describes the source of retrieved data.
+type SourceDescriber interface {
+	// DataSource returns a description of the data source, e.g. a backup name or an RDS instance.
+	DataSource() string
+}
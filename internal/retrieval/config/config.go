@@ -36,4 +36,7 @@ type JobConfig struct {
 	Docker *client.Client
 	Marker *dbmarker.Marker
 	FSPool *resources.Pool
+
+	// Source describes the origin of data retrieved by the preceding jobs.
+	Source string
 }
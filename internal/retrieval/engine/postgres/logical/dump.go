@@ -219,6 +219,15 @@ func (d *DumpJob) Name() string {
 	return d.name
 }
 
+// DataSource returns a description of the dumped database.
+func (d *DumpJob) DataSource() string {
+	if d.Source.Type == sourceTypeRDS && d.Source.RDS != nil {
+		return "RDS instance " + d.Source.RDS.DBInstance
+	}
+
+	return fmt.Sprintf("%s:%d", d.Source.Connection.Host, d.Source.Connection.Port)
+}
+
 // Reload reloads job configuration.
 func (d *DumpJob) Reload(cfg map[string]interface{}) (err error) {
 	if err := options.Unmarshal(cfg, &d.DumpOptions); err != nil {
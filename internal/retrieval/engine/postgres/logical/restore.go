@@ -145,6 +145,11 @@ func (r *RestoreJob) Name() string {
 	return r.name
 }
 
+// DataSource returns a description of the restored dump.
+func (r *RestoreJob) DataSource() string {
+	return "dump " + r.DumpLocation
+}
+
 // Reload reloads job configuration.
 func (r *RestoreJob) Reload(cfg map[string]interface{}) (err error) {
 	if err := options.Unmarshal(cfg, &r.RestoreOptions); err != nil {
@@ -146,6 +146,15 @@ func (r *RestoreJob) Name() string {
 	return r.name
 }
 
+// DataSource returns a description of the restored data source.
+func (r *RestoreJob) DataSource() string {
+	if r.Tool == walgTool {
+		return "wal-g backup " + r.WALG.BackupName
+	}
+
+	return r.Tool
+}
+
 // Reload reloads job configuration.
 func (r *RestoreJob) Reload(cfg map[string]interface{}) (err error) {
 	return options.Unmarshal(cfg, &r.CopyOptions)
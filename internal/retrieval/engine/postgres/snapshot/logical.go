@@ -52,6 +52,7 @@ type LogicalInitial struct {
 	engineProps    global.EngineProps
 	dbMarker       *dbmarker.Marker
 	queryProcessor *queryProcessor
+	dataSource     string
 }
 
 // LogicalOptions describes options for a logical initialization job.
@@ -80,6 +81,7 @@ func NewLogicalInitialJob(cfg config.JobConfig, global *global.Config, enginePro
 		globalCfg:    global,
 		engineProps:  engineProps,
 		dbMarker:     cfg.Marker,
+		dataSource:   cfg.Source,
 		tm:           tm,
 	}
 
@@ -144,7 +146,8 @@ func (s *LogicalInitial) Run(ctx context.Context) error {
 
 	dataStateAt := extractDataStateAt(s.dbMarker)
 
-	if _, err := s.cloneManager.CreateSnapshot("", dataStateAt); err != nil {
+	snapshotName, err := s.cloneManager.CreateSnapshot("", dataStateAt)
+	if err != nil {
 		var existsError *thinclones.SnapshotExistsError
 		if errors.As(err, &existsError) {
 			log.Msg("Skip snapshotting: ", existsError.Error())
@@ -154,6 +157,13 @@ func (s *LogicalInitial) Run(ctx context.Context) error {
 		return errors.Wrap(err, "failed to create a snapshot")
 	}
 
+	metadata := buildSnapshotMetadata(dbmarker.LogicalDataType, s.dataSource, dataDir,
+		s.options.PreprocessingScript, s.options.DataPatching.QueryPreprocessing.QueryPath)
+
+	if err := s.cloneManager.SetSnapshotMetadata(snapshotName, metadata); err != nil {
+		log.Err("Failed to set snapshot metadata:", err)
+	}
+
 	if err := s.markDatabaseData(dataStateAt); err != nil {
 		return errors.Wrap(err, "failed to mark logical data")
 	}
@@ -92,8 +92,9 @@ type PhysicalInitial struct {
 	dockerClient   *client.Client
 	scheduler      *cron.Cron
 	schedulerCtx   context.Context
-	promotionMutex sync.Mutex
+	promotionMutex sync.Mutex // serializes snapshot pipeline runs.
 	queryProcessor *queryProcessor
+	dataSource     string
 	tm             *telemetry.Agent
 }
 
@@ -161,6 +162,7 @@ func NewPhysicalInitialJob(cfg config.JobConfig, global *global.Config, enginePr
 		dbMarker:     cfg.Marker,
 		dbMark:       &dbmarker.Config{DataType: dbmarker.PhysicalDataType},
 		dockerClient: cfg.Docker,
+		dataSource:   cfg.Source,
 		tm:           tm,
 	}
 
@@ -287,10 +289,21 @@ func (p *PhysicalInitial) Run(ctx context.Context) (err error) {
 		return nil
 	}
 
+	_, err = p.run(p.schedulerCtx)
+
+	return err
+}
+
+// CreateSnapshot runs the snapshot pipeline on demand and returns the name of the created snapshot.
+func (p *PhysicalInitial) CreateSnapshot() (string, error) {
+	if p.schedulerCtx == nil {
+		return "", errors.New("the snapshot job has not been started yet")
+	}
+
 	return p.run(p.schedulerCtx)
 }
 
-func (p *PhysicalInitial) run(ctx context.Context) (err error) {
+func (p *PhysicalInitial) run(ctx context.Context) (snapshotName string, err error) {
 	select {
 	case <-ctx.Done():
 		if p.scheduler != nil {
@@ -298,11 +311,15 @@ func (p *PhysicalInitial) run(ctx context.Context) (err error) {
 			p.scheduler.Stop()
 		}
 
-		return nil
+		return "", nil
 
 	default:
 	}
 
+	// Scheduled and on-demand runs must not overlap.
+	p.promotionMutex.Lock()
+	defer p.promotionMutex.Unlock()
+
 	p.dbMark.DataStateAt = extractDataStateAt(p.dbMarker)
 
 	// Snapshot data.
@@ -328,21 +345,21 @@ func (p *PhysicalInitial) run(ctx context.Context) (err error) {
 	}
 
 	// Prepare pre-snapshot.
-	snapshotName, err := p.cloneManager.CreateSnapshot("", preDataStateAt+pre)
+	preSnapshotName, err := p.cloneManager.CreateSnapshot("", preDataStateAt+pre)
 	if err != nil {
-		return errors.Wrap(err, "failed to create snapshot")
+		return "", errors.Wrap(err, "failed to create snapshot")
 	}
 
 	defer func() {
 		if err != nil {
-			if errDestroy := p.cloneManager.DestroySnapshot(snapshotName); errDestroy != nil {
-				log.Err(fmt.Sprintf("Failed to destroy the %q snapshot: %v", snapshotName, errDestroy))
+			if errDestroy := p.cloneManager.DestroySnapshot(preSnapshotName); errDestroy != nil {
+				log.Err(fmt.Sprintf("Failed to destroy the %q snapshot: %v", preSnapshotName, errDestroy))
 			}
 		}
 	}()
 
-	if err := p.cloneManager.CreateClone(cloneName, snapshotName); err != nil {
-		return errors.Wrapf(err, "failed to create \"pre\" clone %s", cloneName)
+	if err := p.cloneManager.CreateClone(cloneName, preSnapshotName); err != nil {
+		return "", errors.Wrapf(err, "failed to create \"pre\" clone %s", cloneName)
 	}
 
 	defer func() {
@@ -356,32 +373,41 @@ func (p *PhysicalInitial) run(ctx context.Context) (err error) {
 	// Promotion.
 	if p.options.Promotion.Enabled {
 		if err := p.promoteInstance(ctx, path.Join(p.fsPool.ClonesDir(), cloneName, p.fsPool.DataSubDir), syState); err != nil {
-			return errors.Wrap(err, "failed to promote instance")
+			return "", errors.Wrap(err, "failed to promote instance")
 		}
 	}
 
 	// Transformation.
 	if p.options.PreprocessingScript != "" {
 		if err := runPreprocessingScript(p.options.PreprocessingScript); err != nil {
-			return err
+			return "", err
 		}
 	}
 
 	// Mark database data.
 	if err := p.markDatabaseData(); err != nil {
-		return errors.Wrap(err, "failed to mark the prepared data")
+		return "", errors.Wrap(err, "failed to mark the prepared data")
 	}
 
 	// Create a snapshot.
-	if _, err := p.cloneManager.CreateSnapshot(cloneName, p.dbMark.DataStateAt); err != nil {
-		return errors.Wrap(err, "failed to create a snapshot")
+	snapshotName, err = p.cloneManager.CreateSnapshot(cloneName, p.dbMark.DataStateAt)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create a snapshot")
+	}
+
+	metadata := buildSnapshotMetadata(dbmarker.PhysicalDataType, p.dataSource,
+		path.Join(p.fsPool.ClonesDir(), cloneName, p.fsPool.DataSubDir),
+		p.options.PreprocessingScript, p.options.Promotion.QueryPreprocessing.QueryPath)
+
+	if err := p.cloneManager.SetSnapshotMetadata(snapshotName, metadata); err != nil {
+		log.Err("Failed to set snapshot metadata:", err)
 	}
 
 	p.updateDataStateAt()
 
 	p.tm.SendEvent(ctx, telemetry.SnapshotCreatedEvent, telemetry.SnapshotCreated{})
 
-	return nil
+	return snapshotName, nil
 }
 
 func (p *PhysicalInitial) checkSyncInstance(ctx context.Context) (string, error) {
@@ -454,7 +480,7 @@ func (p *PhysicalInitial) waitToStopScheduler() {
 
 func (p *PhysicalInitial) runAutoSnapshot(ctx context.Context) func() {
 	return func() {
-		if err := p.run(ctx); err != nil {
+		if _, err := p.run(ctx); err != nil {
 			log.Err(errors.Wrap(err, "failed to take a snapshot automatically"))
 		}
 	}
@@ -473,9 +499,6 @@ func (p *PhysicalInitial) promoteContainerName() string {
 }
 
 func (p *PhysicalInitial) promoteInstance(ctx context.Context, clonePath string, syState syncState) (err error) {
-	p.promotionMutex.Lock()
-	defer p.promotionMutex.Unlock()
-
 	log.Msg("Promote the Postgres instance.")
 
 	cfgManager, err := pgconfig.NewCorrector(clonePath)
@@ -6,10 +6,19 @@
 package snapshot
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
 	"github.com/pkg/errors"
 
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/runners"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/retrieval/dbmarker"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/retrieval/engine/postgres/tools"
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
 )
 
@@ -33,3 +42,69 @@ func runPreprocessingScript(preprocessingScript string) error {
 
 	return nil
 }
+
+// buildSnapshotMetadata collects details of the job that prepared the snapshot data.
+func buildSnapshotMetadata(dataType, source, dataDir, preprocessingScript, queryPath string) resources.SnapshotMetadata {
+	metadata := resources.SnapshotMetadata{
+		RetrievalMode: dataType,
+		Source:        source,
+	}
+
+	pgVersion, err := tools.DetectPGVersion(dataDir)
+	if err != nil {
+		log.Err("Failed to detect the Postgres version of the snapshot data:", err)
+	} else {
+		metadata.PostgresVersion = strconv.FormatFloat(pgVersion, 'f', -1, 64)
+	}
+
+	preprocessingHash, err := hashPreprocessing(preprocessingScript, queryPath)
+	if err != nil {
+		log.Err("Failed to calculate the preprocessing hash:", err)
+	} else {
+		metadata.PreprocessingHash = preprocessingHash
+	}
+
+	return metadata
+}
+
+// hashPreprocessing calculates a hash of the preprocessing script and queries applied to the snapshot data.
+// If the script is a path to a file, the file content is hashed. The query path may be a file or a directory.
+func hashPreprocessing(preprocessingScript, queryPath string) (string, error) {
+	if preprocessingScript == "" && queryPath == "" {
+		return "", nil
+	}
+
+	hash := sha256.New()
+
+	if preprocessingScript != "" {
+		script, err := os.ReadFile(preprocessingScript)
+		if err != nil {
+			// The script is a command rather than a file.
+			script = []byte(preprocessingScript)
+		}
+
+		hash.Write(script)
+	}
+
+	if queryPath != "" {
+		// WalkDir visits files in lexical order, so the hash does not depend on the file system.
+		if err := filepath.WalkDir(queryPath, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+
+			query, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			hash.Write(query)
+
+			return nil
+		}); err != nil {
+			return "", errors.Wrap(err, "failed to read preprocessing queries")
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,36 @@
+/*
+2022 © Postgres.ai
+*/
+
+package snapshot
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreprocessingHash(t *testing.T) {
+	hash, err := hashPreprocessing("", "")
+	require.NoError(t, err)
+	require.Empty(t, hash)
+
+	queryDir := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(queryDir, "01_anonymize.sql"), []byte("update users set email = null;"), 0600))
+
+	initialHash, err := hashPreprocessing("bash ./preprocess.sh", queryDir)
+	require.NoError(t, err)
+	require.Len(t, initialHash, 64)
+
+	sameHash, err := hashPreprocessing("bash ./preprocess.sh", queryDir)
+	require.NoError(t, err)
+	require.Equal(t, initialHash, sameHash)
+
+	require.NoError(t, os.WriteFile(path.Join(queryDir, "02_cleanup.sql"), []byte("truncate logs;"), 0600))
+
+	changedHash, err := hashPreprocessing("bash ./preprocess.sh", queryDir)
+	require.NoError(t, err)
+	require.NotEqual(t, initialHash, changedHash)
+}
@@ -18,7 +18,10 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/pkg/errors"
 	"github.com/robfig/cron/v3"
+	"github.com/rs/xid"
 
+	"gitlab.com/postgres-ai/database-lab/v3/internal/audit"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/events"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/pool"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/runners"
@@ -49,6 +52,8 @@ type Retrieval struct {
 	docker        *client.Client
 	poolManager   *pool.Manager
 	tm            *telemetry.Agent
+	broker        *events.Broker
+	auditLog      *audit.Logger
 	runner        runners.Runner
 	jobs          []components.JobRunner
 	retrieveMutex sync.Mutex
@@ -64,7 +69,7 @@ type Scheduler struct {
 
 // New creates a new data retrieval.
 func New(cfg *dblabCfg.Config, engineProps global.EngineProps, docker *client.Client, pm *pool.Manager, tm *telemetry.Agent,
-	runner runners.Runner) *Retrieval {
+	broker *events.Broker, auditLog *audit.Logger, runner runners.Runner) *Retrieval {
 	r := &Retrieval{
 		cfg:         &cfg.Retrieval,
 		global:      &cfg.Global,
@@ -72,6 +77,8 @@ func New(cfg *dblabCfg.Config, engineProps global.EngineProps, docker *client.Cl
 		docker:      docker,
 		poolManager: pm,
 		tm:          tm,
+		broker:      broker,
+		auditLog:    auditLog,
 		runner:      runner,
 		jobSpecs:    make(map[string]config.JobSpec, len(cfg.Retrieval.Jobs)),
 		State: State{
@@ -131,7 +138,7 @@ func (r *Retrieval) Run(ctx context.Context) error {
 	if err != nil {
 		var skipError *SkipRefreshingError
 		if errors.As(err, &skipError) {
-			r.State.Status = models.Finished
+			r.setStatus(models.Finished)
 
 			log.Msg("Continue without performing a full refresh:", skipError.Error())
 			r.setupScheduler(ctx)
@@ -143,9 +150,8 @@ func (r *Retrieval) Run(ctx context.Context) error {
 			Level:   models.RefreshFailed,
 			Message: "Pool to perform data refresh not found",
 		}
-		r.State.Status = models.Failed
-		r.State.addAlert(alert)
-		r.tm.SendEvent(ctx, telemetry.AlertEvent, alert)
+		r.setStatus(models.Failed)
+		r.raiseAlert(ctx, alert)
 
 		return fmt.Errorf("failed to choose pool to refresh: %w", err)
 	}
@@ -155,8 +161,7 @@ func (r *Retrieval) Run(ctx context.Context) error {
 	if err := r.run(runCtx, fsManager); err != nil {
 		alert := telemetry.Alert{Level: models.RefreshFailed,
 			Message: fmt.Sprintf("Failed to perform initial data retrieving: %s", r.State.Mode)}
-		r.State.addAlert(alert)
-		r.tm.SendEvent(ctx, telemetry.AlertEvent, alert)
+		r.raiseAlert(ctx, alert)
 
 		return err
 	}
@@ -223,18 +228,20 @@ func (r *Retrieval) run(ctx context.Context, fsm pool.FSManager) (err error) {
 		fsm.Pool().SetStatus(resources.RefreshingPool)
 
 		r.retrieveMutex.Lock()
-		r.State.Status = models.Refreshing
+		r.setStatus(models.Refreshing)
 		r.State.LastRefresh = pointer.ToTimeOrNil(time.Now().Truncate(time.Second))
 
 		defer func() {
-			r.State.Status = models.Finished
+			status := models.Finished
 
 			if err != nil {
-				r.State.Status = models.Failed
+				status = models.Failed
 
 				fsm.Pool().SetStatus(resources.EmptyPool)
 			}
 
+			r.setStatus(status)
+
 			r.retrieveMutex.Unlock()
 		}()
 
@@ -279,6 +286,8 @@ func (r *Retrieval) parseJobs(fsm pool.FSManager) error {
 
 	r.jobs = make([]components.JobRunner, 0, len(r.cfg.Jobs))
 
+	var dataSource string
+
 	for _, jobName := range r.cfg.Jobs {
 		jobSpec, ok := r.jobSpecs[jobName]
 		if !ok {
@@ -290,6 +299,7 @@ func (r *Retrieval) parseJobs(fsm pool.FSManager) error {
 			Docker: r.docker,
 			Marker: dbMarker,
 			FSPool: fsm.Pool(),
+			Source: dataSource,
 		}
 
 		job, err := retrievalRunner.BuildJob(jobCfg)
@@ -297,6 +307,11 @@ func (r *Retrieval) parseJobs(fsm pool.FSManager) error {
 			return errors.Wrap(err, "failed to build job")
 		}
 
+		// The first job retrieving data defines the data source, e.g. a dump job precedes a restore job.
+		if describer, ok := job.(components.SourceDescriber); ok && dataSource == "" {
+			dataSource = describer.DataSource()
+		}
+
 		r.addJob(job)
 	}
 
@@ -403,12 +418,37 @@ func (r *Retrieval) setupScheduler(ctx context.Context) {
 	r.Scheduler.Cron.Start()
 }
 
+// setStatus changes the retrieval status, notifies subscribers, and records refreshes to the audit log.
+func (r *Retrieval) setStatus(status models.RetrievalStatus) {
+	r.State.Status = status
+
+	if entry, ok := audit.RefreshEntry(status); ok {
+		r.auditLog.Record(entry)
+	}
+
+	r.broker.Publish(models.Event{
+		Type:   models.EventRetrievalStatus,
+		Status: string(status),
+	})
+}
+
+// raiseAlert registers the alert and reports it to telemetry and subscribers.
+func (r *Retrieval) raiseAlert(ctx context.Context, alert telemetry.Alert) {
+	r.State.addAlert(alert)
+	r.tm.SendEvent(ctx, telemetry.AlertEvent, alert)
+
+	r.broker.Publish(models.Event{
+		Type:    models.EventAlert,
+		Status:  string(alert.Level),
+		Message: alert.Message,
+	})
+}
+
 func (r *Retrieval) refreshFunc(ctx context.Context) func() {
 	return func() {
 		if err := r.fullRefresh(ctx); err != nil {
 			alert := telemetry.Alert{Level: models.RefreshFailed, Message: "Failed to run full-refresh"}
-			r.State.addAlert(alert)
-			r.tm.SendEvent(ctx, telemetry.AlertEvent, alert)
+			r.raiseAlert(ctx, alert)
 			log.Err(alert.Message, err)
 		}
 	}
@@ -421,8 +461,7 @@ func (r *Retrieval) fullRefresh(ctx context.Context) error {
 			Level:   models.RefreshSkipped,
 			Message: "The data refresh is currently in progress. Skip a new data refresh iteration",
 		}
-		r.State.addAlert(alert)
-		r.tm.SendEvent(ctx, telemetry.AlertEvent, alert)
+		r.raiseAlert(ctx, alert)
 		log.Msg(alert.Message)
 
 		return nil
@@ -442,8 +481,7 @@ func (r *Retrieval) fullRefresh(ctx context.Context) error {
 			Level:   models.RefreshSkipped,
 			Message: "Pool to perform full refresh not found. Skip refreshing",
 		}
-		r.State.addAlert(alert)
-		r.tm.SendEvent(ctx, telemetry.AlertEvent, alert)
+		r.raiseAlert(ctx, alert)
 		log.Msg(alert.Message)
 
 		return nil
@@ -477,6 +515,47 @@ func (r *Retrieval) fullRefresh(ctx context.Context) error {
 	return nil
 }
 
+// CreateSnapshot starts an on-demand snapshot job. The job progress is reported in the retrieval state.
+func (r *Retrieval) CreateSnapshot() (*models.SnapshotJob, error) {
+	if r.State.Status == models.Refreshing {
+		return nil, models.New(models.ErrCodeBadRequest, "data retrieval is in progress, try again later")
+	}
+
+	var snapshotCreator components.SnapshotCreator
+
+	for _, job := range r.jobs {
+		if creator, ok := job.(components.SnapshotCreator); ok {
+			snapshotCreator = creator
+			break
+		}
+	}
+
+	if snapshotCreator == nil {
+		return nil, models.New(models.ErrCodeBadRequest, "on-demand snapshots are available only with the physicalSnapshot job")
+	}
+
+	snapshotJob := models.SnapshotJob{
+		ID:        xid.New().String(),
+		Status:    models.SnapshotJobRunning,
+		StartedAt: time.Now().Truncate(time.Second),
+	}
+
+	r.State.addSnapshotJob(snapshotJob)
+
+	go func() {
+		log.Msg("Start an on-demand snapshot job:", snapshotJob.ID)
+
+		snapshotName, err := snapshotCreator.CreateSnapshot()
+		if err != nil {
+			log.Err("Failed to create a snapshot on demand:", err)
+		}
+
+		r.State.finishSnapshotJob(snapshotJob.ID, snapshotName, err)
+	}()
+
+	return &snapshotJob, nil
+}
+
 // Stop stops a retrieval service.
 func (r *Retrieval) Stop() {
 	r.stopScheduler()
@@ -491,7 +570,7 @@ func (r *Retrieval) stopScheduler() {
 
 // IsValidConfig checks if the retrieval configuration is valid.
 func IsValidConfig(cfg *dblabCfg.Config) error {
-	rs := New(cfg, global.EngineProps{}, nil, nil, nil, nil)
+	rs := New(cfg, global.EngineProps{}, nil, nil, nil, nil, nil, nil)
 
 	cm, err := pool.NewManager(nil, pool.ManagerConfig{
 		Pool: &resources.Pool{
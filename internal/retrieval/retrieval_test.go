@@ -1,11 +1,16 @@
 package retrieval
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"gitlab.com/postgres-ai/database-lab/v3/internal/retrieval/components"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/retrieval/config"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
 )
 
 func TestParallelJobSpecs(t *testing.T) {
@@ -150,3 +155,38 @@ func TestLogicalJobs(t *testing.T) {
 		assert.Equal(t, tc.hasLogical, hasLogicalJob)
 	}
 }
+
+type mockSnapshotJob struct {
+	snapshotName string
+}
+
+func (m *mockSnapshotJob) Name() string                          { return "physicalSnapshot" }
+func (m *mockSnapshotJob) Reload(_ map[string]interface{}) error { return nil }
+func (m *mockSnapshotJob) Run(_ context.Context) error           { return nil }
+func (m *mockSnapshotJob) CreateSnapshot() (string, error)       { return m.snapshotName, nil }
+
+func TestCreateSnapshotOnDemand(t *testing.T) {
+	r := &Retrieval{}
+
+	_, err := r.CreateSnapshot()
+	require.Error(t, err)
+
+	r.jobs = []components.JobRunner{&mockSnapshotJob{snapshotName: "dblab_pool@snapshot_20220101000000"}}
+	r.State.Status = models.Refreshing
+
+	_, err = r.CreateSnapshot()
+	require.Error(t, err)
+
+	r.State.Status = models.Finished
+
+	snapshotJob, err := r.CreateSnapshot()
+	require.NoError(t, err)
+	assert.Equal(t, models.SnapshotJobRunning, snapshotJob.Status)
+
+	require.Eventually(t, func() bool {
+		jobs := r.State.SnapshotJobs()
+		return len(jobs) == 1 && jobs[0].Status == models.SnapshotJobFinished
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "dblab_pool@snapshot_20220101000000", r.State.SnapshotJobs()[0].SnapshotID)
+}
@@ -12,13 +12,17 @@ import (
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
 )
 
+// maxSnapshotJobs defines the number of on-demand snapshot jobs kept in the state.
+const maxSnapshotJobs = 10
+
 // State contains state of retrieval service.
 type State struct {
-	Mode        models.RetrievalMode
-	Status      models.RetrievalStatus
-	LastRefresh *time.Time
-	mu          sync.Mutex
-	alerts      map[models.AlertType]models.Alert
+	Mode         models.RetrievalMode
+	Status       models.RetrievalStatus
+	LastRefresh  *time.Time
+	mu           sync.Mutex
+	alerts       map[models.AlertType]models.Alert
+	snapshotJobs []*models.SnapshotJob
 }
 
 // Alerts returns all registered retrieval alerts.
@@ -60,3 +64,55 @@ func (s *State) cleanAlerts() {
 	s.alerts = make(map[models.AlertType]models.Alert)
 	s.mu.Unlock()
 }
+
+// SnapshotJobs returns on-demand snapshot jobs starting with the most recent one.
+func (s *State) SnapshotJobs() []models.SnapshotJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]models.SnapshotJob, 0, len(s.snapshotJobs))
+
+	for i := len(s.snapshotJobs) - 1; i >= 0; i-- {
+		jobs = append(jobs, *s.snapshotJobs[i])
+	}
+
+	return jobs
+}
+
+func (s *State) addSnapshotJob(job models.SnapshotJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.snapshotJobs = append(s.snapshotJobs, &job)
+
+	if len(s.snapshotJobs) > maxSnapshotJobs {
+		s.snapshotJobs = s.snapshotJobs[len(s.snapshotJobs)-maxSnapshotJobs:]
+	}
+}
+
+func (s *State) finishSnapshotJob(jobID, snapshotID string, jobErr error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, job := range s.snapshotJobs {
+		if job.ID != jobID {
+			continue
+		}
+
+		finishedAt := time.Now().Truncate(time.Second)
+		job.FinishedAt = &finishedAt
+		job.SnapshotID = snapshotID
+		job.Status = models.SnapshotJobFinished
+
+		switch {
+		case jobErr != nil:
+			job.Status = models.SnapshotJobFailed
+			job.Message = jobErr.Error()
+
+		case snapshotID == "":
+			job.Message = "snapshot has been skipped"
+		}
+
+		return
+	}
+}
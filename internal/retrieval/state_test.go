@@ -5,6 +5,8 @@
 package retrieval
 
 import (
+	"errors"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -44,3 +46,30 @@ func TestState(t *testing.T) {
 
 	assert.Equal(t, 0, len(state.alerts))
 }
+
+func TestSnapshotJobs(t *testing.T) {
+	state := State{}
+
+	for i := 0; i < maxSnapshotJobs+2; i++ {
+		state.addSnapshotJob(models.SnapshotJob{ID: strconv.Itoa(i), Status: models.SnapshotJobRunning})
+	}
+
+	jobs := state.SnapshotJobs()
+	assert.Equal(t, maxSnapshotJobs, len(jobs))
+	assert.Equal(t, strconv.Itoa(maxSnapshotJobs+1), jobs[0].ID)
+	assert.Equal(t, "2", jobs[len(jobs)-1].ID)
+
+	state.finishSnapshotJob("11", "dblab_pool@snapshot_20220101000000", nil)
+	state.finishSnapshotJob("10", "", errors.New("failed to promote instance"))
+	state.finishSnapshotJob("9", "", nil)
+
+	jobs = state.SnapshotJobs()
+	assert.Equal(t, models.SnapshotJobFinished, jobs[0].Status)
+	assert.Equal(t, "dblab_pool@snapshot_20220101000000", jobs[0].SnapshotID)
+	assert.NotNil(t, jobs[0].FinishedAt)
+	assert.Equal(t, models.SnapshotJobFailed, jobs[1].Status)
+	assert.Equal(t, "failed to promote instance", jobs[1].Message)
+	assert.Equal(t, models.SnapshotJobFinished, jobs[2].Status)
+	assert.Equal(t, "snapshot has been skipped", jobs[2].Message)
+	assert.Equal(t, models.SnapshotJobRunning, jobs[3].Status)
+}
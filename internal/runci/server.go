@@ -16,6 +16,7 @@ import (
 	"gitlab.com/postgres-ai/database-lab/v3/internal/runci/source"
 
 	"gitlab.com/postgres-ai/database-lab/v3/internal/platform"
+	srvCfg "gitlab.com/postgres-ai/database-lab/v3/internal/srv/config"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/srv/mw"
 
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi"
@@ -54,11 +55,14 @@ func NewServer(cfg *Config, dle *dblabapi.Client, platform *platform.Service, co
 func (s *Server) Run() error {
 	r := mux.NewRouter().StrictSlash(true)
 
-	authMW := mw.NewAuth(s.config.App.VerificationToken, s.platform)
+	authMW, err := mw.NewAuth(srvCfg.Config{VerificationToken: s.config.App.VerificationToken}, s.platform)
+	if err != nil {
+		return fmt.Errorf("failed to initialize authorization: %w", err)
+	}
 
-	r.HandleFunc("/migration/run", authMW.Authorized(s.runMigration)).Methods(http.MethodPost)
-	r.HandleFunc("/artifact/download", authMW.Authorized(s.downloadArtifact)).Methods(http.MethodGet)
-	r.HandleFunc("/artifact/stop", authMW.Authorized(s.destroyClone)).Methods(http.MethodGet)
+	r.HandleFunc("/migration/run", authMW.Authorized(mw.ScopeCloneCreate, s.runMigration)).Methods(http.MethodPost)
+	r.HandleFunc("/artifact/download", authMW.Authorized(mw.ScopeReadOnly, s.downloadArtifact)).Methods(http.MethodGet)
+	r.HandleFunc("/artifact/stop", authMW.Authorized(mw.ScopeCloneCreate, s.destroyClone)).Methods(http.MethodGet)
 	r.HandleFunc("/healthz", s.healthCheck).Methods(http.MethodGet)
 
 	addr := fmt.Sprintf("%s:%d", s.config.App.Host, s.config.App.Port)
@@ -0,0 +1,51 @@
+/*
+2022 © Postgres.ai
+*/
+
+package srv
+
+import (
+	"fmt"
+	"net/http"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/srv/mw"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+// authorizeClone checks whether the user of the request is allowed to manage the clone.
+// Admins manage any clone, other users manage only their own clones.
+func (s *Server) authorizeClone(r *http.Request, cloneID string) *models.Error {
+	identity := mw.IdentityFromContext(r.Context())
+	if identity.Admin {
+		return nil
+	}
+
+	clone, err := s.Cloning.GetClone(cloneID)
+	if err != nil {
+		return models.New(models.ErrCodeNotFound, "clone not found")
+	}
+
+	if !identity.CanManage(clone.Owner) {
+		return models.New(models.ErrCodeForbidden, "only the clone owner or admins are allowed to manage the clone")
+	}
+
+	return nil
+}
+
+// authorizeDependentClones checks whether the user of the request is allowed to manage all clones
+// destroyed along with the snapshot.
+func (s *Server) authorizeDependentClones(r *http.Request, snapshotID string) *models.Error {
+	identity := mw.IdentityFromContext(r.Context())
+	if identity.Admin {
+		return nil
+	}
+
+	for _, clone := range s.Cloning.GetSnapshotDependentClones(snapshotID) {
+		if !identity.CanManage(clone.Owner) {
+			return models.New(models.ErrCodeForbidden,
+				fmt.Sprintf("only the clone owner or admins are allowed to destroy dependent clone %s", clone.ID))
+		}
+	}
+
+	return nil
+}
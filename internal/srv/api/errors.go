@@ -80,9 +80,15 @@ func toStatusCode(err models.Error) int {
 	case models.ErrCodeUnauthorized:
 		return http.StatusUnauthorized
 
+	case models.ErrCodeForbidden:
+		return http.StatusForbidden
+
 	case models.ErrCodeNotFound:
 		return http.StatusNotFound
 
+	case models.ErrCodeQuotaExceeded:
+		return http.StatusTooManyRequests
+
 	case models.ErrCodeInternal:
 		return http.StatusInternalServerError
 
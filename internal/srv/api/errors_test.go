@@ -25,10 +25,18 @@ func TestErrorCodeStatuses(t *testing.T) {
 			error: "UNAUTHORIZED",
 			code:  401,
 		},
+		{
+			error: "FORBIDDEN",
+			code:  403,
+		},
 		{
 			error: "NOT_FOUND",
 			code:  404,
 		},
+		{
+			error: "QUOTA_EXCEEDED",
+			code:  429,
+		},
 		{
 			error: "INTERNAL_ERROR",
 			code:  500,
@@ -5,9 +5,47 @@
 // Package config contains configuration options of HTTP server.
 package config
 
+import (
+	"time"
+)
+
 // Config provides configuration for an HTTP server of the Database Lab.
 type Config struct {
-	VerificationToken string `yaml:"verificationToken"`
-	Host              string `yaml:"host"`
-	Port              uint   `yaml:"port"`
+	VerificationToken string     `yaml:"verificationToken"`
+	Host              string     `yaml:"host"`
+	Port              uint       `yaml:"port"`
+	Tokens            []APIToken `yaml:"tokens"`
+	JWT               JWTConfig  `yaml:"jwt"`
+}
+
+// APIToken defines an API token with limited permissions.
+type APIToken struct {
+	// Name identifies the token holder. Clones created with the token are owned by "token:<name>".
+	Name   string   `yaml:"name"`
+	Token  string   `yaml:"token"`
+	Scopes []string `yaml:"scopes"`
+	// ExpiresAt defines the moment after which the token is rejected. The token never expires if it is not set.
+	ExpiresAt *time.Time `yaml:"expiresAt"`
+	Revoked   bool       `yaml:"revoked"`
+}
+
+// JWTConfig defines the verification of JWT bearer tokens issued by an OpenID Connect provider.
+type JWTConfig struct {
+	// JWKSURL defines the URL of the JSON Web Key Set used to verify token signatures.
+	JWKSURL string `yaml:"jwksURL"`
+	// JWKSFile defines the path to a local JSON Web Key Set file. Only one of JWKSURL and JWKSFile can be set.
+	JWKSFile string `yaml:"jwksFile"`
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+	// UsernameClaim defines the claim identifying the user. Default: "sub".
+	UsernameClaim string `yaml:"usernameClaim"`
+	// RolesClaim defines the claim listing roles of the user. Nested claims are separated by dots. Default: "roles".
+	RolesClaim string `yaml:"rolesClaim"`
+	// Roles maps roles of users to scopes of the API.
+	Roles map[string][]string `yaml:"roles"`
+}
+
+// IsEnabled checks whether JWT bearer tokens are accepted.
+func (c JWTConfig) IsEnabled() bool {
+	return c.JWKSURL != "" || c.JWKSFile != ""
 }
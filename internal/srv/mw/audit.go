@@ -0,0 +1,157 @@
+/*
+2022 © Postgres.ai
+*/
+
+package mw
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/xid"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/audit"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+const (
+	// RequestIDHeader defines a header passing the request ID. The ID is generated if the header is not set.
+	RequestIDHeader = "X-Request-ID"
+
+	// OperationIDHeader defines the response header containing the ID of the operation started by the request.
+	OperationIDHeader = "Operation-ID"
+
+	forwardedForHeader = "X-Forwarded-For"
+
+	// maxErrorBodySize limits the size of error responses kept to describe failed actions.
+	maxErrorBodySize = 4096
+)
+
+// Audit defines a middleware recording API calls to the audit log.
+type Audit struct {
+	logger *audit.Logger
+}
+
+// NewAudit creates a new Audit middleware.
+func NewAudit(logger *audit.Logger) *Audit {
+	return &Audit{logger: logger}
+}
+
+// auditRecord collects details of the audited request known only to handlers and inner middlewares.
+type auditRecord struct {
+	user   string
+	target string
+}
+
+type auditRecordKey struct{}
+
+// SetAuditTarget sets the target of the audited request, e.g. the ID of a created clone.
+// By default, the target is taken from the "id" route variable.
+func SetAuditTarget(ctx context.Context, target string) {
+	if record, ok := ctx.Value(auditRecordKey{}).(*auditRecord); ok {
+		record.target = target
+	}
+}
+
+// setAuditUser sets the user of the audited request.
+func setAuditUser(ctx context.Context, user string) {
+	if record, ok := ctx.Value(auditRecordKey{}).(*auditRecord); ok {
+		record.user = user
+	}
+}
+
+// Audited records the call of the handler to the audit log.
+// The middleware wraps the authorization middleware to record rejected calls as well.
+func (a *Audit) Audited(action models.AuditAction, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = xid.New().String()
+		}
+
+		w.Header().Set(RequestIDHeader, requestID)
+
+		record := &auditRecord{target: mux.Vars(r)["id"]}
+		recorder := &auditResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+		h(recorder, r.WithContext(context.WithValue(r.Context(), auditRecordKey{}, record)))
+
+		entry := models.AuditEntry{
+			RequestID:    requestID,
+			User:         record.user,
+			SourceIP:     sourceIP(r),
+			ForwardedFor: r.Header.Get(forwardedForHeader),
+			Action:       action,
+			Method:       r.Method,
+			Path:         r.URL.Path,
+			Target:       record.target,
+			OperationID:  recorder.Header().Get(OperationIDHeader),
+			Result:       auditResult(recorder.statusCode),
+			StatusCode:   recorder.statusCode,
+		}
+
+		if recorder.statusCode >= http.StatusBadRequest {
+			var responseErr models.Error
+			if err := json.Unmarshal(recorder.errorBody.Bytes(), &responseErr); err == nil {
+				entry.Message = responseErr.Message
+			}
+		}
+
+		a.logger.Record(entry)
+	}
+}
+
+// auditResult converts the status code of the response to the result of the audited action.
+func auditResult(statusCode int) models.AuditResult {
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return models.AuditDenied
+
+	case statusCode >= http.StatusBadRequest:
+		return models.AuditFailure
+
+	default:
+		return models.AuditSuccess
+	}
+}
+
+// sourceIP returns the IP address of the client connection.
+func sourceIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// auditResponseWriter keeps the status code and the error body of the response.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	errorBody   bytes.Buffer
+}
+
+func (w *auditResponseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.statusCode = statusCode
+		w.wroteHeader = true
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *auditResponseWriter) Write(data []byte) (int, error) {
+	w.wroteHeader = true
+
+	if w.statusCode >= http.StatusBadRequest && w.errorBody.Len() < maxErrorBodySize {
+		w.errorBody.Write(data)
+	}
+
+	return w.ResponseWriter.Write(data)
+}
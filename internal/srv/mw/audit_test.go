@@ -0,0 +1,98 @@
+/*
+2022 © Postgres.ai
+*/
+
+package mw
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/audit"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/srv/api"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/srv/config"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+func TestAudited(t *testing.T) {
+	logger, err := audit.New(audit.Config{Enabled: true, Path: path.Join(t.TempDir(), "audit.log")})
+	require.NoError(t, err)
+
+	defer func() { _ = logger.Close() }()
+
+	authMW, err := NewAuth(config.Config{
+		VerificationToken: testVerificationToken,
+		Tokens:            []config.APIToken{{Name: "bi", Token: "bi-token", Scopes: []string{"read-only"}}},
+	}, nil)
+	require.NoError(t, err)
+
+	auditMW := NewAudit(logger)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/clone", auditMW.Audited(models.AuditCloneCreate, authMW.Authorized(ScopeCloneCreate,
+		func(w http.ResponseWriter, r *http.Request) {
+			SetAuditTarget(r.Context(), "clone1")
+			w.Header().Set(OperationIDHeader, "operation1")
+			w.WriteHeader(http.StatusCreated)
+		}))).Methods(http.MethodPost)
+	r.HandleFunc("/clone/{id}", auditMW.Audited(models.AuditCloneDestroy, authMW.Authorized(ScopeCloneCreate,
+		func(w http.ResponseWriter, r *http.Request) {
+			api.SendError(w, r, *models.New(models.ErrCodeNotFound, "clone not found"))
+		}))).Methods(http.MethodDelete)
+
+	requests := []struct {
+		method string
+		url    string
+		token  string
+	}{
+		{method: http.MethodPost, url: "/clone", token: testVerificationToken},
+		{method: http.MethodPost, url: "/clone", token: "bi-token"},
+		{method: http.MethodPost, url: "/clone", token: "WrongToken"},
+		{method: http.MethodDelete, url: "/clone/clone2", token: testVerificationToken},
+	}
+
+	for _, request := range requests {
+		req := httptest.NewRequest(request.method, request.url, nil)
+		req.RemoteAddr = "192.0.2.10:51234"
+		req.Header.Set(VerificationTokenHeader, request.token)
+		req.Header.Set(RequestIDHeader, "request-"+request.token)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, "request-"+request.token, w.Header().Get(RequestIDHeader))
+	}
+
+	entries, err := logger.Entries(audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+
+	notFound, unauthorized, forbidden, created := entries[0], entries[1], entries[2], entries[3]
+
+	assert.Equal(t, models.AuditCloneCreate, created.Action)
+	assert.Equal(t, AdminIdentity, created.User)
+	assert.Equal(t, "clone1", created.Target)
+	assert.Equal(t, "operation1", created.OperationID)
+	assert.Equal(t, "192.0.2.10", created.SourceIP)
+	assert.Equal(t, "request-"+testVerificationToken, created.RequestID)
+	assert.Equal(t, models.AuditSuccess, created.Result)
+	assert.Equal(t, http.StatusCreated, created.StatusCode)
+
+	assert.Equal(t, "token:bi", forbidden.User)
+	assert.Equal(t, models.AuditDenied, forbidden.Result)
+	assert.Equal(t, http.StatusForbidden, forbidden.StatusCode)
+
+	assert.Empty(t, unauthorized.User)
+	assert.Equal(t, models.AuditDenied, unauthorized.Result)
+
+	assert.Equal(t, models.AuditCloneDestroy, notFound.Action)
+	assert.Equal(t, "clone2", notFound.Target)
+	assert.Equal(t, models.AuditFailure, notFound.Result)
+	assert.Equal(t, "clone not found", notFound.Message)
+}
@@ -8,52 +8,151 @@ package mw
 import (
 	"context"
 	"crypto/subtle"
+	"fmt"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"gitlab.com/postgres-ai/database-lab/v3/internal/platform"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/srv/api"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/srv/config"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
 )
 
-// VerificationTokenHeader defines a verification token name that should be passed in request headers.
-const VerificationTokenHeader = "Verification-Token"
+const (
+	// VerificationTokenHeader defines a verification token name that should be passed in request headers.
+	VerificationTokenHeader = "Verification-Token"
+
+	// AuthorizationHeader defines a header passing JWT bearer tokens.
+	AuthorizationHeader = "Authorization"
+
+	bearerPrefix = "Bearer "
+)
 
 // Auth defines an authorization middleware of the Database Lab HTTP server.
 type Auth struct {
+	mu                    sync.RWMutex
 	verificationToken     string
+	tokens                *tokenStore
+	jwt                   *jwtValidator
 	personalTokenVerifier platform.PersonalTokenVerifier
 }
 
 // NewAuth creates a new Auth middleware.
-func NewAuth(verificationToken string, personalTokenVerifier platform.PersonalTokenVerifier) *Auth {
-	return &Auth{verificationToken: verificationToken, personalTokenVerifier: personalTokenVerifier}
+func NewAuth(cfg config.Config, personalTokenVerifier platform.PersonalTokenVerifier) (*Auth, error) {
+	a := &Auth{personalTokenVerifier: personalTokenVerifier}
+
+	if err := a.Reload(cfg); err != nil {
+		return nil, err
+	}
+
+	return a, nil
 }
 
-// Authorized checks if the user has permission to access.
-func (a *Auth) Authorized(h http.HandlerFunc) http.HandlerFunc {
+// Reload reloads the verification token, API tokens, and the verification of JWT bearer tokens.
+func (a *Auth) Reload(cfg config.Config) error {
+	tokens, err := newTokenStore(cfg.Tokens)
+	if err != nil {
+		return err
+	}
+
+	jwt, err := newJWTValidator(cfg.JWT)
+	if err != nil {
+		return fmt.Errorf("failed to initialize JWT verification: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.verificationToken = cfg.VerificationToken
+	a.tokens = tokens
+	a.jwt = jwt
+
+	return nil
+}
+
+// Authorized checks if the user has permission to access routes of the scope.
+// The identity of the user is passed in the request context.
+func (a *Auth) Authorized(scope Scope, h http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		token := r.Header.Get(VerificationTokenHeader)
-		if !a.isAccessAllowed(r.Context(), token) {
+		var (
+			identity Identity
+			ok       bool
+		)
+
+		if authorization := r.Header.Get(AuthorizationHeader); strings.HasPrefix(authorization, bearerPrefix) {
+			identity, ok = a.authenticateBearer(r.Context(), strings.TrimPrefix(authorization, bearerPrefix))
+		} else {
+			identity, ok = a.authenticate(r.Context(), r.Header.Get(VerificationTokenHeader))
+		}
+
+		if !ok {
 			api.SendUnauthorizedError(w, r)
 			return
 		}
 
-		h(w, r)
+		setAuditUser(r.Context(), identity.Name)
+
+		if !identity.HasScope(scope) {
+			api.SendError(w, r, models.Error{
+				Code:    models.ErrCodeForbidden,
+				Message: fmt.Sprintf("the token does not have the %q scope", scope),
+			})
+
+			return
+		}
+
+		h(w, r.WithContext(WithIdentity(r.Context(), identity)))
 	}
 }
 
-func (a *Auth) isAccessAllowed(ctx context.Context, token string) bool {
-	if a.verificationToken == "" {
-		return true
+// authenticate returns the identity of the token holder.
+// If no credentials are configured, everyone is an admin.
+func (a *Auth) authenticate(ctx context.Context, token string) (Identity, bool) {
+	a.mu.RLock()
+	verificationToken, tokens, jwt := a.verificationToken, a.tokens, a.jwt
+	a.mu.RUnlock()
+
+	if verificationToken == "" && (tokens == nil || len(tokens.tokens) == 0) && jwt == nil {
+		return Identity{Admin: true, Scopes: allScopes}, true
+	}
+
+	if verificationToken != "" && subtle.ConstantTimeCompare([]byte(verificationToken), []byte(token)) == 1 {
+		return Identity{Name: AdminIdentity, Admin: true, Scopes: allScopes}, true
 	}
 
-	if subtle.ConstantTimeCompare([]byte(a.verificationToken), []byte(token)) == 1 {
-		return true
+	if tokens != nil {
+		if identity, ok := tokens.authenticate(token, time.Now()); ok {
+			return identity, true
+		}
 	}
 
 	if a.personalTokenVerifier != nil && a.personalTokenVerifier.IsPersonalTokenEnabled() &&
 		a.personalTokenVerifier.IsAllowedToken(ctx, token) {
-		return true
+		return Identity{Name: a.personalTokenVerifier.TokenIdentity(token), Scopes: userScopes}, true
+	}
+
+	return Identity{}, false
+}
+
+// authenticateBearer returns the identity of the JWT bearer token holder.
+// If JWT bearer tokens are not enabled, the bearer token is checked as a verification token.
+func (a *Auth) authenticateBearer(ctx context.Context, token string) (Identity, bool) {
+	a.mu.RLock()
+	jwt := a.jwt
+	a.mu.RUnlock()
+
+	if jwt == nil {
+		return a.authenticate(ctx, token)
+	}
+
+	identity, err := jwt.authenticate(ctx, token, time.Now())
+	if err != nil {
+		log.Dbg("Failed to verify JWT bearer token:", err)
+		return Identity{}, false
 	}
 
-	return false
+	return identity, true
 }
@@ -30,6 +30,10 @@ func (m MockPersonalTokenVerifier) IsPersonalTokenEnabled() bool {
 	return m.isPersonalTokenEnabled
 }
 
+func (m MockPersonalTokenVerifier) TokenIdentity(token string) string {
+	return "platform:" + token
+}
+
 func TestAccess(t *testing.T) {
 	testCases := []struct {
 		name                   string
@@ -54,7 +58,39 @@ func TestAccess(t *testing.T) {
 		t.Log(tc.name)
 		mw.personalTokenVerifier = MockPersonalTokenVerifier{isPersonalTokenEnabled: tc.result}
 
-		isAllowed := mw.isAccessAllowed(context.Background(), tc.requestToken)
+		_, isAllowed := mw.authenticate(context.Background(), tc.requestToken)
 		assert.Equal(t, tc.result, isAllowed)
 	}
 }
+
+func TestIdentity(t *testing.T) {
+	mw := Auth{
+		verificationToken:     testVerificationToken,
+		personalTokenVerifier: MockPersonalTokenVerifier{isPersonalTokenEnabled: true},
+	}
+
+	identity, ok := mw.authenticate(context.Background(), testVerificationToken)
+	assert.True(t, ok)
+	assert.Equal(t, Identity{Name: AdminIdentity, Admin: true, Scopes: allScopes}, identity)
+
+	identity, ok = mw.authenticate(context.Background(), testPlatformAccessToken)
+	assert.True(t, ok)
+	assert.Equal(t, Identity{Name: "platform:" + testPlatformAccessToken, Scopes: userScopes}, identity)
+	assert.True(t, identity.HasScope(ScopeCloneCreate))
+	assert.False(t, identity.HasScope(ScopeSnapshotAdmin))
+
+	assert.True(t, identity.CanManage(""))
+	assert.True(t, identity.CanManage("platform:"+testPlatformAccessToken))
+	assert.False(t, identity.CanManage("platform:AnotherToken"))
+
+	ctx := WithIdentity(context.Background(), identity)
+	assert.Equal(t, identity, IdentityFromContext(ctx))
+	assert.Equal(t, Identity{}, IdentityFromContext(context.Background()))
+
+	mw.verificationToken = ""
+
+	identity, ok = mw.authenticate(context.Background(), "")
+	assert.True(t, ok)
+	assert.True(t, identity.Admin)
+	assert.True(t, identity.CanManage("platform:AnotherToken"))
+}
@@ -0,0 +1,60 @@
+/*
+2022 © Postgres.ai
+*/
+
+package mw
+
+import (
+	"context"
+)
+
+// AdminIdentity defines the identity of the verification token holder.
+const AdminIdentity = "admin"
+
+// Identity describes the authenticated user of the API.
+type Identity struct {
+	// Name identifies the user. Clones record the name of their creator as the owner.
+	Name string
+	// Admin allows managing clones of other users and transferring clone ownership.
+	Admin bool
+	// Scopes lists API routes the user is allowed to call.
+	Scopes []Scope
+}
+
+// HasScope checks whether the user is granted the scope.
+func (i Identity) HasScope(scope Scope) bool {
+	for _, granted := range i.Scopes {
+		if granted == scope || scope == ScopeReadOnly {
+			return true
+		}
+
+		for _, implied := range impliedScopes[granted] {
+			if implied == scope {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// CanManage checks whether the user is allowed to manage a resource of the owner.
+// Resources without owner, e.g. clones created before owners were recorded, can be managed by anyone.
+func (i Identity) CanManage(owner string) bool {
+	return i.Admin || owner == "" || owner == i.Name
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of the context carrying the identity.
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the identity of the authenticated user.
+// The zero identity is returned if the context has no identity.
+func IdentityFromContext(ctx context.Context) Identity {
+	identity, _ := ctx.Value(identityKey{}).(Identity)
+
+	return identity
+}
@@ -0,0 +1,293 @@
+/*
+2022 © Postgres.ai
+*/
+
+package mw
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksRefreshInterval defines how long keys fetched from the JWKS URL are used before fetching them again.
+	jwksRefreshInterval = time.Hour
+	// jwksMinRefreshInterval limits how often keys are fetched when tokens are signed with unknown keys.
+	jwksMinRefreshInterval = time.Minute
+	jwksRequestTimeout     = 10 * time.Second
+	jwksMaxSize            = 1 << 20
+
+	// minRSAKeySize defines the minimum size of RSA keys in bits.
+	minRSAKeySize = 2048
+)
+
+// jsonWebKey describes a public key of a JSON Web Key Set defined in RFC 7517.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// verificationKey describes a public key used to verify token signatures.
+type verificationKey struct {
+	id        string
+	algorithm string
+	publicKey crypto.PublicKey
+}
+
+// parseJWKS parses a JSON Web Key Set. Keys not intended for signatures and keys of unsupported types are skipped.
+func parseJWKS(data []byte) ([]verificationKey, error) {
+	var keySet struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &keySet); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make([]verificationKey, 0, len(keySet.Keys))
+
+	for _, jwk := range keySet.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		publicKey, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key %q: %w", jwk.Kid, err)
+		}
+
+		if publicKey == nil {
+			continue
+		}
+
+		keys = append(keys, verificationKey{id: jwk.Kid, algorithm: jwk.Alg, publicKey: publicKey})
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS does not contain signature verification keys")
+	}
+
+	return keys, nil
+}
+
+// publicKey returns the public key defined by JWK. Nil is returned for unsupported key types.
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeKeyParameter(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		if n.BitLen() < minRSAKeySize {
+			return nil, fmt.Errorf("RSA key size %d is less than %d bits", n.BitLen(), minRSAKeySize)
+		}
+
+		e, err := decodeKeyParameter(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		if !e.IsInt64() {
+			return nil, errors.New("invalid RSA exponent")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decodeKeyParameter(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeKeyParameter(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve %q", k.Crv)
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, nil
+}
+
+func decodeKeyParameter(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode key parameter: %w", err)
+	}
+
+	if len(data) == 0 {
+		return nil, errors.New("key parameter is empty")
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
+
+// keySet provides keys loaded from a JWKS file or fetched from a JWKS URL.
+type keySet struct {
+	mu        sync.Mutex
+	url       string
+	client    *http.Client
+	keys      []verificationKey
+	fetchedAt time.Time
+	fetchErr  error
+
+	// fetchDone is closed when the current fetch of keys is finished. It is nil if keys are not being fetched.
+	fetchDone chan struct{}
+}
+
+// newFileKeySet loads keys from the local JWKS file.
+func newFileKeySet(path string) (*keySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keySet{keys: keys}, nil
+}
+
+// newURLKeySet creates a key set fetching keys from the JWKS URL on demand.
+func newURLKeySet(url string) *keySet {
+	return &keySet{url: url, client: &http.Client{Timeout: jwksRequestTimeout}}
+}
+
+// key returns the key by ID. Keys are fetched again if the key is unknown or the fetched keys are outdated.
+// The only key of the set is returned if the ID is empty.
+// Keys are fetched by one request at a time without holding the lock. Other requests wait for unknown keys
+// and use known keys meanwhile.
+func (s *keySet) key(ctx context.Context, id string, now time.Time) (verificationKey, error) {
+	s.mu.Lock()
+	key, ok := findKey(s.keys, id)
+	fetchDone, isFetcher := s.fetchDone, false
+
+	if fetchDone == nil && s.url != "" &&
+		((!ok && now.Sub(s.fetchedAt) > jwksMinRefreshInterval) || now.Sub(s.fetchedAt) > jwksRefreshInterval) {
+		fetchDone, isFetcher = make(chan struct{}), true
+		s.fetchDone, s.fetchedAt = fetchDone, now
+	}
+	s.mu.Unlock()
+
+	switch {
+	case isFetcher:
+		keys, err := s.fetch(ctx)
+
+		s.mu.Lock()
+		if err == nil {
+			s.keys = keys
+		}
+
+		s.fetchErr = err
+		s.fetchDone = nil
+		s.mu.Unlock()
+
+		close(fetchDone)
+
+	case ok:
+		return key, nil
+
+	case fetchDone != nil:
+		select {
+		case <-fetchDone:
+		case <-ctx.Done():
+			return verificationKey{}, ctx.Err()
+		}
+	}
+
+	s.mu.Lock()
+	key, ok = findKey(s.keys, id)
+	fetchErr := s.fetchErr
+	s.mu.Unlock()
+
+	if !ok {
+		if fetchErr != nil {
+			return verificationKey{}, fetchErr
+		}
+
+		return verificationKey{}, fmt.Errorf("unknown key %q", id)
+	}
+
+	return key, nil
+}
+
+// fetch fetches keys from the JWKS URL.
+func (s *keySet) fetch(ctx context.Context) ([]verificationKey, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make a JWKS request: %w", err)
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: unexpected status code %d", response.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(response.Body, jwksMaxSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+
+	return parseJWKS(data)
+}
+
+func findKey(keys []verificationKey, id string) (verificationKey, bool) {
+	if id == "" && len(keys) == 1 {
+		return keys[0], true
+	}
+
+	for _, key := range keys {
+		if key.id == id {
+			return key, true
+		}
+	}
+
+	return verificationKey{}, false
+}
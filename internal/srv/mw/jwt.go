@@ -0,0 +1,334 @@
+/*
+2022 © Postgres.ai
+*/
+
+package mw
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	// Register hash functions used by signature algorithms.
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/srv/config"
+)
+
+const (
+	// jwtIdentityPrefix defines the prefix of identities of JWT bearer token holders.
+	jwtIdentityPrefix = "jwt:"
+
+	defaultUsernameClaim = "sub"
+	defaultRolesClaim    = "roles"
+
+	// jwtLeeway defines the allowed clock skew between the engine and the token issuer.
+	jwtLeeway = time.Minute
+
+	// maxNumericDate limits NumericDate claims to integers exactly represented by float64.
+	maxNumericDate = 1 << 53
+)
+
+// signatureAlgorithm describes a supported algorithm of JWT signatures.
+type signatureAlgorithm struct {
+	hash crypto.Hash
+	// curve defines the curve of ECDSA keys required by the algorithm. It is nil for RSA algorithms.
+	curve elliptic.Curve
+}
+
+var signatureAlgorithms = map[string]signatureAlgorithm{
+	"RS256": {hash: crypto.SHA256},
+	"RS384": {hash: crypto.SHA384},
+	"RS512": {hash: crypto.SHA512},
+	"ES256": {hash: crypto.SHA256, curve: elliptic.P256()},
+	"ES384": {hash: crypto.SHA384, curve: elliptic.P384()},
+	"ES512": {hash: crypto.SHA512, curve: elliptic.P521()},
+}
+
+// jwtValidator verifies JWT bearer tokens and maps their claims to identities.
+type jwtValidator struct {
+	keys          *keySet
+	issuer        string
+	audience      string
+	usernameClaim string
+	rolesClaim    []string
+	roles         map[string][]Scope
+}
+
+// ValidateJWT checks whether the verification of JWT bearer tokens is correctly defined.
+func ValidateJWT(cfg config.JWTConfig) error {
+	_, err := newJWTValidator(cfg)
+
+	return err
+}
+
+// newJWTValidator creates a validator of JWT bearer tokens. Nil is returned if JWT bearer tokens are not enabled.
+func newJWTValidator(cfg config.JWTConfig) (*jwtValidator, error) {
+	if !cfg.IsEnabled() {
+		return nil, nil
+	}
+
+	validator := &jwtValidator{
+		issuer:        cfg.Issuer,
+		audience:      cfg.Audience,
+		usernameClaim: cfg.UsernameClaim,
+		rolesClaim:    strings.Split(cfg.RolesClaim, "."),
+		roles:         make(map[string][]Scope, len(cfg.Roles)),
+	}
+
+	if validator.usernameClaim == "" {
+		validator.usernameClaim = defaultUsernameClaim
+	}
+
+	if cfg.RolesClaim == "" {
+		validator.rolesClaim = []string{defaultRolesClaim}
+	}
+
+	for role, scopes := range cfg.Roles {
+		for _, scope := range scopes {
+			if !isKnownScope(Scope(scope)) {
+				return nil, fmt.Errorf("JWT role %q has unknown scope %q", role, scope)
+			}
+
+			validator.roles[role] = append(validator.roles[role], Scope(scope))
+		}
+	}
+
+	switch {
+	case cfg.JWKSURL != "" && cfg.JWKSFile != "":
+		return nil, errors.New("only one of jwksURL and jwksFile can be defined")
+
+	case cfg.JWKSFile != "":
+		keys, err := newFileKeySet(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+
+		validator.keys = keys
+
+	default:
+		validator.keys = newURLKeySet(cfg.JWKSURL)
+	}
+
+	return validator, nil
+}
+
+// jwtHeader describes the JOSE header of JWT.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// authenticate verifies the token and returns the identity of the token holder.
+func (v *jwtValidator) authenticate(ctx context.Context, token string, now time.Time) (Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Identity{}, errors.New("malformed token")
+	}
+
+	var header jwtHeader
+
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Identity{}, fmt.Errorf("failed to decode token header: %w", err)
+	}
+
+	algorithm, ok := signatureAlgorithms[header.Alg]
+	if !ok {
+		return Identity{}, fmt.Errorf("unsupported signature algorithm %q", header.Alg)
+	}
+
+	key, err := v.keys.key(ctx, header.Kid, now)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	if key.algorithm != "" && key.algorithm != header.Alg {
+		return Identity{}, fmt.Errorf("key %q is not intended for algorithm %q", key.id, header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Identity{}, fmt.Errorf("failed to decode token signature: %w", err)
+	}
+
+	if err := verifySignature(key.publicKey, algorithm, parts[0]+"."+parts[1], signature); err != nil {
+		return Identity{}, err
+	}
+
+	var claims map[string]interface{}
+
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Identity{}, fmt.Errorf("failed to decode token claims: %w", err)
+	}
+
+	if err := v.validateClaims(claims, now); err != nil {
+		return Identity{}, err
+	}
+
+	return v.identity(claims)
+}
+
+// validateClaims checks the validity period, issuer, and audience of the token.
+func (v *jwtValidator) validateClaims(claims map[string]interface{}, now time.Time) error {
+	expiresAt, ok := numericDate(claims["exp"])
+	if !ok {
+		return errors.New("token has no expiration time")
+	}
+
+	if now.After(expiresAt.Add(jwtLeeway)) {
+		return errors.New("token is expired")
+	}
+
+	if notBefore, ok := numericDate(claims["nbf"]); ok && now.Add(jwtLeeway).Before(notBefore) {
+		return errors.New("token is not valid yet")
+	}
+
+	if v.issuer != "" && claims["iss"] != v.issuer {
+		return fmt.Errorf("unexpected token issuer %v", claims["iss"])
+	}
+
+	if v.audience != "" && !containsString(claims["aud"], v.audience) {
+		return fmt.Errorf("token is not intended for audience %q", v.audience)
+	}
+
+	return nil
+}
+
+// identity maps claims of the token to the identity of the user.
+func (v *jwtValidator) identity(claims map[string]interface{}) (Identity, error) {
+	username, ok := claims[v.usernameClaim].(string)
+	if !ok || username == "" {
+		return Identity{}, fmt.Errorf("token has no %q claim", v.usernameClaim)
+	}
+
+	identity := Identity{Name: jwtIdentityPrefix + username}
+
+	var rolesValue interface{} = claims
+
+	for _, claim := range v.rolesClaim {
+		nestedClaims, ok := rolesValue.(map[string]interface{})
+		if !ok {
+			rolesValue = nil
+			break
+		}
+
+		rolesValue = nestedClaims[claim]
+	}
+
+	for role, scopes := range v.roles {
+		if !containsString(rolesValue, role) {
+			continue
+		}
+
+		for _, scope := range scopes {
+			if scope == ScopeCloneAdmin {
+				identity.Admin = true
+			}
+
+			identity.Scopes = append(identity.Scopes, scope)
+		}
+	}
+
+	return identity, nil
+}
+
+// verifySignature verifies the signature of the signing input with the public key.
+func verifySignature(publicKey crypto.PublicKey, algorithm signatureAlgorithm, signingInput string, signature []byte) error {
+	hash := algorithm.hash.New()
+	_, _ = hash.Write([]byte(signingInput))
+	digest := hash.Sum(nil)
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		if algorithm.curve != nil {
+			return errors.New("key type does not match the signature algorithm")
+		}
+
+		if err := rsa.VerifyPKCS1v15(key, algorithm.hash, digest, signature); err != nil {
+			return errors.New("invalid token signature")
+		}
+
+	case *ecdsa.PublicKey:
+		if algorithm.curve == nil {
+			return errors.New("key type does not match the signature algorithm")
+		}
+
+		if key.Curve.Params().Name != algorithm.curve.Params().Name {
+			return errors.New("key curve does not match the signature algorithm")
+		}
+
+		keySize := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*keySize {
+			return errors.New("invalid token signature")
+		}
+
+		r := new(big.Int).SetBytes(signature[:keySize])
+		s := new(big.Int).SetBytes(signature[keySize:])
+
+		if !ecdsa.Verify(key, digest, r, s) {
+			return errors.New("invalid token signature")
+		}
+
+	default:
+		return errors.New("unsupported key type")
+	}
+
+	return nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	return decoder.Decode(v)
+}
+
+// numericDate converts the NumericDate claim to time.
+// Non-integer and out-of-range values are rejected because their conversion to integers is not well-defined.
+func numericDate(value interface{}) (time.Time, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	seconds, err := number.Float64()
+	if err != nil || seconds != math.Trunc(seconds) || math.Abs(seconds) > maxNumericDate {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(seconds), 0), true
+}
+
+// containsString checks whether the claim is equal to the string or is an array containing the string.
+func containsString(claim interface{}, value string) bool {
+	switch claim := claim.(type) {
+	case string:
+		return claim == value
+
+	case []interface{}:
+		for _, item := range claim {
+			if item == value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
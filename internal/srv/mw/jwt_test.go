@@ -0,0 +1,393 @@
+/*
+2022 © Postgres.ai
+*/
+
+package mw
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/srv/config"
+)
+
+const (
+	testIssuer   = "https://sso.example.com"
+	testAudience = "dblab"
+)
+
+type testSigningKeys struct {
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+}
+
+func newTestSigningKeys(t *testing.T) testSigningKeys {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return testSigningKeys{rsaKey: rsaKey, ecKey: ecKey}
+}
+
+func (k testSigningKeys) jwks(t *testing.T) []byte {
+	encode := func(value *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(value.Bytes())
+	}
+
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []jsonWebKey{
+			{Kty: "RSA", Kid: "rsa-key", Use: "sig", Alg: "RS256", N: encode(k.rsaKey.N), E: encode(big.NewInt(int64(k.rsaKey.E)))},
+			{Kty: "EC", Kid: "ec-key", Crv: "P-256", X: encode(k.ecKey.X), Y: encode(k.ecKey.Y)},
+			{Kty: "RSA", Kid: "enc-key", Use: "enc", N: encode(k.rsaKey.N), E: encode(big.NewInt(int64(k.rsaKey.E)))},
+		},
+	})
+	require.NoError(t, err)
+
+	return jwks
+}
+
+func (k testSigningKeys) sign(t *testing.T, alg, kid string, claims map[string]interface{}) string {
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	signingInput := encode(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"}) + "." + encode(claims)
+	digest := crypto.SHA256.New()
+	_, _ = digest.Write([]byte(signingInput))
+
+	var signature []byte
+
+	switch alg {
+	case "RS256":
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k.rsaKey, crypto.SHA256, digest.Sum(nil))
+		require.NoError(t, err)
+
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, k.ecKey, digest.Sum(nil))
+		require.NoError(t, err)
+
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func testJWTConfig(jwksFile string) config.JWTConfig {
+	return config.JWTConfig{
+		JWKSFile:      jwksFile,
+		Issuer:        testIssuer,
+		Audience:      testAudience,
+		UsernameClaim: "preferred_username",
+		RolesClaim:    "realm_access.roles",
+		Roles: map[string][]string{
+			"dblab-admins": {"clone:admin", "snapshot:admin"},
+			"developers":   {"clone:create"},
+		},
+	}
+}
+
+func TestJWTValidation(t *testing.T) {
+	keys := newTestSigningKeys(t)
+	now := time.Now()
+
+	jwksFile := path.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwksFile, keys.jwks(t), 0600))
+
+	validator, err := newJWTValidator(testJWTConfig(jwksFile))
+	require.NoError(t, err)
+
+	claims := func(overrides map[string]interface{}) map[string]interface{} {
+		claims := map[string]interface{}{
+			"iss":                testIssuer,
+			"aud":                []string{testAudience, "account"},
+			"sub":                "f3a1c2",
+			"preferred_username": "alice",
+			"exp":                now.Add(time.Hour).Unix(),
+			"realm_access":       map[string]interface{}{"roles": []string{"developers", "offline_access"}},
+		}
+
+		for claim, value := range overrides {
+			claims[claim] = value
+		}
+
+		return claims
+	}
+
+	identity, err := validator.authenticate(context.Background(), keys.sign(t, "RS256", "rsa-key", claims(nil)), now)
+	require.NoError(t, err)
+	assert.Equal(t, "jwt:alice", identity.Name)
+	assert.False(t, identity.Admin)
+	assert.True(t, identity.HasScope(ScopeCloneCreate))
+	assert.False(t, identity.HasScope(ScopeSnapshotAdmin))
+
+	adminClaims := claims(map[string]interface{}{
+		"preferred_username": "bob",
+		"realm_access":       map[string]interface{}{"roles": []string{"dblab-admins"}},
+	})
+
+	identity, err = validator.authenticate(context.Background(), keys.sign(t, "ES256", "ec-key", adminClaims), now)
+	require.NoError(t, err)
+	assert.Equal(t, "jwt:bob", identity.Name)
+	assert.True(t, identity.Admin)
+	assert.True(t, identity.HasScope(ScopeSnapshotAdmin))
+	assert.True(t, identity.CanManage("jwt:alice"))
+
+	identity, err = validator.authenticate(context.Background(), keys.sign(t, "RS256", "rsa-key", claims(map[string]interface{}{
+		"realm_access": map[string]interface{}{"roles": []string{"offline_access"}},
+	})), now)
+	require.NoError(t, err)
+	assert.False(t, identity.HasScope(ScopeReadOnly))
+
+	invalidTokens := map[string]string{
+		"expired":          keys.sign(t, "RS256", "rsa-key", claims(map[string]interface{}{"exp": now.Add(-time.Hour).Unix()})),
+		"no expiration":    keys.sign(t, "RS256", "rsa-key", claims(map[string]interface{}{"exp": nil})),
+		"not valid yet":    keys.sign(t, "RS256", "rsa-key", claims(map[string]interface{}{"nbf": now.Add(time.Hour).Unix()})),
+		"wrong issuer":     keys.sign(t, "RS256", "rsa-key", claims(map[string]interface{}{"iss": "https://evil.example.com"})),
+		"wrong audience":   keys.sign(t, "RS256", "rsa-key", claims(map[string]interface{}{"aud": "another-service"})),
+		"no username":      keys.sign(t, "RS256", "rsa-key", claims(map[string]interface{}{"preferred_username": nil})),
+		"unknown key":      keys.sign(t, "RS256", "unknown-key", claims(nil)),
+		"encryption key":   keys.sign(t, "RS256", "enc-key", claims(nil)),
+		"key of other alg": keys.sign(t, "ES256", "rsa-key", claims(nil)),
+		"alg none":         base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"alice"}`)) + ".",
+		"malformed":        "not-a-token",
+	}
+
+	// Replace claims of a signed token keeping its signature.
+	signedParts := strings.Split(keys.sign(t, "RS256", "rsa-key", claims(nil)), ".")
+	tamperedParts := strings.Split(keys.sign(t, "RS256", "rsa-key", claims(map[string]interface{}{"preferred_username": "mallory"})), ".")
+	invalidTokens["tampered"] = strings.Join([]string{signedParts[0], tamperedParts[1], signedParts[2]}, ".")
+
+	for name, token := range invalidTokens {
+		_, err := validator.authenticate(context.Background(), token, now)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestJWTConfigValidation(t *testing.T) {
+	jwksFile := path.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwksFile, newTestSigningKeys(t).jwks(t), 0600))
+
+	require.NoError(t, ValidateJWT(config.JWTConfig{}))
+	require.NoError(t, ValidateJWT(testJWTConfig(jwksFile)))
+
+	cfg := testJWTConfig(jwksFile)
+	cfg.JWKSURL = "https://sso.example.com/jwks"
+	assert.Error(t, ValidateJWT(cfg))
+
+	cfg = testJWTConfig(jwksFile)
+	cfg.Roles = map[string][]string{"developers": {"clone:destroy"}}
+	assert.Error(t, ValidateJWT(cfg))
+
+	assert.Error(t, ValidateJWT(testJWTConfig(path.Join(t.TempDir(), "missing.json"))))
+}
+
+func TestJWTBearerAuthorization(t *testing.T) {
+	keys := newTestSigningKeys(t)
+
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(keys.jwks(t))
+	}))
+	defer jwksServer.Close()
+
+	jwtConfig := testJWTConfig("")
+	jwtConfig.JWKSURL = jwksServer.URL
+
+	authMW, err := NewAuth(config.Config{VerificationToken: testVerificationToken, JWT: jwtConfig}, nil)
+	require.NoError(t, err)
+
+	handler := authMW.Authorized(ScopeCloneCreate, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "jwt:alice", IdentityFromContext(r.Context()).Name)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	token := func(roles ...string) string {
+		return keys.sign(t, "RS256", "rsa-key", map[string]interface{}{
+			"iss":                testIssuer,
+			"aud":                testAudience,
+			"preferred_username": "alice",
+			"exp":                time.Now().Add(time.Hour).Unix(),
+			"realm_access":       map[string]interface{}{"roles": roles},
+		})
+	}
+
+	testCases := []struct {
+		authorization string
+		statusCode    int
+	}{
+		{authorization: "Bearer " + token("developers"), statusCode: http.StatusOK},
+		{authorization: "Bearer " + token(), statusCode: http.StatusForbidden},
+		{authorization: "Bearer " + testVerificationToken, statusCode: http.StatusUnauthorized},
+		{authorization: "Basic " + token("developers"), statusCode: http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		r := httptest.NewRequest(http.MethodPost, "/clone", nil)
+		r.Header.Set(AuthorizationHeader, tc.authorization)
+
+		w := httptest.NewRecorder()
+		handler(w, r)
+
+		assert.Equal(t, tc.statusCode, w.Code, tc.authorization)
+	}
+}
+
+func TestKeySetFetch(t *testing.T) {
+	keys := newTestSigningKeys(t)
+	jwks := keys.jwks(t)
+
+	var requests int32
+
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		time.Sleep(100 * time.Millisecond)
+
+		_, _ = w.Write(jwks)
+	}))
+	defer jwksServer.Close()
+
+	keySet := newURLKeySet(jwksServer.URL)
+	now := time.Now()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			key, err := keySet.key(context.Background(), "ec-key", now)
+			assert.NoError(t, err)
+			assert.Equal(t, "ec-key", key.id)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	_, err := keySet.key(context.Background(), "unknown-key", now)
+	assert.EqualError(t, err, `unknown key "unknown-key"`)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestVerifySignatureCurve(t *testing.T) {
+	signingInput := "header.payload"
+
+	signECDSA := func(key *ecdsa.PrivateKey, hash crypto.Hash) []byte {
+		digest := hash.New()
+		_, _ = digest.Write([]byte(signingInput))
+
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest.Sum(nil))
+		require.NoError(t, err)
+
+		keySize := (key.Curve.Params().BitSize + 7) / 8
+		signature := make([]byte, 2*keySize)
+		r.FillBytes(signature[:keySize])
+		s.FillBytes(signature[keySize:])
+
+		return signature
+	}
+
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	p521Key, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		key   *ecdsa.PrivateKey
+		alg   string
+		valid bool
+	}{
+		{key: p256Key, alg: "ES256", valid: true},
+		{key: p384Key, alg: "ES384", valid: true},
+		{key: p521Key, alg: "ES512", valid: true},
+		{key: p384Key, alg: "ES256"},
+		{key: p521Key, alg: "ES256"},
+		{key: p256Key, alg: "ES384"},
+		{key: p521Key, alg: "ES384"},
+		{key: p256Key, alg: "ES512"},
+	}
+
+	for _, tc := range testCases {
+		algorithm := signatureAlgorithms[tc.alg]
+		signature := signECDSA(tc.key, algorithm.hash)
+
+		err := verifySignature(&tc.key.PublicKey, algorithm, signingInput, signature)
+		if tc.valid {
+			assert.NoError(t, err, tc.alg)
+			continue
+		}
+
+		assert.EqualError(t, err, "key curve does not match the signature algorithm", tc.alg)
+	}
+
+	keys := newTestSigningKeys(t)
+
+	err = verifySignature(&keys.rsaKey.PublicKey, signatureAlgorithms["ES256"], signingInput, nil)
+	assert.EqualError(t, err, "key type does not match the signature algorithm")
+
+	err = verifySignature(&p256Key.PublicKey, signatureAlgorithms["RS256"], signingInput, nil)
+	assert.EqualError(t, err, "key type does not match the signature algorithm")
+}
+
+func TestNumericDate(t *testing.T) {
+	date, ok := numericDate(json.Number("1600000000"))
+	require.True(t, ok)
+	assert.Equal(t, time.Unix(1600000000, 0), date)
+
+	date, ok = numericDate(json.Number("1.6e9"))
+	require.True(t, ok)
+	assert.Equal(t, time.Unix(1600000000, 0), date)
+
+	for _, value := range []interface{}{json.Number("1e300"), json.Number("-1e300"), json.Number("9007199254740994"),
+		json.Number("1600000000.5"), "1600000000"} {
+		_, ok := numericDate(value)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestWeakRSAKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	jwk := jsonWebKey{
+		Kty: "RSA",
+		N:   base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+	}
+
+	_, err = jwk.publicKey()
+	require.EqualError(t, err, "RSA key size 1024 is less than 2048 bits")
+}
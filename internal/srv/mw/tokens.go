@@ -0,0 +1,171 @@
+/*
+2022 © Postgres.ai
+*/
+
+package mw
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/srv/config"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
+)
+
+// Scope defines a permission granted to API tokens.
+type Scope string
+
+const (
+	// ScopeReadOnly allows reading the instance status, snapshots, clones, and operations.
+	// Tokens with any other scope are allowed to read as well.
+	ScopeReadOnly Scope = "read-only"
+	// ScopeCloneCreate allows creating clones and managing own clones.
+	ScopeCloneCreate Scope = "clone:create"
+	// ScopeCloneAdmin allows managing clones of all users and transferring clone ownership.
+	ScopeCloneAdmin Scope = "clone:admin"
+	// ScopeSnapshotAdmin allows creating, updating, and destroying snapshots.
+	ScopeSnapshotAdmin Scope = "snapshot:admin"
+	// ScopeObservation allows running observation sessions and downloading their artifacts.
+	ScopeObservation Scope = "observation"
+	// ScopeAudit allows reading the audit log.
+	ScopeAudit Scope = "audit"
+)
+
+// tokenIdentityPrefix defines the prefix of identities of API token holders.
+const tokenIdentityPrefix = "token:"
+
+// allScopes lists scopes of the verification token holder.
+var allScopes = []Scope{ScopeReadOnly, ScopeCloneCreate, ScopeCloneAdmin, ScopeSnapshotAdmin, ScopeObservation, ScopeAudit}
+
+// userScopes lists scopes of Platform users, who manage only their own clones.
+// Snapshots are shared by all users, so managing them is left to admins.
+var userScopes = []Scope{ScopeReadOnly, ScopeCloneCreate, ScopeObservation}
+
+// impliedScopes lists scopes granted along with a scope.
+var impliedScopes = map[Scope][]Scope{
+	ScopeCloneAdmin: {ScopeCloneCreate},
+}
+
+// isKnownScope checks whether the scope is supported.
+func isKnownScope(scope Scope) bool {
+	for _, knownScope := range allScopes {
+		if scope == knownScope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// apiToken describes an API token defined in the configuration.
+type apiToken struct {
+	name      string
+	token     []byte
+	scopes    []Scope
+	expiresAt *time.Time
+	revoked   bool
+}
+
+// tokenStore keeps API tokens defined in the configuration.
+type tokenStore struct {
+	tokens []apiToken
+}
+
+// ValidateTokens checks whether API tokens are correctly defined.
+func ValidateTokens(tokens []config.APIToken) error {
+	_, err := newTokenStore(tokens)
+
+	return err
+}
+
+func newTokenStore(tokens []config.APIToken) (*tokenStore, error) {
+	store := &tokenStore{tokens: make([]apiToken, 0, len(tokens))}
+	names := make(map[string]struct{}, len(tokens))
+	secrets := make(map[string]struct{}, len(tokens))
+
+	for _, token := range tokens {
+		if token.Name == "" {
+			return nil, errors.New("API token name must not be empty")
+		}
+
+		if _, ok := names[token.Name]; ok {
+			return nil, fmt.Errorf("API token %q is defined more than once", token.Name)
+		}
+
+		if token.Token == "" {
+			return nil, fmt.Errorf("API token %q must not be empty", token.Name)
+		}
+
+		if _, ok := secrets[token.Token]; ok {
+			return nil, fmt.Errorf("API token %q duplicates another token", token.Name)
+		}
+
+		if len(token.Scopes) == 0 {
+			return nil, fmt.Errorf("API token %q must have at least one scope", token.Name)
+		}
+
+		scopes := make([]Scope, 0, len(token.Scopes))
+
+		for _, scope := range token.Scopes {
+			if !isKnownScope(Scope(scope)) {
+				return nil, fmt.Errorf("API token %q has unknown scope %q", token.Name, scope)
+			}
+
+			scopes = append(scopes, Scope(scope))
+		}
+
+		names[token.Name] = struct{}{}
+		secrets[token.Token] = struct{}{}
+
+		store.tokens = append(store.tokens, apiToken{
+			name:      token.Name,
+			token:     []byte(token.Token),
+			scopes:    scopes,
+			expiresAt: token.ExpiresAt,
+			revoked:   token.Revoked,
+		})
+	}
+
+	return store, nil
+}
+
+// authenticate returns the identity of the API token holder. Revoked and expired tokens are rejected.
+func (s *tokenStore) authenticate(token string, now time.Time) (Identity, bool) {
+	var matched *apiToken
+
+	// Compare the token with all stored ones to not reveal the position of the matching token.
+	for i := range s.tokens {
+		if subtle.ConstantTimeCompare(s.tokens[i].token, []byte(token)) == 1 {
+			matched = &s.tokens[i]
+		}
+	}
+
+	if matched == nil {
+		return Identity{}, false
+	}
+
+	if matched.revoked {
+		log.Dbg(fmt.Sprintf("API token %q is revoked", matched.name))
+		return Identity{}, false
+	}
+
+	if matched.expiresAt != nil && now.After(*matched.expiresAt) {
+		log.Dbg(fmt.Sprintf("API token %q expired at %s", matched.name, matched.expiresAt.Format(time.RFC3339)))
+		return Identity{}, false
+	}
+
+	identity := Identity{
+		Name:   tokenIdentityPrefix + matched.name,
+		Scopes: matched.scopes,
+	}
+
+	for _, scope := range matched.scopes {
+		if scope == ScopeCloneAdmin {
+			identity.Admin = true
+		}
+	}
+
+	return identity, true
+}
@@ -0,0 +1,132 @@
+/*
+2022 © Postgres.ai
+*/
+
+package mw
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/srv/config"
+)
+
+func TestTokenValidation(t *testing.T) {
+	testCases := []struct {
+		name   string
+		tokens []config.APIToken
+		valid  bool
+	}{
+		{name: "no tokens", tokens: nil, valid: true},
+		{name: "valid token", tokens: []config.APIToken{{Name: "ci", Token: "ci-token", Scopes: []string{"clone:create"}}}, valid: true},
+		{name: "empty name", tokens: []config.APIToken{{Token: "ci-token", Scopes: []string{"clone:create"}}}},
+		{name: "empty token", tokens: []config.APIToken{{Name: "ci", Scopes: []string{"clone:create"}}}},
+		{name: "no scopes", tokens: []config.APIToken{{Name: "ci", Token: "ci-token"}}},
+		{name: "unknown scope", tokens: []config.APIToken{{Name: "ci", Token: "ci-token", Scopes: []string{"clone:destroy"}}}},
+		{name: "duplicate name", tokens: []config.APIToken{
+			{Name: "ci", Token: "ci-token", Scopes: []string{"clone:create"}},
+			{Name: "ci", Token: "another-token", Scopes: []string{"read-only"}},
+		}},
+		{name: "duplicate token", tokens: []config.APIToken{
+			{Name: "ci", Token: "ci-token", Scopes: []string{"clone:create"}},
+			{Name: "bi", Token: "ci-token", Scopes: []string{"read-only"}},
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Log(tc.name)
+
+		err := ValidateTokens(tc.tokens)
+		assert.Equal(t, tc.valid, err == nil)
+	}
+}
+
+func TestTokenAuthentication(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Hour)
+	valid := now.Add(time.Hour)
+
+	store, err := newTokenStore([]config.APIToken{
+		{Name: "bi", Token: "bi-token", Scopes: []string{"read-only"}, ExpiresAt: &valid},
+		{Name: "ci", Token: "ci-token", Scopes: []string{"clone:create"}},
+		{Name: "dba", Token: "dba-token", Scopes: []string{"clone:admin", "snapshot:admin"}},
+		{Name: "old", Token: "old-token", Scopes: []string{"read-only"}, ExpiresAt: &expired},
+		{Name: "leaked", Token: "leaked-token", Scopes: []string{"clone:admin"}, Revoked: true},
+	})
+	require.NoError(t, err)
+
+	identity, ok := store.authenticate("bi-token", now)
+	require.True(t, ok)
+	assert.Equal(t, "token:bi", identity.Name)
+	assert.False(t, identity.Admin)
+	assert.True(t, identity.HasScope(ScopeReadOnly))
+	assert.False(t, identity.HasScope(ScopeCloneCreate))
+
+	identity, ok = store.authenticate("ci-token", now)
+	require.True(t, ok)
+	assert.True(t, identity.HasScope(ScopeReadOnly))
+	assert.True(t, identity.HasScope(ScopeCloneCreate))
+	assert.False(t, identity.HasScope(ScopeCloneAdmin))
+	assert.False(t, identity.HasScope(ScopeSnapshotAdmin))
+	assert.False(t, identity.CanManage("token:dba"))
+
+	identity, ok = store.authenticate("dba-token", now)
+	require.True(t, ok)
+	assert.True(t, identity.Admin)
+	assert.True(t, identity.HasScope(ScopeCloneCreate))
+	assert.True(t, identity.HasScope(ScopeSnapshotAdmin))
+	assert.False(t, identity.HasScope(ScopeObservation))
+	assert.True(t, identity.CanManage("token:ci"))
+
+	_, ok = store.authenticate("old-token", now)
+	assert.False(t, ok)
+
+	_, ok = store.authenticate("leaked-token", now)
+	assert.False(t, ok)
+
+	_, ok = store.authenticate("unknown-token", now)
+	assert.False(t, ok)
+}
+
+func TestAuthorizedScope(t *testing.T) {
+	authMW, err := NewAuth(config.Config{
+		VerificationToken: testVerificationToken,
+		Tokens:            []config.APIToken{{Name: "bi", Token: "bi-token", Scopes: []string{"read-only"}}},
+	}, nil)
+	require.NoError(t, err)
+
+	handler := authMW.Authorized(ScopeCloneCreate, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, AdminIdentity, IdentityFromContext(r.Context()).Name)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	testCases := []struct {
+		token      string
+		statusCode int
+	}{
+		{token: testVerificationToken, statusCode: http.StatusOK},
+		{token: "bi-token", statusCode: http.StatusForbidden},
+		{token: "WrongToken", statusCode: http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		r := httptest.NewRequest(http.MethodPost, "/clone", nil)
+		r.Header.Set(VerificationTokenHeader, tc.token)
+
+		w := httptest.NewRecorder()
+		handler(w, r)
+
+		assert.Equal(t, tc.statusCode, w.Code)
+	}
+
+	require.NoError(t, authMW.Reload(config.Config{VerificationToken: testVerificationToken}))
+
+	_, ok := authMW.authenticate(context.Background(), "bi-token")
+	assert.False(t, ok)
+}
@@ -1,11 +1,16 @@
 package srv
 
 import (
+	"archive/tar"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
 	"strconv"
 	"time"
 
@@ -14,9 +19,12 @@ import (
 	"github.com/jackc/pgtype/pgxtype"
 	"github.com/pkg/errors"
 
+	"gitlab.com/postgres-ai/database-lab/v3/internal/audit"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/estimator"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/observer"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/srv/api"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/srv/mw"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/telemetry"
 
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
@@ -27,66 +35,710 @@ import (
 	"gitlab.com/postgres-ai/database-lab/v3/version"
 )
 
+const (
+	// operationIDHeader defines the response header containing the ID of the operation started by the request.
+	operationIDHeader = mw.OperationIDHeader
+
+	// defaultAuditLimit defines the number of audit log entries returned if the limit is not specified.
+	defaultAuditLimit = 100
+)
+
 func (s *Server) getInstanceStatus(w http.ResponseWriter, r *http.Request) {
-	if err := api.WriteJSON(w, http.StatusOK, s.instanceStatus()); err != nil {
+	labelSelector, err := util.ParseLabelSelector(r.URL.Query()["label"])
+	if err != nil {
+		api.SendBadRequestError(w, r, err.Error())
+		return
+	}
+
+	instanceStatus := s.instanceStatus()
+	instanceStatus.Cloning.Clones = filterClonesByLabels(instanceStatus.Cloning.Clones, labelSelector)
+
+	if err := api.WriteJSON(w, http.StatusOK, instanceStatus); err != nil {
+		api.SendError(w, r, err)
+		return
+	}
+}
+
+func (s *Server) getClones(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	offset, err := intQueryParam(values, "offset")
+	if err != nil {
+		api.SendBadRequestError(w, r, err.Error())
+		return
+	}
+
+	limit, err := intQueryParam(values, "limit")
+	if err != nil {
+		api.SendBadRequestError(w, r, err.Error())
+		return
+	}
+
+	listRequest := types.CloneListRequest{
+		Offset:     offset,
+		Limit:      limit,
+		SortBy:     values.Get("sort"),
+		Order:      values.Get("order"),
+		Status:     values.Get("status"),
+		SnapshotID: values.Get("snapshot_id"),
+		Pool:       values.Get("pool"),
+		Labels:     values["label"],
+	}
+
+	clonesPage, err := s.Cloning.ListClones(listRequest)
+	if err != nil {
+		var reqErr *models.Error
+		if errors.As(err, &reqErr) {
+			api.SendError(w, r, *reqErr)
+			return
+		}
+
+		api.SendError(w, r, errors.Wrap(err, "failed to list clones"))
+
+		return
+	}
+
+	if err = api.WriteJSON(w, http.StatusOK, clonesPage); err != nil {
+		api.SendError(w, r, err)
+		return
+	}
+}
+
+// intQueryParam parses an optional integer query parameter.
+func intQueryParam(values url.Values, param string) (int, error) {
+	if values.Get(param) == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.Atoi(values.Get(param))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %s", param, values.Get(param))
+	}
+
+	return value, nil
+}
+
+// boolQueryParam parses an optional boolean query parameter.
+func boolQueryParam(values url.Values, param string) (bool, error) {
+	if values.Get(param) == "" {
+		return false, nil
+	}
+
+	value, err := strconv.ParseBool(values.Get(param))
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %s", param, values.Get(param))
+	}
+
+	return value, nil
+}
+
+func (s *Server) getSnapshots(w http.ResponseWriter, r *http.Request) {
+	snapshots, err := s.Cloning.GetSnapshots()
+	if err != nil {
+		api.SendError(w, r, err)
+		return
+	}
+
+	if err = api.WriteJSON(w, http.StatusOK, snapshots); err != nil {
+		api.SendError(w, r, err)
+		return
+	}
+}
+
+func (s *Server) createSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshotJob, err := s.Retrieval.CreateSnapshot()
+	if err != nil {
+		var reqErr *models.Error
+		if errors.As(err, &reqErr) {
+			api.SendError(w, r, *reqErr)
+			return
+		}
+
+		api.SendError(w, r, errors.Wrap(err, "failed to create snapshot"))
+
+		return
+	}
+
+	if err := api.WriteJSON(w, http.StatusAccepted, snapshotJob); err != nil {
+		api.SendError(w, r, err)
+		return
+	}
+
+	log.Dbg(fmt.Sprintf("Snapshot job ID=%s has been started", snapshotJob.ID))
+}
+
+func (s *Server) destroySnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshotID := mux.Vars(r)["id"]
+
+	if snapshotID == "" {
+		api.SendBadRequestError(w, r, "ID must not be empty")
+		return
+	}
+
+	force, err := boolQueryParam(r.URL.Query(), "force")
+	if err != nil {
+		api.SendBadRequestError(w, r, err.Error())
+		return
+	}
+
+	if force {
+		if accessErr := s.authorizeDependentClones(r, snapshotID); accessErr != nil {
+			api.SendError(w, r, *accessErr)
+			return
+		}
+	}
+
+	if err := s.Cloning.DestroySnapshot(snapshotID, force); err != nil {
+		var reqErr *models.Error
+		if errors.As(err, &reqErr) {
+			api.SendError(w, r, *reqErr)
+			return
+		}
+
+		api.SendError(w, r, errors.Wrap(err, "failed to destroy snapshot"))
+
+		return
+	}
+
+	log.Dbg(fmt.Sprintf("Snapshot %s has been destroyed", snapshotID))
+}
+
+func (s *Server) patchSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshotID := mux.Vars(r)["id"]
+
+	if snapshotID == "" {
+		api.SendBadRequestError(w, r, "ID must not be empty")
+		return
+	}
+
+	var patchSnapshot types.SnapshotUpdateRequest
+	if err := api.ReadJSON(r, &patchSnapshot); err != nil {
+		api.SendBadRequestError(w, r, err.Error())
+
+		return
+	}
+
+	if err := s.validator.ValidateSnapshotUpdateRequest(&patchSnapshot); err != nil {
+		api.SendBadRequestError(w, r, err.Error())
+		return
+	}
+
+	updatedSnapshot, err := s.Cloning.UpdateSnapshot(snapshotID, patchSnapshot)
+	if err != nil {
+		var reqErr *models.Error
+		if errors.As(err, &reqErr) {
+			api.SendError(w, r, *reqErr)
+			return
+		}
+
+		api.SendError(w, r, errors.Wrap(err, "failed to update snapshot"))
+
+		return
+	}
+
+	if err := api.WriteJSON(w, http.StatusOK, updatedSnapshot); err != nil {
+		api.SendError(w, r, err)
+		return
+	}
+}
+
+func (s *Server) createClone(w http.ResponseWriter, r *http.Request) {
+	var cloneRequest *types.CloneCreateRequest
+	if err := api.ReadJSON(r, &cloneRequest); err != nil {
+		api.SendBadRequestError(w, r, err.Error())
+		return
+	}
+
+	if err := s.validator.ValidateCloneRequest(cloneRequest); err != nil {
+		api.SendBadRequestError(w, r, err.Error())
+		return
+	}
+
+	if err := s.validator.ValidateExtraConf(cloneRequest.ExtraConf, s.Cloning.AllowedSettings()); err != nil {
+		api.SendBadRequestError(w, r, err.Error())
+		return
+	}
+
+	owner := mw.IdentityFromContext(r.Context()).Name
+
+	newClone, operation, err := s.Cloning.CreateClone(cloneRequest, owner)
+	if err != nil {
+		var reqErr *models.Error
+		if errors.As(err, &reqErr) {
+			api.SendError(w, r, *reqErr)
+			return
+		}
+
+		api.SendError(w, r, errors.Wrap(err, "failed to create clone"))
+
+		return
+	}
+
+	mw.SetAuditTarget(r.Context(), newClone.ID)
+	w.Header().Set(operationIDHeader, operation.ID)
+
+	if err := api.WriteJSON(w, http.StatusCreated, newClone); err != nil {
+		api.SendError(w, r, err)
+		return
+	}
+
+	s.tm.SendEvent(context.Background(), telemetry.CloneCreatedEvent, telemetry.CloneCreated{
+		ID:          util.HashID(newClone.ID),
+		CloningTime: newClone.Metadata.CloningTime,
+		DSADiff:     util.GetDataFreshness(newClone.Snapshot.DataStateAt),
+	})
+
+	log.Dbg(fmt.Sprintf("Clone ID=%s is being created", newClone.ID))
+}
+
+func (s *Server) destroyClone(w http.ResponseWriter, r *http.Request) {
+	cloneID := mux.Vars(r)["id"]
+
+	if cloneID == "" {
+		api.SendBadRequestError(w, r, "ID must not be empty")
+		return
+	}
+
+	if accessErr := s.authorizeClone(r, cloneID); accessErr != nil {
+		api.SendError(w, r, *accessErr)
+		return
+	}
+
+	operation, err := s.Cloning.DestroyClone(cloneID)
+	if err != nil {
+		var reqErr *models.Error
+		if errors.As(err, &reqErr) {
+			api.SendError(w, r, *reqErr)
+			return
+		}
+
+		api.SendError(w, r, errors.Wrap(err, "failed to destroy clone"))
+
+		return
+	}
+
+	w.Header().Set(operationIDHeader, operation.ID)
+
+	if err := api.WriteJSON(w, http.StatusOK, operation); err != nil {
+		api.SendError(w, r, err)
+		return
+	}
+
+	s.tm.SendEvent(context.Background(), telemetry.CloneDestroyedEvent, telemetry.CloneDestroyed{
+		ID: util.HashID(cloneID),
+	})
+
+	log.Dbg(fmt.Sprintf("Clone ID=%s is being deleted", cloneID))
+}
+
+func (s *Server) patchClone(w http.ResponseWriter, r *http.Request) {
+	cloneID := mux.Vars(r)["id"]
+
+	if cloneID == "" {
+		api.SendBadRequestError(w, r, "ID must not be empty")
+		return
+	}
+
+	if accessErr := s.authorizeClone(r, cloneID); accessErr != nil {
+		api.SendError(w, r, *accessErr)
+		return
+	}
+
+	var patchClone types.CloneUpdateRequest
+	if err := api.ReadJSON(r, &patchClone); err != nil {
+		api.SendBadRequestError(w, r, err.Error())
+
+		return
+	}
+
+	if err := s.validator.ValidateUpdateRequest(&patchClone); err != nil {
+		api.SendBadRequestError(w, r, err.Error())
+		return
+	}
+
+	if patchClone.Owner != nil && !mw.IdentityFromContext(r.Context()).Admin {
+		api.SendError(w, r, models.Error{Code: models.ErrCodeForbidden, Message: "only admins are allowed to transfer clone ownership"})
+		return
+	}
+
+	updatedClone, err := s.Cloning.UpdateClone(cloneID, patchClone)
+	if err != nil {
+		api.SendError(w, r, errors.Wrap(err, "failed to update clone"))
+		return
+	}
+
+	if err := api.WriteJSON(w, http.StatusOK, updatedClone); err != nil {
+		api.SendError(w, r, err)
+		return
+	}
+}
+
+func (s *Server) extendClone(w http.ResponseWriter, r *http.Request) {
+	cloneID := mux.Vars(r)["id"]
+
+	if cloneID == "" {
+		api.SendBadRequestError(w, r, "ID must not be empty")
+		return
+	}
+
+	if accessErr := s.authorizeClone(r, cloneID); accessErr != nil {
+		api.SendError(w, r, *accessErr)
+		return
+	}
+
+	var extendRequest types.CloneExtendRequest
+	if err := api.ReadJSON(r, &extendRequest); err != nil {
+		api.SendBadRequestError(w, r, err.Error())
+		return
+	}
+
+	if err := s.validator.ValidateExtendRequest(&extendRequest); err != nil {
+		api.SendBadRequestError(w, r, err.Error())
+		return
+	}
+
+	extendedClone, err := s.Cloning.ExtendClone(cloneID, extendRequest)
+	if err != nil {
+		api.SendError(w, r, errors.Wrap(err, "failed to extend clone"))
+		return
+	}
+
+	if err := api.WriteJSON(w, http.StatusOK, extendedClone); err != nil {
+		api.SendError(w, r, err)
+		return
+	}
+
+	log.Dbg(fmt.Sprintf("Clone ID=%s has been extended until %s", cloneID, extendedClone.DeleteAt))
+}
+
+func (s *Server) snapshotClone(w http.ResponseWriter, r *http.Request) {
+	cloneID := mux.Vars(r)["id"]
+
+	if cloneID == "" {
+		api.SendBadRequestError(w, r, "ID must not be empty")
+		return
+	}
+
+	if accessErr := s.authorizeClone(r, cloneID); accessErr != nil {
+		api.SendError(w, r, *accessErr)
+		return
+	}
+
+	snapshot, err := s.Cloning.SnapshotClone(cloneID)
+	if err != nil {
+		var reqErr *models.Error
+		if errors.As(err, &reqErr) {
+			api.SendError(w, r, *reqErr)
+			return
+		}
+
+		api.SendError(w, r, errors.Wrap(err, "failed to create a snapshot of clone"))
+
+		return
+	}
+
+	if err := api.WriteJSON(w, http.StatusCreated, snapshot); err != nil {
+		api.SendError(w, r, err)
+		return
+	}
+
+	log.Dbg(fmt.Sprintf("Snapshot %s of clone ID=%s has been created", snapshot.ID, cloneID))
+}
+
+func (s *Server) touchClone(w http.ResponseWriter, r *http.Request) {
+	cloneID := mux.Vars(r)["id"]
+
+	if cloneID == "" {
+		api.SendBadRequestError(w, r, "ID must not be empty")
+		return
+	}
+
+	if accessErr := s.authorizeClone(r, cloneID); accessErr != nil {
+		api.SendError(w, r, *accessErr)
+		return
+	}
+
+	touchedClone, err := s.Cloning.TouchClone(cloneID)
+	if err != nil {
+		var reqErr *models.Error
+		if errors.As(err, &reqErr) {
+			api.SendError(w, r, *reqErr)
+			return
+		}
+
+		api.SendError(w, r, errors.Wrap(err, "failed to touch clone"))
+
+		return
+	}
+
+	if err := api.WriteJSON(w, http.StatusOK, touchedClone); err != nil {
+		api.SendError(w, r, err)
+		return
+	}
+
+	log.Dbg(fmt.Sprintf("Idle timer of clone ID=%s has been reset", cloneID))
+}
+
+func (s *Server) restartClone(w http.ResponseWriter, r *http.Request) {
+	cloneID := mux.Vars(r)["id"]
+
+	if cloneID == "" {
+		api.SendBadRequestError(w, r, "ID must not be empty")
+		return
+	}
+
+	if accessErr := s.authorizeClone(r, cloneID); accessErr != nil {
+		api.SendError(w, r, *accessErr)
+		return
+	}
+
+	operation, err := s.Cloning.RestartClone(cloneID)
+	if err != nil {
+		var reqErr *models.Error
+		if errors.As(err, &reqErr) {
+			api.SendError(w, r, *reqErr)
+			return
+		}
+
+		api.SendError(w, r, errors.Wrap(err, "failed to restart clone"))
+
+		return
+	}
+
+	w.Header().Set(operationIDHeader, operation.ID)
+
+	if err := api.WriteJSON(w, http.StatusOK, operation); err != nil {
+		api.SendError(w, r, err)
+		return
+	}
+
+	log.Dbg(fmt.Sprintf("Restart of clone ID=%s has been requested", cloneID))
+}
+
+func (s *Server) getCloneLogs(w http.ResponseWriter, r *http.Request) {
+	cloneID := mux.Vars(r)["id"]
+
+	if cloneID == "" {
+		api.SendBadRequestError(w, r, "ID must not be empty")
+		return
+	}
+
+	if accessErr := s.authorizeClone(r, cloneID); accessErr != nil {
+		api.SendError(w, r, *accessErr)
+		return
+	}
+
+	logOptions, err := parseLogOptions(r.URL.Query())
+	if err != nil {
+		api.SendBadRequestError(w, r, err.Error())
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	isStreaming := false
+
+	// The response status is sent along with the first log line, so errors occurred before it are still reported.
+	writeLine := func(line string) error {
+		if !isStreaming {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+
+			isStreaming = true
+		}
+
+		if _, err := fmt.Fprintln(w, s.Observer.MaskText(line)); err != nil {
+			return err
+		}
+
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		return nil
+	}
+
+	if err := s.Cloning.StreamCloneLogs(r.Context(), cloneID, logOptions, writeLine); err != nil {
+		if isStreaming {
+			log.Err("Failed to stream clone logs:", err)
+			return
+		}
+
+		var reqErr *models.Error
+		if errors.As(err, &reqErr) {
+			api.SendError(w, r, *reqErr)
+			return
+		}
+
+		api.SendError(w, r, errors.Wrap(err, "failed to get clone logs"))
+
+		return
+	}
+
+	if !isStreaming {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// parseLogOptions parses query parameters of a clone logs request.
+// The since parameter accepts either RFC3339 time or a duration relative to the current time, e.g. 15m.
+func parseLogOptions(values url.Values) (provision.LogOptions, error) {
+	logOptions := provision.LogOptions{
+		Source: provision.LogSource(values.Get("source")),
+	}
+
+	switch logOptions.Source {
+	case "":
+		logOptions.Source = provision.ContainerLogSource
+
+	case provision.ContainerLogSource, provision.CSVLogSource:
+
+	default:
+		return logOptions, errors.Errorf("invalid log source %q", logOptions.Source)
+	}
+
+	if since := values.Get("since"); since != "" {
+		sinceTime, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			duration, durationErr := time.ParseDuration(since)
+			if durationErr != nil || duration < 0 {
+				return logOptions, errors.Errorf("invalid since parameter %q", since)
+			}
+
+			sinceTime = time.Now().Add(-duration)
+		}
+
+		logOptions.Since = sinceTime
+	}
+
+	if tail := values.Get("tail"); tail != "" {
+		lines, err := strconv.Atoi(tail)
+		if err != nil || lines < 0 {
+			return logOptions, errors.Errorf("invalid tail parameter %q", tail)
+		}
+
+		logOptions.Tail = lines
+	}
+
+	if follow := values.Get("follow"); follow != "" {
+		isFollowing, err := strconv.ParseBool(follow)
+		if err != nil {
+			return logOptions, errors.Errorf("invalid follow parameter %q", follow)
+		}
+
+		logOptions.Follow = isFollowing
+	}
+
+	return logOptions, nil
+}
+
+func (s *Server) createCheckpoint(w http.ResponseWriter, r *http.Request) {
+	cloneID := mux.Vars(r)["id"]
+
+	if cloneID == "" {
+		api.SendBadRequestError(w, r, "ID must not be empty")
+		return
+	}
+
+	if accessErr := s.authorizeClone(r, cloneID); accessErr != nil {
+		api.SendError(w, r, *accessErr)
+		return
+	}
+
+	checkpoint, err := s.Cloning.CreateCheckpoint(cloneID)
+	if err != nil {
+		var reqErr *models.Error
+		if errors.As(err, &reqErr) {
+			api.SendError(w, r, *reqErr)
+			return
+		}
+
+		api.SendError(w, r, errors.Wrap(err, "failed to create a checkpoint of clone"))
+
+		return
+	}
+
+	if err := api.WriteJSON(w, http.StatusCreated, checkpoint); err != nil {
 		api.SendError(w, r, err)
 		return
 	}
+
+	log.Dbg(fmt.Sprintf("Checkpoint %s of clone ID=%s has been created", checkpoint.ID, cloneID))
 }
 
-func (s *Server) getSnapshots(w http.ResponseWriter, r *http.Request) {
-	snapshots, err := s.Cloning.GetSnapshots()
+func (s *Server) getCheckpoints(w http.ResponseWriter, r *http.Request) {
+	cloneID := mux.Vars(r)["id"]
+
+	if cloneID == "" {
+		api.SendBadRequestError(w, r, "ID must not be empty")
+		return
+	}
+
+	checkpoints, err := s.Cloning.ListCheckpoints(cloneID)
 	if err != nil {
-		api.SendError(w, r, err)
+		var reqErr *models.Error
+		if errors.As(err, &reqErr) {
+			api.SendError(w, r, *reqErr)
+			return
+		}
+
+		api.SendError(w, r, errors.Wrap(err, "failed to list checkpoints of clone"))
+
 		return
 	}
 
-	if err = api.WriteJSON(w, http.StatusOK, snapshots); err != nil {
+	if err := api.WriteJSON(w, http.StatusOK, checkpoints); err != nil {
 		api.SendError(w, r, err)
 		return
 	}
 }
 
-func (s *Server) createClone(w http.ResponseWriter, r *http.Request) {
-	var cloneRequest *types.CloneCreateRequest
-	if err := api.ReadJSON(r, &cloneRequest); err != nil {
-		api.SendBadRequestError(w, r, err.Error())
+func (s *Server) exportClone(w http.ResponseWriter, r *http.Request) {
+	cloneID := mux.Vars(r)["id"]
+
+	if cloneID == "" {
+		api.SendBadRequestError(w, r, "ID must not be empty")
 		return
 	}
 
-	if err := s.validator.ValidateCloneRequest(cloneRequest); err != nil {
-		api.SendBadRequestError(w, r, err.Error())
+	if accessErr := s.authorizeClone(r, cloneID); accessErr != nil {
+		api.SendError(w, r, *accessErr)
 		return
 	}
 
-	newClone, err := s.Cloning.CreateClone(cloneRequest)
+	var exportRequest types.CloneExportRequest
+
+	if r.Body != http.NoBody {
+		if err := json.NewDecoder(r.Body).Decode(&exportRequest); err != nil {
+			api.SendError(w, r, errors.Wrap(err, "failed to parse request parameters"))
+			return
+		}
+	}
+
+	operation, err := s.Cloning.ExportClone(cloneID, exportRequest)
 	if err != nil {
 		var reqErr *models.Error
 		if errors.As(err, &reqErr) {
-			api.SendBadRequestError(w, r, reqErr.Error())
+			api.SendError(w, r, *reqErr)
 			return
 		}
 
-		api.SendError(w, r, errors.Wrap(err, "failed to create clone"))
+		api.SendError(w, r, errors.Wrap(err, "failed to export clone"))
 
 		return
 	}
 
-	if err := api.WriteJSON(w, http.StatusCreated, newClone); err != nil {
+	w.Header().Set(operationIDHeader, operation.ID)
+
+	if err := api.WriteJSON(w, http.StatusOK, operation); err != nil {
 		api.SendError(w, r, err)
 		return
 	}
 
-	s.tm.SendEvent(context.Background(), telemetry.CloneCreatedEvent, telemetry.CloneCreated{
-		ID:          util.HashID(newClone.ID),
-		CloningTime: newClone.Metadata.CloningTime,
-		DSADiff:     util.GetDataFreshness(newClone.Snapshot.DataStateAt),
-	})
-
-	log.Dbg(fmt.Sprintf("Clone ID=%s is being created", newClone.ID))
+	log.Dbg(fmt.Sprintf("Export of clone ID=%s has been requested", cloneID))
 }
 
-func (s *Server) destroyClone(w http.ResponseWriter, r *http.Request) {
+func (s *Server) getCloneExports(w http.ResponseWriter, r *http.Request) {
 	cloneID := mux.Vars(r)["id"]
 
 	if cloneID == "" {
@@ -94,43 +746,124 @@ func (s *Server) destroyClone(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.Cloning.DestroyClone(cloneID); err != nil {
-		api.SendError(w, r, errors.Wrap(err, "failed to destroy clone"))
+	if accessErr := s.authorizeClone(r, cloneID); accessErr != nil {
+		api.SendError(w, r, *accessErr)
 		return
 	}
 
-	s.tm.SendEvent(context.Background(), telemetry.CloneDestroyedEvent, telemetry.CloneDestroyed{
-		ID: util.HashID(cloneID),
-	})
-
-	log.Dbg(fmt.Sprintf("Clone ID=%s is being deleted", cloneID))
+	if err := api.WriteJSON(w, http.StatusOK, s.Cloning.GetCloneExports(cloneID)); err != nil {
+		api.SendError(w, r, err)
+		return
+	}
 }
 
-func (s *Server) patchClone(w http.ResponseWriter, r *http.Request) {
+// downloadCloneExport sends the export artifact. Exports of the directory format are sent as a tar archive.
+func (s *Server) downloadCloneExport(w http.ResponseWriter, r *http.Request) {
 	cloneID := mux.Vars(r)["id"]
+	exportID := mux.Vars(r)["export_id"]
 
-	if cloneID == "" {
-		api.SendBadRequestError(w, r, "ID must not be empty")
+	if accessErr := s.authorizeClone(r, cloneID); accessErr != nil {
+		api.SendError(w, r, *accessErr)
 		return
 	}
 
-	var patchClone types.CloneUpdateRequest
-	if err := api.ReadJSON(r, &patchClone); err != nil {
-		api.SendBadRequestError(w, r, err.Error())
+	export, artifactPath, err := s.Cloning.GetExportArtifact(cloneID, exportID)
+	if err != nil {
+		var reqErr *models.Error
+		if errors.As(err, &reqErr) {
+			api.SendError(w, r, *reqErr)
+			return
+		}
+
+		api.SendError(w, r, errors.Wrap(err, "failed to get clone export"))
 
 		return
 	}
 
-	updatedClone, err := s.Cloning.UpdateClone(cloneID, patchClone)
-	if err != nil {
-		api.SendError(w, r, errors.Wrap(err, "failed to update clone"))
+	if export.Format != string(provision.DirectoryExportFormat) {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(artifactPath)))
+		http.ServeFile(w, r, artifactPath)
+
 		return
 	}
 
-	if err := api.WriteJSON(w, http.StatusOK, updatedClone); err != nil {
-		api.SendError(w, r, err)
+	w.Header().Set("Content-Type", "application/x-tar")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(artifactPath)+".tar"))
+
+	if err := writeTarArchive(w, artifactPath); err != nil {
+		log.Err("Failed to send clone export:", err)
+	}
+}
+
+func (s *Server) deleteCloneExport(w http.ResponseWriter, r *http.Request) {
+	cloneID := mux.Vars(r)["id"]
+	exportID := mux.Vars(r)["export_id"]
+
+	if accessErr := s.authorizeClone(r, cloneID); accessErr != nil {
+		api.SendError(w, r, *accessErr)
+		return
+	}
+
+	if err := s.Cloning.DeleteExport(cloneID, exportID); err != nil {
+		var reqErr *models.Error
+		if errors.As(err, &reqErr) {
+			api.SendError(w, r, *reqErr)
+			return
+		}
+
+		api.SendError(w, r, errors.Wrap(err, "failed to delete clone export"))
+
 		return
 	}
+
+	log.Dbg(fmt.Sprintf("Export %s of clone ID=%s has been deleted", exportID, cloneID))
+}
+
+// writeTarArchive writes files of the directory to the tar archive. Paths in the archive are relative to the directory.
+func writeTarArchive(w io.Writer, dir string) error {
+	tarWriter := tar.NewWriter(w)
+
+	err := filepath.Walk(dir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+
+		header.Name = filepath.ToSlash(relativePath)
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+
+		file, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			if err := file.Close(); err != nil {
+				log.Err("Failed to close the export file:", err)
+			}
+		}()
+
+		_, err = io.Copy(tarWriter, file)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tarWriter.Close()
 }
 
 func (s *Server) getClone(w http.ResponseWriter, r *http.Request) {
@@ -161,6 +894,11 @@ func (s *Server) resetClone(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if accessErr := s.authorizeClone(r, cloneID); accessErr != nil {
+		api.SendError(w, r, *accessErr)
+		return
+	}
+
 	var resetOptions types.ResetCloneRequest
 
 	if r.Body != http.NoBody {
@@ -175,8 +913,28 @@ func (s *Server) resetClone(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.Cloning.ResetClone(cloneID, resetOptions); err != nil {
+	if resetOptions.CheckpointID != "" && (resetOptions.Latest || resetOptions.SnapshotID != "") {
+		api.SendBadRequestError(w, r, "parameter `checkpoint ID` must not be specified together with `latest` or `snapshot ID`")
+		return
+	}
+
+	operation, err := s.Cloning.ResetClone(cloneID, resetOptions)
+	if err != nil {
+		var reqErr *models.Error
+		if errors.As(err, &reqErr) {
+			api.SendError(w, r, *reqErr)
+			return
+		}
+
 		api.SendError(w, r, errors.Wrap(err, "failed to reset clone"))
+
+		return
+	}
+
+	w.Header().Set(operationIDHeader, operation.ID)
+
+	if err := api.WriteJSON(w, http.StatusOK, operation); err != nil {
+		api.SendError(w, r, err)
 		return
 	}
 
@@ -303,6 +1061,148 @@ func (s *Server) runEstimator(ctx context.Context, ws *websocket.Conn, db pgxtyp
 	return nil
 }
 
+func (s *Server) getOperations(w http.ResponseWriter, r *http.Request) {
+	operations := s.Cloning.GetOperations(r.URL.Query().Get("clone_id"))
+
+	if err := api.WriteJSON(w, http.StatusOK, operations); err != nil {
+		api.SendError(w, r, err)
+		return
+	}
+}
+
+func (s *Server) getAuditLog(w http.ResponseWriter, r *http.Request) {
+	if !s.auditLog.IsAPIExposed() {
+		api.SendNotFoundError(w, r)
+		return
+	}
+
+	values := r.URL.Query()
+
+	limit, err := intQueryParam(values, "limit")
+	if err != nil {
+		api.SendBadRequestError(w, r, err.Error())
+		return
+	}
+
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+
+	entries, err := s.auditLog.Entries(audit.Filter{
+		User:   values.Get("user"),
+		Action: models.AuditAction(values.Get("action")),
+		Target: values.Get("target"),
+		Limit:  limit,
+	})
+	if err != nil {
+		api.SendError(w, r, errors.Wrap(err, "failed to read the audit log"))
+		return
+	}
+
+	if err := api.WriteJSON(w, http.StatusOK, entries); err != nil {
+		api.SendError(w, r, err)
+		return
+	}
+}
+
+func (s *Server) getOperation(w http.ResponseWriter, r *http.Request) {
+	operation, err := s.Cloning.GetOperation(mux.Vars(r)["id"])
+	if err != nil {
+		var reqErr *models.Error
+		if errors.As(err, &reqErr) {
+			api.SendError(w, r, *reqErr)
+			return
+		}
+
+		api.SendError(w, r, errors.Wrap(err, "failed to get operation"))
+
+		return
+	}
+
+	if err := api.WriteJSON(w, http.StatusOK, operation); err != nil {
+		api.SendError(w, r, err)
+		return
+	}
+}
+
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	cloneID := values.Get("clone_id")
+
+	eventTypes := make(map[models.EventType]struct{}, len(values["type"]))
+	for _, eventType := range values["type"] {
+		eventTypes[models.EventType(eventType)] = struct{}{}
+	}
+
+	ws, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already replied with an HTTP error.
+		log.Err("Failed to upgrade the event stream connection:", err)
+		return
+	}
+
+	defer func() {
+		if err := ws.Close(); err != nil {
+			log.Err(err)
+		}
+	}()
+
+	eventCh, cancel := s.broker.Subscribe()
+	defer cancel()
+
+	done := make(chan struct{})
+
+	go wsPing(ws, done)
+
+	// Read incoming messages to handle control frames and detect the closed connection.
+	go func() {
+		defer close(done)
+
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case event, ok := <-eventCh:
+			if !ok {
+				return
+			}
+
+			if !matchEvent(event, eventTypes, cloneID) {
+				continue
+			}
+
+			if err := ws.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+				log.Err(err)
+				return
+			}
+
+			if err := ws.WriteJSON(event); err != nil {
+				log.Dbg("Failed to send an event:", err)
+				return
+			}
+
+		case <-done:
+			return
+		}
+	}
+}
+
+// matchEvent checks whether the event passes the stream filters. Empty filters match all events.
+func matchEvent(event models.Event, eventTypes map[models.EventType]struct{}, cloneID string) bool {
+	if len(eventTypes) > 0 {
+		if _, ok := eventTypes[event.Type]; !ok {
+			return false
+		}
+	}
+
+	return cloneID == "" || event.CloneID == cloneID
+}
+
 func (s *Server) startObservation(w http.ResponseWriter, r *http.Request) {
 	if s.Platform.Client == nil {
 		api.SendBadRequestError(w, r, "cannot start the session observation because a Platform client is not configured")
@@ -315,6 +1215,13 @@ func (s *Server) startObservation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	mw.SetAuditTarget(r.Context(), observationRequest.CloneID)
+
+	if accessErr := s.authorizeClone(r, observationRequest.CloneID); accessErr != nil {
+		api.SendError(w, r, *accessErr)
+		return
+	}
+
 	clone, err := s.Cloning.GetClone(observationRequest.CloneID)
 	if err != nil {
 		api.SendNotFoundError(w, r)
@@ -390,6 +1297,13 @@ func (s *Server) stopObservation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	mw.SetAuditTarget(r.Context(), observationRequest.CloneID)
+
+	if accessErr := s.authorizeClone(r, observationRequest.CloneID); accessErr != nil {
+		api.SendError(w, r, *accessErr)
+		return
+	}
+
 	observingClone, err := s.Observer.GetObservingClone(observationRequest.CloneID)
 	if err != nil {
 		api.SendNotFoundError(w, r)
@@ -402,16 +1316,20 @@ func (s *Server) stopObservation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.Cloning.UpdateCloneStatus(observationRequest.CloneID, models.Status{Code: models.StatusExporting}); err != nil {
-		api.SendNotFoundError(w, r)
+	originalStatus, err := s.Cloning.MarkCloneExporting(observationRequest.CloneID)
+	if err != nil {
+		var reqErr *models.Error
+		if errors.As(err, &reqErr) {
+			api.SendError(w, r, *reqErr)
+			return
+		}
+
+		api.SendError(w, r, errors.Wrap(err, "failed to update clone status"))
+
 		return
 	}
 
-	defer func() {
-		if err := s.Cloning.UpdateCloneStatus(observationRequest.CloneID, models.Status{Code: models.StatusOK}); err != nil {
-			log.Err("failed to update clone status", err)
-		}
-	}()
+	defer s.Cloning.RestoreExportedCloneStatus(observationRequest.CloneID, originalStatus)
 
 	if observationRequest.OverallError {
 		// This is the single way to determine that an external migration command fails.
@@ -484,6 +1402,11 @@ func (s *Server) sessionSummaryObservation(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if accessErr := s.authorizeClone(r, cloneID); accessErr != nil {
+		api.SendError(w, r, *accessErr)
+		return
+	}
+
 	observingClone, err := s.Observer.GetObservingClone(cloneID)
 	if err != nil || !observingClone.IsExistArtifacts(sessionID) {
 		api.SendNotFoundError(w, r)
@@ -519,6 +1442,11 @@ func (s *Server) downloadArtifact(w http.ResponseWriter, r *http.Request) {
 
 	cloneID := values.Get("clone_id")
 
+	if accessErr := s.authorizeClone(r, cloneID); accessErr != nil {
+		api.SendError(w, r, *accessErr)
+		return
+	}
+
 	observingClone, err := s.Observer.GetObservingClone(cloneID)
 	if err != nil || !observingClone.IsExistArtifacts(sessionID) {
 		api.SendNotFoundError(w, r)
@@ -18,8 +18,10 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/pkg/errors"
 
+	"gitlab.com/postgres-ai/database-lab/v3/internal/audit"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/cloning"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/estimator"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/events"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/observer"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/platform"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/provision"
@@ -54,6 +56,9 @@ type Server struct {
 	docker      *client.Client
 	pm          *pool.Manager
 	tm          *telemetry.Agent
+	broker      *events.Broker
+	auditLog    *audit.Logger
+	auth        *mw.Auth
 	startedAt   *time.Time
 }
 
@@ -68,7 +73,9 @@ func NewServer(cfg *srvCfg.Config, globalCfg *global.Config,
 	observer *observer.Observer,
 	estimator *estimator.Estimator,
 	pm *pool.Manager,
-	tm *telemetry.Agent) *Server {
+	tm *telemetry.Agent,
+	broker *events.Broker,
+	auditLog *audit.Logger) *Server {
 	server := &Server{
 		Config:      cfg,
 		Global:      globalCfg,
@@ -83,6 +90,8 @@ func NewServer(cfg *srvCfg.Config, globalCfg *global.Config,
 		docker:      dockerClient,
 		pm:          pm,
 		tm:          tm,
+		broker:      broker,
+		auditLog:    auditLog,
 		startedAt:   pointer.ToTimeOrNil(time.Now().Truncate(time.Second)),
 	}
 
@@ -104,10 +113,11 @@ func (s *Server) instanceStatus() *models.InstanceStatus {
 		Cloning:     s.Cloning.GetCloningState(),
 		Provisioner: s.provisioner.ContainerOptions(),
 		Retrieving: models.Retrieving{
-			Mode:        s.Retrieval.State.Mode,
-			Status:      s.Retrieval.State.Status,
-			Alerts:      s.Retrieval.State.Alerts(),
-			LastRefresh: s.Retrieval.State.LastRefresh,
+			Mode:         s.Retrieval.State.Mode,
+			Status:       s.Retrieval.State.Status,
+			Alerts:       s.Retrieval.State.Alerts(),
+			LastRefresh:  s.Retrieval.State.LastRefresh,
+			SnapshotJobs: s.Retrieval.State.SnapshotJobs(),
 		},
 	}
 
@@ -120,6 +130,23 @@ func (s *Server) instanceStatus() *models.InstanceStatus {
 	return instanceStatus
 }
 
+// filterClonesByLabels returns clones matching the label selector.
+func filterClonesByLabels(clones []*models.Clone, labelSelector map[string]string) []*models.Clone {
+	if len(labelSelector) == 0 {
+		return clones
+	}
+
+	filteredClones := make([]*models.Clone, 0, len(clones))
+
+	for _, clone := range clones {
+		if util.MatchLabels(clone.Labels, labelSelector) {
+			filteredClones = append(filteredClones, clone)
+		}
+	}
+
+	return filteredClones
+}
+
 func (s *Server) summarizeStatus(instance *models.InstanceStatus) {
 	subsystems := []string{}
 	if instance.Retrieving.Status == models.Failed {
@@ -158,29 +185,71 @@ func attachAPI(r *mux.Router) error {
 	return nil
 }
 
+// IsValidConfig checks if the server configuration is valid.
+func IsValidConfig(cfg srvCfg.Config) error {
+	if err := mw.ValidateTokens(cfg.Tokens); err != nil {
+		return err
+	}
+
+	return mw.ValidateJWT(cfg.JWT)
+}
+
 // Reload reloads server configuration.
 func (s *Server) Reload(cfg srvCfg.Config) {
 	*s.Config = cfg
+
+	if s.auth == nil {
+		return
+	}
+
+	if err := s.auth.Reload(cfg); err != nil {
+		log.Err("Failed to reload API tokens:", err)
+	}
 }
 
 // InitHandlers initializes handler functions of the HTTP server.
-func (s *Server) InitHandlers() {
+func (s *Server) InitHandlers() error {
 	r := mux.NewRouter().StrictSlash(true)
 
-	authMW := mw.NewAuth(s.Config.VerificationToken, s.Platform)
-
-	r.HandleFunc("/status", authMW.Authorized(s.getInstanceStatus)).Methods(http.MethodGet)
-	r.HandleFunc("/snapshots", authMW.Authorized(s.getSnapshots)).Methods(http.MethodGet)
-	r.HandleFunc("/clone", authMW.Authorized(s.createClone)).Methods(http.MethodPost)
-	r.HandleFunc("/clone/{id}", authMW.Authorized(s.destroyClone)).Methods(http.MethodDelete)
-	r.HandleFunc("/clone/{id}", authMW.Authorized(s.patchClone)).Methods(http.MethodPatch)
-	r.HandleFunc("/clone/{id}", authMW.Authorized(s.getClone)).Methods(http.MethodGet)
-	r.HandleFunc("/clone/{id}/reset", authMW.Authorized(s.resetClone)).Methods(http.MethodPost)
-	r.HandleFunc("/clone/{id}", authMW.Authorized(s.getClone)).Methods(http.MethodGet)
-	r.HandleFunc("/observation/start", authMW.Authorized(s.startObservation)).Methods(http.MethodPost)
-	r.HandleFunc("/observation/stop", authMW.Authorized(s.stopObservation)).Methods(http.MethodPost)
-	r.HandleFunc("/observation/summary/{clone_id}/{session_id}", authMW.Authorized(s.sessionSummaryObservation)).Methods(http.MethodGet)
-	r.HandleFunc("/observation/download", authMW.Authorized(s.downloadArtifact)).Methods(http.MethodGet)
+	authMW, err := mw.NewAuth(*s.Config, s.Platform)
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize authorization")
+	}
+
+	s.auth = authMW
+	auditMW := mw.NewAudit(s.auditLog)
+
+	r.HandleFunc("/status", authMW.Authorized(mw.ScopeReadOnly, s.getInstanceStatus)).Methods(http.MethodGet)
+	r.HandleFunc("/snapshots", authMW.Authorized(mw.ScopeReadOnly, s.getSnapshots)).Methods(http.MethodGet)
+	r.HandleFunc("/snapshot", auditMW.Audited(models.AuditSnapshotCreate, authMW.Authorized(mw.ScopeSnapshotAdmin, s.createSnapshot))).Methods(http.MethodPost)
+	r.HandleFunc("/snapshot/{id:.+}", auditMW.Audited(models.AuditSnapshotDestroy, authMW.Authorized(mw.ScopeSnapshotAdmin, s.destroySnapshot))).Methods(http.MethodDelete)
+	r.HandleFunc("/snapshot/{id:.+}", auditMW.Audited(models.AuditSnapshotUpdate, authMW.Authorized(mw.ScopeSnapshotAdmin, s.patchSnapshot))).Methods(http.MethodPatch)
+	r.HandleFunc("/clones", authMW.Authorized(mw.ScopeReadOnly, s.getClones)).Methods(http.MethodGet)
+	r.HandleFunc("/clone", auditMW.Audited(models.AuditCloneCreate, authMW.Authorized(mw.ScopeCloneCreate, s.createClone))).Methods(http.MethodPost)
+	r.HandleFunc("/clone/{id}", auditMW.Audited(models.AuditCloneDestroy, authMW.Authorized(mw.ScopeCloneCreate, s.destroyClone))).Methods(http.MethodDelete)
+	r.HandleFunc("/clone/{id}", auditMW.Audited(models.AuditCloneUpdate, authMW.Authorized(mw.ScopeCloneCreate, s.patchClone))).Methods(http.MethodPatch)
+	r.HandleFunc("/clone/{id}", authMW.Authorized(mw.ScopeReadOnly, s.getClone)).Methods(http.MethodGet)
+	r.HandleFunc("/clone/{id}/reset", auditMW.Audited(models.AuditCloneReset, authMW.Authorized(mw.ScopeCloneCreate, s.resetClone))).Methods(http.MethodPost)
+	r.HandleFunc("/clone/{id}/extend", auditMW.Audited(models.AuditCloneExtend, authMW.Authorized(mw.ScopeCloneCreate, s.extendClone))).Methods(http.MethodPost)
+	r.HandleFunc("/clone/{id}/touch", auditMW.Audited(models.AuditCloneTouch, authMW.Authorized(mw.ScopeCloneCreate, s.touchClone))).Methods(http.MethodPost)
+	r.HandleFunc("/clone/{id}/restart", auditMW.Audited(models.AuditCloneRestart, authMW.Authorized(mw.ScopeCloneCreate, s.restartClone))).Methods(http.MethodPost)
+	r.HandleFunc("/clone/{id}/logs", authMW.Authorized(mw.ScopeReadOnly, s.getCloneLogs)).Methods(http.MethodGet)
+	r.HandleFunc("/clone/{id}/export", auditMW.Audited(models.AuditCloneExport, authMW.Authorized(mw.ScopeCloneCreate, s.exportClone))).Methods(http.MethodPost)
+	r.HandleFunc("/clone/{id}/exports", authMW.Authorized(mw.ScopeReadOnly, s.getCloneExports)).Methods(http.MethodGet)
+	r.HandleFunc("/clone/{id}/export/{export_id}", auditMW.Audited(models.AuditCloneExportDownload, authMW.Authorized(mw.ScopeCloneCreate, s.downloadCloneExport))).Methods(http.MethodGet)
+	r.HandleFunc("/clone/{id}/export/{export_id}", auditMW.Audited(models.AuditCloneExportDelete, authMW.Authorized(mw.ScopeCloneCreate, s.deleteCloneExport))).Methods(http.MethodDelete)
+	r.HandleFunc("/clone/{id}/snapshot", auditMW.Audited(models.AuditCloneSnapshot, authMW.Authorized(mw.ScopeSnapshotAdmin, s.snapshotClone))).Methods(http.MethodPost)
+	r.HandleFunc("/clone/{id}/checkpoint", auditMW.Audited(models.AuditCloneCheckpoint, authMW.Authorized(mw.ScopeCloneCreate, s.createCheckpoint))).Methods(http.MethodPost)
+	r.HandleFunc("/clone/{id}/checkpoints", authMW.Authorized(mw.ScopeReadOnly, s.getCheckpoints)).Methods(http.MethodGet)
+	r.HandleFunc("/clone/{id}", authMW.Authorized(mw.ScopeReadOnly, s.getClone)).Methods(http.MethodGet)
+	r.HandleFunc("/events", authMW.Authorized(mw.ScopeReadOnly, s.streamEvents)).Methods(http.MethodGet)
+	r.HandleFunc("/operations", authMW.Authorized(mw.ScopeReadOnly, s.getOperations)).Methods(http.MethodGet)
+	r.HandleFunc("/operations/{id}", authMW.Authorized(mw.ScopeReadOnly, s.getOperation)).Methods(http.MethodGet)
+	r.HandleFunc("/observation/start", auditMW.Audited(models.AuditObservationStart, authMW.Authorized(mw.ScopeObservation, s.startObservation))).Methods(http.MethodPost)
+	r.HandleFunc("/observation/stop", auditMW.Audited(models.AuditObservationStop, authMW.Authorized(mw.ScopeObservation, s.stopObservation))).Methods(http.MethodPost)
+	r.HandleFunc("/observation/summary/{clone_id}/{session_id}", authMW.Authorized(mw.ScopeObservation, s.sessionSummaryObservation)).Methods(http.MethodGet)
+	r.HandleFunc("/observation/download", authMW.Authorized(mw.ScopeObservation, s.downloadArtifact)).Methods(http.MethodGet)
+	r.HandleFunc("/audit", authMW.Authorized(mw.ScopeAudit, s.getAuditLog)).Methods(http.MethodGet)
 	r.HandleFunc("/estimate", s.startEstimator).Methods(http.MethodGet)
 
 	// Health check.
@@ -200,6 +269,8 @@ func (s *Server) InitHandlers() {
 	r.NotFoundHandler = http.HandlerFunc(api.SendNotFoundError)
 
 	s.httpSrv = &http.Server{Addr: fmt.Sprintf("%s:%d", s.Config.Host, s.Config.Port), Handler: mw.Logging(r)}
+
+	return nil
 }
 
 // Run starts HTTP server on specified port in configuration.
@@ -6,11 +6,31 @@
 package validator
 
 import (
+	"regexp"
+	"strings"
+	"time"
+
 	"github.com/pkg/errors"
 
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
 )
 
+// labelForbiddenChars contains characters used as separators in label selectors.
+const labelForbiddenChars = "=,"
+
+// lsnPattern defines the textual representation of a Postgres LSN, e.g. 16/B374D848.
+var lsnPattern = regexp.MustCompile(`^[0-9A-Fa-f]{1,8}/[0-9A-Fa-f]{1,8}$`)
+
+// settingNamePattern defines valid names of Postgres settings, including custom ones, e.g. auto_explain.log_min_duration.
+var settingNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(\.[A-Za-z_][A-Za-z0-9_$]*)?$`)
+
+// Bounds of clone container limits accepted by Docker.
+const (
+	minCPUQuota    = 1000
+	minBlkioWeight = 10
+	maxBlkioWeight = 1000
+)
+
 // Service provides a validation service.
 type Service struct {
 }
@@ -29,5 +49,152 @@ func (v Service) ValidateCloneRequest(cloneRequest *types.CloneCreateRequest) er
 		return errors.New("missing DB password")
 	}
 
+	if err := validateLabels(cloneRequest.Labels); err != nil {
+		return err
+	}
+
+	if err := validateRecoveryTarget(cloneRequest.RecoveryTarget); err != nil {
+		return err
+	}
+
+	if err := validateResources(cloneRequest.Resources); err != nil {
+		return err
+	}
+
+	return validateExpiration(cloneRequest.TTL, cloneRequest.DeleteAt)
+}
+
+// ValidateExtraConf validates Postgres settings requested for a clone.
+// If allowedSettings is not empty, only the listed settings may be defined. An empty list of values allows any value.
+func (v Service) ValidateExtraConf(extraConf map[string]string, allowedSettings map[string][]string) error {
+	for name, value := range extraConf {
+		if !settingNamePattern.MatchString(name) {
+			return errors.Errorf("invalid setting name %q", name)
+		}
+
+		if strings.ContainsAny(value, "\r\n") {
+			return errors.Errorf("value of setting %q must be a single line", name)
+		}
+
+		if len(allowedSettings) == 0 {
+			continue
+		}
+
+		allowedValues, ok := lookupSetting(allowedSettings, name)
+		if !ok {
+			return errors.Errorf("setting %q is not allowed", name)
+		}
+
+		if len(allowedValues) > 0 && !containsFold(allowedValues, value) {
+			return errors.Errorf("value %q of setting %q is not allowed", value, name)
+		}
+	}
+
+	return nil
+}
+
+// ValidateUpdateRequest validates a clone update request.
+func (v Service) ValidateUpdateRequest(updateRequest *types.CloneUpdateRequest) error {
+	return validateLabels(updateRequest.Labels)
+}
+
+// ValidateSnapshotUpdateRequest validates a snapshot update request.
+func (v Service) ValidateSnapshotUpdateRequest(updateRequest *types.SnapshotUpdateRequest) error {
+	if updateRequest.Description != nil && strings.ContainsAny(*updateRequest.Description, "\t\r\n") {
+		return errors.New("snapshot description must be a single line")
+	}
+
+	return validateLabels(updateRequest.Labels)
+}
+
+// ValidateExtendRequest validates a request to extend the clone lease.
+func (v Service) ValidateExtendRequest(extendRequest *types.CloneExtendRequest) error {
+	if extendRequest.TTL == 0 && extendRequest.DeleteAt == nil {
+		return errors.New("either TTL or expiration time must be specified")
+	}
+
+	return validateExpiration(extendRequest.TTL, extendRequest.DeleteAt)
+}
+
+func validateLabels(labels map[string]string) error {
+	for key, value := range labels {
+		if strings.TrimSpace(key) == "" {
+			return errors.New("label key must not be empty")
+		}
+
+		if strings.ContainsAny(key, labelForbiddenChars) || strings.ContainsAny(value, labelForbiddenChars) {
+			return errors.Errorf("label %q must not contain any of the characters %q", key, labelForbiddenChars)
+		}
+	}
+
+	return nil
+}
+
+func validateRecoveryTarget(target *types.RecoveryTargetRequest) error {
+	if target == nil {
+		return nil
+	}
+
+	if (target.Time == nil) == (target.LSN == "") {
+		return errors.New("either recovery target time or LSN must be specified")
+	}
+
+	if target.Time != nil && target.Time.After(time.Now()) {
+		return errors.New("recovery target time must be in the past")
+	}
+
+	if target.LSN != "" && !lsnPattern.MatchString(target.LSN) {
+		return errors.Errorf("invalid recovery target LSN %q", target.LSN)
+	}
+
+	return nil
+}
+
+func validateResources(limits *types.CloneResourcesRequest) error {
+	if limits == nil {
+		return nil
+	}
+
+	if limits.CPUQuota > 0 && limits.CPUQuota < minCPUQuota {
+		return errors.Errorf("CPU quota must be at least %d microseconds", minCPUQuota)
+	}
+
+	if limits.BlkioWeight > 0 && (limits.BlkioWeight < minBlkioWeight || limits.BlkioWeight > maxBlkioWeight) {
+		return errors.Errorf("block IO weight must be between %d and %d", minBlkioWeight, maxBlkioWeight)
+	}
+
+	return nil
+}
+
+// lookupSetting finds allowed values of the setting. Setting names are case-insensitive.
+func lookupSetting(settings map[string][]string, name string) ([]string, bool) {
+	for settingName, values := range settings {
+		if strings.EqualFold(settingName, name) {
+			return values, true
+		}
+	}
+
+	return nil, false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, allowedValue := range values {
+		if strings.EqualFold(allowedValue, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateExpiration(ttl uint, deleteAt *time.Time) error {
+	if ttl > 0 && deleteAt != nil {
+		return errors.New("TTL and expiration time must not be specified together")
+	}
+
+	if deleteAt != nil && deleteAt.Before(time.Now()) {
+		return errors.New("expiration time must be in the future")
+	}
+
 	return nil
 }
@@ -6,7 +6,9 @@ package validator
 
 import (
 	"testing"
+	"time"
 
+	"github.com/AlekSi/pointer"
 	"github.com/stretchr/testify/assert"
 
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
@@ -43,6 +45,80 @@ func TestValidationCloneRequestErrors(t *testing.T) {
 			createRequest: types.CloneCreateRequest{DB: &types.DatabaseRequest{Password: "password"}},
 			error:         "missing DB username",
 		},
+		{
+			createRequest: types.CloneCreateRequest{
+				DB:       &types.DatabaseRequest{Username: "user", Password: "password"},
+				TTL:      60,
+				DeleteAt: pointer.ToTime(time.Now().Add(time.Hour)),
+			},
+			error: "TTL and expiration time must not be specified together",
+		},
+		{
+			createRequest: types.CloneCreateRequest{
+				DB:       &types.DatabaseRequest{Username: "user", Password: "password"},
+				DeleteAt: pointer.ToTime(time.Now().Add(-time.Hour)),
+			},
+			error: "expiration time must be in the future",
+		},
+		{
+			createRequest: types.CloneCreateRequest{
+				DB:     &types.DatabaseRequest{Username: "user", Password: "password"},
+				Labels: map[string]string{"": "value"},
+			},
+			error: "label key must not be empty",
+		},
+		{
+			createRequest: types.CloneCreateRequest{
+				DB:     &types.DatabaseRequest{Username: "user", Password: "password"},
+				Labels: map[string]string{"team": "a,b"},
+			},
+			error: `label "team" must not contain any of the characters "=,"`,
+		},
+		{
+			createRequest: types.CloneCreateRequest{
+				DB:             &types.DatabaseRequest{Username: "user", Password: "password"},
+				RecoveryTarget: &types.RecoveryTargetRequest{},
+			},
+			error: "either recovery target time or LSN must be specified",
+		},
+		{
+			createRequest: types.CloneCreateRequest{
+				DB: &types.DatabaseRequest{Username: "user", Password: "password"},
+				RecoveryTarget: &types.RecoveryTargetRequest{
+					Time: pointer.ToTime(time.Now().Add(-time.Hour)),
+					LSN:  "16/B374D848",
+				},
+			},
+			error: "either recovery target time or LSN must be specified",
+		},
+		{
+			createRequest: types.CloneCreateRequest{
+				DB:             &types.DatabaseRequest{Username: "user", Password: "password"},
+				RecoveryTarget: &types.RecoveryTargetRequest{Time: pointer.ToTime(time.Now().Add(time.Hour))},
+			},
+			error: "recovery target time must be in the past",
+		},
+		{
+			createRequest: types.CloneCreateRequest{
+				DB:             &types.DatabaseRequest{Username: "user", Password: "password"},
+				RecoveryTarget: &types.RecoveryTargetRequest{LSN: "16-B374D848"},
+			},
+			error: `invalid recovery target LSN "16-B374D848"`,
+		},
+		{
+			createRequest: types.CloneCreateRequest{
+				DB:        &types.DatabaseRequest{Username: "user", Password: "password"},
+				Resources: &types.CloneResourcesRequest{CPUQuota: 500},
+			},
+			error: "CPU quota must be at least 1000 microseconds",
+		},
+		{
+			createRequest: types.CloneCreateRequest{
+				DB:        &types.DatabaseRequest{Username: "user", Password: "password"},
+				Resources: &types.CloneResourcesRequest{BlkioWeight: 2000},
+			},
+			error: "block IO weight must be between 10 and 1000",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -51,3 +127,72 @@ func TestValidationCloneRequestErrors(t *testing.T) {
 		assert.EqualError(t, err, tc.error)
 	}
 }
+
+func TestValidationExtraConf(t *testing.T) {
+	validator := Service{}
+
+	allowedSettings := map[string][]string{
+		"work_mem": {},
+		"fsync":    {"on"},
+	}
+
+	testCases := []struct {
+		extraConf       map[string]string
+		allowedSettings map[string][]string
+		error           string
+	}{
+		{extraConf: map[string]string{"fsync": "off", "shared_buffers": "1GB"}},
+		{extraConf: map[string]string{"auto_explain.log_min_duration": "100ms"}},
+		{extraConf: map[string]string{"Work_Mem": "64MB", "fsync": "ON"}, allowedSettings: allowedSettings},
+		{extraConf: map[string]string{"work mem": "64MB"}, error: `invalid setting name "work mem"`},
+		{
+			extraConf: map[string]string{"work_mem": "64MB\nfsync = off"},
+			error:     `value of setting "work_mem" must be a single line`,
+		},
+		{
+			extraConf:       map[string]string{"shared_buffers": "1GB"},
+			allowedSettings: allowedSettings,
+			error:           `setting "shared_buffers" is not allowed`,
+		},
+		{
+			extraConf:       map[string]string{"fsync": "off"},
+			allowedSettings: allowedSettings,
+			error:           `value "off" of setting "fsync" is not allowed`,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := validator.ValidateExtraConf(tc.extraConf, tc.allowedSettings)
+
+		if tc.error == "" {
+			assert.NoError(t, err)
+			continue
+		}
+
+		assert.EqualError(t, err, tc.error)
+	}
+}
+
+func TestValidationExtendRequest(t *testing.T) {
+	validator := Service{}
+
+	assert.NoError(t, validator.ValidateExtendRequest(&types.CloneExtendRequest{TTL: 30}))
+	assert.NoError(t, validator.ValidateExtendRequest(&types.CloneExtendRequest{DeleteAt: pointer.ToTime(time.Now().Add(time.Hour))}))
+	assert.EqualError(t, validator.ValidateExtendRequest(&types.CloneExtendRequest{}),
+		"either TTL or expiration time must be specified")
+}
+
+func TestValidationSnapshotUpdateRequest(t *testing.T) {
+	validator := Service{}
+
+	err := validator.ValidateSnapshotUpdateRequest(&types.SnapshotUpdateRequest{
+		Description: pointer.ToString("pre-release data"),
+		Labels:      map[string]string{"stage": "pre-release"},
+	})
+	assert.Nil(t, err)
+
+	err = validator.ValidateSnapshotUpdateRequest(&types.SnapshotUpdateRequest{
+		Description: pointer.ToString("pre-release\ndata"),
+	})
+	assert.EqualError(t, err, "snapshot description must be a single line")
+}
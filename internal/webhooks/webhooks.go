@@ -0,0 +1,219 @@
+/*
+2022 © Postgres.ai
+*/
+
+// Package webhooks delivers engine events to external HTTP endpoints.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/events"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+const (
+	// EventHeader defines the header containing the webhook event name.
+	EventHeader = "DBLab-Event"
+
+	// SignatureHeader defines the header containing the HMAC-SHA256 signature of the request body.
+	SignatureHeader = "DBLab-Signature"
+
+	signaturePrefix = "sha256="
+
+	defaultRetryInterval = 5 * time.Second
+	requestTimeout       = 10 * time.Second
+)
+
+// Config defines configuration of outgoing webhooks.
+type Config struct {
+	Hooks         []Hook        `yaml:"hooks"`
+	MaxRetries    uint          `yaml:"maxRetries"`
+	RetryInterval time.Duration `yaml:"retryInterval"`
+}
+
+// Hook defines an endpoint receiving events.
+type Hook struct {
+	URL     string   `yaml:"url"`
+	Secret  string   `yaml:"secret"`
+	Trigger []string `yaml:"trigger"`
+}
+
+// Payload defines the body of a webhook request.
+type Payload struct {
+	Event      string       `json:"event"`
+	InstanceID string       `json:"instanceId"`
+	Data       models.Event `json:"data"`
+}
+
+// Service delivers events to the configured webhooks.
+type Service struct {
+	mu         sync.RWMutex
+	cfg        Config
+	instanceID string
+	broker     *events.Broker
+	client     *http.Client
+}
+
+// New creates a new webhook service.
+func New(cfg Config, instanceID string, broker *events.Broker) *Service {
+	return &Service{
+		cfg:        cfg,
+		instanceID: instanceID,
+		broker:     broker,
+		client:     &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Reload reloads webhook configuration.
+func (s *Service) Reload(cfg Config) {
+	s.mu.Lock()
+	s.cfg = cfg
+	s.mu.Unlock()
+}
+
+// Run listens to engine events and delivers them until the context is canceled.
+func (s *Service) Run(ctx context.Context) {
+	eventCh, cancel := s.broker.Subscribe()
+	defer cancel()
+
+	for {
+		select {
+		case event, ok := <-eventCh:
+			if !ok {
+				return
+			}
+
+			s.dispatch(ctx, event)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// dispatch starts the event delivery to the hooks subscribed to it.
+func (s *Service) dispatch(ctx context.Context, event models.Event) {
+	s.mu.RLock()
+	cfg := s.cfg
+	s.mu.RUnlock()
+
+	name := eventName(event)
+
+	for _, hook := range cfg.Hooks {
+		if !hook.isTriggered(name) {
+			continue
+		}
+
+		go func(hook Hook) {
+			if err := s.deliver(ctx, cfg, hook, name, event); err != nil {
+				log.Err(fmt.Sprintf("Failed to deliver webhook %q to %s: %v", name, hook.URL, err))
+			}
+		}(hook)
+	}
+}
+
+// deliver sends the event to the hook retrying failed attempts with exponential backoff.
+func (s *Service) deliver(ctx context.Context, cfg Config, hook Hook, name string, event models.Event) error {
+	body, err := json.Marshal(Payload{
+		Event:      name,
+		InstanceID: s.instanceID,
+		Data:       event,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to encode payload")
+	}
+
+	retryInterval := cfg.RetryInterval
+	if retryInterval == 0 {
+		retryInterval = defaultRetryInterval
+	}
+
+	for attempt := uint(0); ; attempt++ {
+		err = s.send(ctx, hook, name, body)
+		if err == nil || attempt >= cfg.MaxRetries {
+			return err
+		}
+
+		log.Dbg(fmt.Sprintf("Webhook %q to %s failed, retrying in %v: %v", name, hook.URL, retryInterval, err))
+
+		select {
+		case <-time.After(retryInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		retryInterval *= 2
+	}
+}
+
+func (s *Service) send(ctx context.Context, hook Hook, name string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to make a request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, name)
+
+	if hook.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(hook.Secret, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send a request")
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf("unexpected response status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Sign calculates the signature of the webhook body, which receivers use to verify the sender.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// isTriggered checks whether the hook is subscribed to the event. Hooks without triggers receive all events.
+func (h Hook) isTriggered(name string) bool {
+	if len(h.Trigger) == 0 {
+		return true
+	}
+
+	for _, trigger := range h.Trigger {
+		if trigger == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// eventName returns the webhook event name. Alerts are named after their types, e.g. refresh_failed.
+func eventName(event models.Event) string {
+	if event.Type == models.EventAlert && event.Status != "" {
+		return event.Status
+	}
+
+	return string(event.Type)
+}
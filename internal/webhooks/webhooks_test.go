@@ -0,0 +1,110 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/internal/events"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+func TestWebhookDelivery(t *testing.T) {
+	const secret = "webhook_secret"
+
+	var attempts int32
+
+	received := make(chan Payload, 1)
+
+	hookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, Sign(secret, body), r.Header.Get(SignatureHeader))
+		assert.Equal(t, "refresh_failed", r.Header.Get(EventHeader))
+
+		// Fail the first attempt to check retries.
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		var payload Payload
+
+		require.NoError(t, json.Unmarshal(body, &payload))
+
+		select {
+		case received <- payload:
+		default:
+		}
+	}))
+	defer hookServer.Close()
+
+	broker := events.NewBroker()
+
+	svc := New(Config{
+		Hooks: []Hook{
+			{URL: hookServer.URL, Secret: secret, Trigger: []string{"refresh_failed"}},
+		},
+		MaxRetries:    2,
+		RetryInterval: time.Millisecond,
+	}, "instanceID", broker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go svc.Run(ctx)
+
+	// Wait for the service subscription.
+	require.Eventually(t, func() bool {
+		broker.Publish(models.Event{Type: models.EventCloneCreated, CloneID: "clone"})
+		broker.Publish(models.Event{Type: models.EventAlert, Status: string(models.RefreshFailed), Message: "failed"})
+
+		select {
+		case payload := <-received:
+			assert.Equal(t, "refresh_failed", payload.Event)
+			assert.Equal(t, "instanceID", payload.InstanceID)
+			assert.Equal(t, "failed", payload.Data.Message)
+
+			return true
+		case <-time.After(50 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&attempts), int32(2))
+}
+
+func TestWebhookDeliveryFailure(t *testing.T) {
+	var attempts int32
+
+	hookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer hookServer.Close()
+
+	cfg := Config{MaxRetries: 2, RetryInterval: time.Millisecond}
+	svc := New(cfg, "instanceID", events.NewBroker())
+
+	err := svc.deliver(context.Background(), cfg, Hook{URL: hookServer.URL}, "clone_created", models.Event{})
+	require.EqualError(t, err, "unexpected response status: 502")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+func TestHookTrigger(t *testing.T) {
+	assert.True(t, Hook{}.isTriggered("clone_created"))
+	assert.True(t, Hook{Trigger: []string{"clone_reset", "clone_created"}}.isTriggered("clone_created"))
+	assert.False(t, Hook{Trigger: []string{"clone_reset"}}.isTriggered("clone_created"))
+
+	assert.Equal(t, "refresh_skipped", eventName(models.Event{Type: models.EventAlert, Status: string(models.RefreshSkipped)}))
+	assert.Equal(t, "snapshot_created", eventName(models.Event{Type: models.EventSnapshotCreated}))
+}
@@ -12,6 +12,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -23,7 +24,13 @@ import (
 
 // ListClones provides a list of Database Lab clones.
 func (c *Client) ListClones(ctx context.Context) ([]*models.Clone, error) {
-	body, err := c.ListClonesRaw(ctx)
+	return c.ListClonesBySelector(ctx, nil)
+}
+
+// ListClonesBySelector provides a list of Database Lab clones matching the label selector.
+// The selector contains label requirements in the "key=value" format.
+func (c *Client) ListClonesBySelector(ctx context.Context, selector []string) ([]*models.Clone, error) {
+	body, err := c.ListClonesRawBySelector(ctx, selector)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get response")
 	}
@@ -41,8 +48,23 @@ func (c *Client) ListClones(ctx context.Context) ([]*models.Clone, error) {
 
 // ListClonesRaw provides a raw list of Database Lab clones.
 func (c *Client) ListClonesRaw(ctx context.Context) (io.ReadCloser, error) {
+	return c.ListClonesRawBySelector(ctx, nil)
+}
+
+// ListClonesRawBySelector provides a raw list of Database Lab clones matching the label selector.
+func (c *Client) ListClonesRawBySelector(ctx context.Context, selector []string) (io.ReadCloser, error) {
 	u := c.URL("/status")
 
+	if len(selector) > 0 {
+		values := url.Values{}
+
+		for _, requirement := range selector {
+			values.Add("label", requirement)
+		}
+
+		u.RawQuery = values.Encode()
+	}
+
 	request, err := http.NewRequest(http.MethodGet, u.String(), nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to make a request")
@@ -56,6 +78,73 @@ func (c *Client) ListClonesRaw(ctx context.Context) (io.ReadCloser, error) {
 	return response.Body, nil
 }
 
+// ListClonesPage provides a filtered and sorted page of Database Lab clones.
+func (c *Client) ListClonesPage(ctx context.Context, listRequest types.CloneListRequest) (*models.ClonesPage, error) {
+	body, err := c.ListClonesPageRaw(ctx, listRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get response")
+	}
+
+	defer func() { _ = body.Close() }()
+
+	var clonesPage models.ClonesPage
+
+	if err := json.NewDecoder(body).Decode(&clonesPage); err != nil {
+		return nil, errors.Wrap(err, "failed to decode a response body")
+	}
+
+	return &clonesPage, nil
+}
+
+// ListClonesPageRaw provides a filtered and sorted page of Database Lab clones in raw format.
+func (c *Client) ListClonesPageRaw(ctx context.Context, listRequest types.CloneListRequest) (io.ReadCloser, error) {
+	u := c.URL("/clones")
+	u.RawQuery = cloneListValues(listRequest).Encode()
+
+	request, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make a request")
+	}
+
+	response, err := c.Do(ctx, request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get response")
+	}
+
+	return response.Body, nil
+}
+
+// cloneListValues builds query parameters of the clone list request.
+func cloneListValues(listRequest types.CloneListRequest) url.Values {
+	values := url.Values{}
+
+	if listRequest.Offset > 0 {
+		values.Set("offset", strconv.Itoa(listRequest.Offset))
+	}
+
+	if listRequest.Limit > 0 {
+		values.Set("limit", strconv.Itoa(listRequest.Limit))
+	}
+
+	for param, value := range map[string]string{
+		"sort":        listRequest.SortBy,
+		"order":       listRequest.Order,
+		"status":      listRequest.Status,
+		"snapshot_id": listRequest.SnapshotID,
+		"pool":        listRequest.Pool,
+	} {
+		if value != "" {
+			values.Set(param, value)
+		}
+	}
+
+	for _, requirement := range listRequest.Labels {
+		values.Add("label", requirement)
+	}
+
+	return values
+}
+
 // GetClone returns info about a Database Lab clone.
 func (c *Client) GetClone(ctx context.Context, cloneID string) (*models.Clone, error) {
 	body, err := c.GetCloneRaw(ctx, cloneID)
@@ -215,6 +304,112 @@ func (c *Client) UpdateClone(ctx context.Context, cloneID string, updateRequest
 	return &clone, nil
 }
 
+// ExtendClone extends the lease of a Database Lab clone.
+func (c *Client) ExtendClone(ctx context.Context, cloneID string, extendRequest types.CloneExtendRequest) (*models.Clone, error) {
+	u := c.URL(fmt.Sprintf("/clone/%s/extend", cloneID))
+
+	var clone models.Clone
+
+	err := c.request(ctx, u, extendRequest, &clone)
+
+	return &clone, err
+}
+
+// TouchClone resets the idle timer of a Database Lab clone.
+func (c *Client) TouchClone(ctx context.Context, cloneID string) (*models.Clone, error) {
+	u := c.URL(fmt.Sprintf("/clone/%s/touch", cloneID))
+
+	var clone models.Clone
+
+	if err := c.request(ctx, u, nil, &clone); err != nil {
+		return nil, err
+	}
+
+	return &clone, nil
+}
+
+// RestartClone restarts the clone container and waits until Postgres of the clone is ready.
+func (c *Client) RestartClone(ctx context.Context, cloneID string) (*models.Clone, error) {
+	if _, err := c.RestartCloneAsync(ctx, cloneID); err != nil {
+		return nil, err
+	}
+
+	clone, err := c.watchCloneStatus(ctx, cloneID, models.StatusRestarting)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to watch the clone status")
+	}
+
+	if clone.Status.Code != models.StatusOK {
+		return nil, errors.Errorf("unexpected clone status given: %v", clone.Status)
+	}
+
+	return clone, nil
+}
+
+// RestartCloneAsync asynchronously restarts the clone container and returns the operation tracking the restart.
+func (c *Client) RestartCloneAsync(ctx context.Context, cloneID string) (*models.Operation, error) {
+	u := c.URL(fmt.Sprintf("/clone/%s/restart", cloneID))
+
+	var operation models.Operation
+
+	if err := c.request(ctx, u, nil, &operation); err != nil {
+		return nil, err
+	}
+
+	return &operation, nil
+}
+
+// SnapshotClone creates a snapshot of a Database Lab clone, which can be used to create new clones.
+func (c *Client) SnapshotClone(ctx context.Context, cloneID string) (*models.Snapshot, error) {
+	u := c.URL(fmt.Sprintf("/clone/%s/snapshot", cloneID))
+
+	var snapshot models.Snapshot
+
+	if err := c.request(ctx, u, nil, &snapshot); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// CreateCheckpoint takes a checkpoint of the clone data, which the clone can be reset to.
+func (c *Client) CreateCheckpoint(ctx context.Context, cloneID string) (*models.Checkpoint, error) {
+	u := c.URL(fmt.Sprintf("/clone/%s/checkpoint", cloneID))
+
+	var checkpoint models.Checkpoint
+
+	if err := c.request(ctx, u, nil, &checkpoint); err != nil {
+		return nil, err
+	}
+
+	return &checkpoint, nil
+}
+
+// ListCheckpoints returns checkpoints of the clone.
+func (c *Client) ListCheckpoints(ctx context.Context, cloneID string) ([]models.Checkpoint, error) {
+	u := c.URL(fmt.Sprintf("/clone/%s/checkpoints", cloneID))
+
+	request, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make a request")
+	}
+
+	response, err := c.Do(ctx, request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get response")
+	}
+
+	defer func() { _ = response.Body.Close() }()
+
+	var checkpoints []models.Checkpoint
+
+	if err := json.NewDecoder(response.Body).Decode(&checkpoints); err != nil {
+		return nil, errors.Wrap(err, "failed to decode a response body")
+	}
+
+	return checkpoints, nil
+}
+
 // ResetClone resets a Database Lab clone session.
 func (c *Client) ResetClone(ctx context.Context, cloneID string, params types.ResetCloneRequest) error {
 	u := c.URL(fmt.Sprintf("/clone/%s/reset", cloneID))
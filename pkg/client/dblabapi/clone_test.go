@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/AlekSi/pointer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -65,6 +66,86 @@ func TestClientListClones(t *testing.T) {
 	assert.EqualValues(t, expectedClones, cloneList)
 }
 
+func TestClientListClonesBySelector(t *testing.T) {
+	expectedClones := []*models.Clone{{
+		ID:     "testCloneID",
+		Labels: map[string]string{"team": "analytics", "pr": "1234"},
+	}}
+
+	mockClient := NewTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, req.URL.String(), "https://example.com/status?label=team%3Danalytics&label=pr%3D1234")
+
+		// Prepare response.
+		body, err := json.Marshal(models.InstanceStatus{Cloning: models.Cloning{Clones: expectedClones}})
+		require.NoError(t, err)
+
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewBuffer(body)),
+			Header:     make(http.Header),
+		}
+	})
+
+	c, err := NewClient(Options{
+		Host:              "https://example.com/",
+		VerificationToken: "token",
+	})
+	require.NoError(t, err)
+
+	c.client = mockClient
+
+	// Send a request.
+	cloneList, err := c.ListClonesBySelector(context.Background(), []string{"team=analytics", "pr=1234"})
+	require.NoError(t, err)
+
+	assert.EqualValues(t, expectedClones, cloneList)
+}
+
+func TestClientListClonesPage(t *testing.T) {
+	expectedPage := &models.ClonesPage{
+		Clones: []*models.Clone{{ID: "testCloneID", Status: models.Status{Code: models.StatusOK}}},
+		Total:  12,
+		Offset: 10,
+		Limit:  10,
+	}
+
+	mockClient := NewTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, req.URL.String(),
+			"https://example.com/clones?label=team%3Danalytics&limit=10&offset=10&order=asc&sort=diffSize&status=OK")
+
+		// Prepare response.
+		body, err := json.Marshal(expectedPage)
+		require.NoError(t, err)
+
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewBuffer(body)),
+			Header:     make(http.Header),
+		}
+	})
+
+	c, err := NewClient(Options{
+		Host:              "https://example.com/",
+		VerificationToken: "token",
+	})
+	require.NoError(t, err)
+
+	c.client = mockClient
+
+	// Send a request.
+	clonesPage, err := c.ListClonesPage(context.Background(), types.CloneListRequest{
+		Offset: 10,
+		Limit:  10,
+		SortBy: types.SortByDiffSize,
+		Order:  types.OrderAsc,
+		Status: string(models.StatusOK),
+		Labels: []string{"team=analytics"},
+	})
+	require.NoError(t, err)
+
+	assert.EqualValues(t, expectedPage, clonesPage)
+}
+
 func TestClientListClonesWithFailedRequest(t *testing.T) {
 	mockClient := NewTestClient(func(r *http.Request) *http.Response {
 		return &http.Response{
@@ -356,7 +437,8 @@ func TestClientUpdateClone(t *testing.T) {
 		err = json.Unmarshal(requestBody, &updateRequest)
 		require.NoError(t, err)
 
-		cloneModel.Protected = updateRequest.Protected
+		require.NotNil(t, updateRequest.Protected)
+		cloneModel.Protected = *updateRequest.Protected
 
 		// Prepare response.
 		responseBody, err := json.Marshal(cloneModel)
@@ -379,7 +461,7 @@ func TestClientUpdateClone(t *testing.T) {
 
 	// Send a request.
 	newClone, err := c.UpdateClone(context.Background(), cloneModel.ID, types.CloneUpdateRequest{
-		Protected: false,
+		Protected: pointer.ToBool(false),
 	})
 	require.NoError(t, err)
 
@@ -416,6 +498,57 @@ func TestClientUpdateCloneWithFailedRequest(t *testing.T) {
 	require.Nil(t, clone)
 }
 
+func TestClientExtendClone(t *testing.T) {
+	cloneModel := &models.Clone{
+		ID:        "testCloneID",
+		CreatedAt: "2020-01-10 00:00:00 UTC",
+		Status: models.Status{
+			Code:    "OK",
+			Message: "Instance is ready",
+		},
+	}
+
+	mockClient := NewTestClient(func(r *http.Request) *http.Response {
+		assert.Equal(t, r.URL.String(), "https://example.com/clone/testCloneID/extend")
+		assert.Equal(t, r.Method, http.MethodPost)
+
+		requestBody, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		defer func() { _ = r.Body.Close() }()
+
+		extendRequest := types.CloneExtendRequest{}
+		err = json.Unmarshal(requestBody, &extendRequest)
+		require.NoError(t, err)
+		assert.Equal(t, uint(60), extendRequest.TTL)
+
+		cloneModel.DeleteAt = "2020-01-10 01:00:00 UTC"
+
+		// Prepare response.
+		responseBody, err := json.Marshal(cloneModel)
+		require.NoError(t, err)
+
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewBuffer(responseBody)),
+			Header:     make(http.Header),
+		}
+	})
+
+	c, err := NewClient(Options{
+		Host:              "https://example.com/",
+		VerificationToken: "token",
+	})
+	require.NoError(t, err)
+
+	c.client = mockClient
+
+	// Send a request.
+	extendedClone, err := c.ExtendClone(context.Background(), cloneModel.ID, types.CloneExtendRequest{TTL: 60})
+	require.NoError(t, err)
+
+	assert.EqualValues(t, cloneModel, extendedClone)
+}
+
 func TestClientDestroyClone(t *testing.T) {
 	mockClient := NewTestClient(func(r *http.Request) *http.Response {
 		assert.Equal(t, r.URL.String(), "https://example.com/clone/testCloneID")
@@ -609,3 +742,199 @@ func TestClientResetCloneWithFailedRequest(t *testing.T) {
 	err = c.ResetClone(context.Background(), "testCloneID", types.ResetCloneRequest{Latest: true, SnapshotID: "test"})
 	assert.EqualError(t, err, `failed to get response: Check your verification token.`)
 }
+
+func TestClientTouchClone(t *testing.T) {
+	expectedClone := &models.Clone{
+		ID: "testCloneID",
+		Status: models.Status{
+			Code:    models.StatusOK,
+			Message: models.CloneMessageOK,
+		},
+	}
+
+	mockClient := NewTestClient(func(r *http.Request) *http.Response {
+		assert.Equal(t, r.URL.String(), "https://example.com/clone/testCloneID/touch")
+		assert.Equal(t, r.Method, http.MethodPost)
+
+		// Prepare response.
+		responseBody, err := json.Marshal(expectedClone)
+		require.NoError(t, err)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBuffer(responseBody)),
+			Header:     make(http.Header),
+		}
+	})
+
+	c, err := NewClient(Options{
+		Host:              "https://example.com/",
+		VerificationToken: "token",
+	})
+	require.NoError(t, err)
+
+	c.client = mockClient
+
+	// Send a request.
+	clone, err := c.TouchClone(context.Background(), "testCloneID")
+	require.NoError(t, err)
+
+	assert.EqualValues(t, expectedClone, clone)
+}
+
+func TestClientRestartCloneAsync(t *testing.T) {
+	expectedOperation := &models.Operation{
+		ID:        "testOperationID",
+		Type:      models.OperationRestartClone,
+		CloneID:   "testCloneID",
+		Status:    models.OperationRunning,
+		Step:      "restarting clone container",
+		StartedAt: time.Date(2021, 1, 10, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2021, 1, 10, 0, 0, 0, 0, time.UTC),
+	}
+
+	mockClient := NewTestClient(func(r *http.Request) *http.Response {
+		assert.Equal(t, r.URL.String(), "https://example.com/clone/testCloneID/restart")
+		assert.Equal(t, r.Method, http.MethodPost)
+
+		// Prepare response.
+		responseBody, err := json.Marshal(expectedOperation)
+		require.NoError(t, err)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBuffer(responseBody)),
+			Header:     make(http.Header),
+		}
+	})
+
+	c, err := NewClient(Options{
+		Host:              "https://example.com/",
+		VerificationToken: "token",
+	})
+	require.NoError(t, err)
+
+	c.client = mockClient
+
+	// Send a request.
+	operation, err := c.RestartCloneAsync(context.Background(), "testCloneID")
+	require.NoError(t, err)
+
+	assert.EqualValues(t, expectedOperation, operation)
+}
+
+func TestClientSnapshotClone(t *testing.T) {
+	expectedSnapshot := &models.Snapshot{
+		ID:          "dblab_pool/dblab_clone_6000@snapshot_20200110000000",
+		CreatedAt:   "2020-01-10 00:00:00 UTC",
+		DataStateAt: "2020-01-10 00:00:00 UTC",
+		Pool:        "dblab_pool",
+		CloneID:     "testCloneID",
+	}
+
+	mockClient := NewTestClient(func(r *http.Request) *http.Response {
+		assert.Equal(t, r.URL.String(), "https://example.com/clone/testCloneID/snapshot")
+		assert.Equal(t, r.Method, http.MethodPost)
+
+		// Prepare response.
+		responseBody, err := json.Marshal(expectedSnapshot)
+		require.NoError(t, err)
+
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(bytes.NewBuffer(responseBody)),
+			Header:     make(http.Header),
+		}
+	})
+
+	c, err := NewClient(Options{
+		Host:              "https://example.com/",
+		VerificationToken: "token",
+	})
+	require.NoError(t, err)
+
+	c.client = mockClient
+
+	// Send a request.
+	snapshot, err := c.SnapshotClone(context.Background(), "testCloneID")
+	require.NoError(t, err)
+
+	assert.EqualValues(t, expectedSnapshot, snapshot)
+}
+
+func TestClientCreateCheckpoint(t *testing.T) {
+	expectedCheckpoint := &models.Checkpoint{
+		ID:        "checkpoint_20200110000000",
+		CloneID:   "testCloneID",
+		CreatedAt: "2020-01-10 00:00:00 UTC",
+	}
+
+	mockClient := NewTestClient(func(r *http.Request) *http.Response {
+		assert.Equal(t, r.URL.String(), "https://example.com/clone/testCloneID/checkpoint")
+		assert.Equal(t, r.Method, http.MethodPost)
+
+		// Prepare response.
+		responseBody, err := json.Marshal(expectedCheckpoint)
+		require.NoError(t, err)
+
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(bytes.NewBuffer(responseBody)),
+			Header:     make(http.Header),
+		}
+	})
+
+	c, err := NewClient(Options{
+		Host:              "https://example.com/",
+		VerificationToken: "token",
+	})
+	require.NoError(t, err)
+
+	c.client = mockClient
+
+	// Send a request.
+	checkpoint, err := c.CreateCheckpoint(context.Background(), "testCloneID")
+	require.NoError(t, err)
+
+	assert.EqualValues(t, expectedCheckpoint, checkpoint)
+}
+
+func TestClientListCheckpoints(t *testing.T) {
+	expectedCheckpoints := []models.Checkpoint{
+		{
+			ID:           "checkpoint_20200110000000",
+			CloneID:      "testCloneID",
+			CreatedAt:    "2020-01-10 00:00:00 UTC",
+			PhysicalSize: 1024,
+		},
+	}
+
+	mockClient := NewTestClient(func(r *http.Request) *http.Response {
+		assert.Equal(t, r.URL.String(), "https://example.com/clone/testCloneID/checkpoints")
+		assert.Equal(t, r.Method, http.MethodGet)
+
+		// Prepare response.
+		responseBody, err := json.Marshal(expectedCheckpoints)
+		require.NoError(t, err)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBuffer(responseBody)),
+			Header:     make(http.Header),
+		}
+	})
+
+	c, err := NewClient(Options{
+		Host:              "https://example.com/",
+		VerificationToken: "token",
+	})
+	require.NoError(t, err)
+
+	c.client = mockClient
+
+	// Send a request.
+	checkpoints, err := c.ListCheckpoints(context.Background(), "testCloneID")
+	require.NoError(t, err)
+
+	assert.EqualValues(t, expectedCheckpoints, checkpoints)
+}
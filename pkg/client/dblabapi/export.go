@@ -0,0 +1,148 @@
+/*
+2022 © Postgres.ai
+*/
+
+package dblabapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+// ExportClone exports the clone database and waits until the export is completed.
+// Dumping may take a long time, so the export is not limited by the request timeout of the client.
+func (c *Client) ExportClone(ctx context.Context, cloneID string, exportRequest types.CloneExportRequest) (*models.CloneExport, error) {
+	operation, err := c.ExportCloneAsync(ctx, cloneID, exportRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if operation, err = c.watchOperation(ctx, operation.ID); err != nil {
+		return nil, errors.Wrap(err, "failed to watch the export operation")
+	}
+
+	if operation.Status == models.OperationFailed {
+		return nil, errors.Errorf("failed to export clone: %s", operation.Error)
+	}
+
+	exports, err := c.ListCloneExports(ctx, cloneID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, export := range exports {
+		if export.ID == operation.ID {
+			return &export, nil
+		}
+	}
+
+	return nil, errors.Errorf("export %s not found", operation.ID)
+}
+
+// ExportCloneAsync asynchronously exports the clone database and returns the operation tracking the export.
+// The ID of the operation identifies the export.
+func (c *Client) ExportCloneAsync(ctx context.Context, cloneID string, exportRequest types.CloneExportRequest) (*models.Operation, error) {
+	u := c.URL(fmt.Sprintf("/clone/%s/export", cloneID))
+
+	var operation models.Operation
+
+	if err := c.request(ctx, u, exportRequest, &operation); err != nil {
+		return nil, err
+	}
+
+	return &operation, nil
+}
+
+// ListCloneExports returns exports of the clone starting with the most recent one.
+func (c *Client) ListCloneExports(ctx context.Context, cloneID string) ([]models.CloneExport, error) {
+	u := c.URL(fmt.Sprintf("/clone/%s/exports", cloneID))
+
+	request, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make a request")
+	}
+
+	response, err := c.Do(ctx, request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get response")
+	}
+
+	defer func() { _ = response.Body.Close() }()
+
+	var exports []models.CloneExport
+
+	if err := json.NewDecoder(response.Body).Decode(&exports); err != nil {
+		return nil, errors.Wrap(err, "failed to decode a response body")
+	}
+
+	return exports, nil
+}
+
+// DownloadCloneExport returns the content of the export stored in the pool. The caller must close the stream.
+// Exports of the directory format are downloaded as a tar archive.
+func (c *Client) DownloadCloneExport(ctx context.Context, cloneID, exportID string) (io.ReadCloser, error) {
+	u := c.URL(fmt.Sprintf("/clone/%s/export/%s", cloneID, exportID))
+
+	request, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make a request")
+	}
+
+	response, err := c.Do(ctx, request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get response")
+	}
+
+	return response.Body, nil
+}
+
+// DeleteCloneExport deletes the export of the clone.
+func (c *Client) DeleteCloneExport(ctx context.Context, cloneID, exportID string) error {
+	u := c.URL(fmt.Sprintf("/clone/%s/export/%s", cloneID, exportID))
+
+	request, err := http.NewRequest(http.MethodDelete, u.String(), nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to make a request")
+	}
+
+	response, err := c.Do(ctx, request)
+	if err != nil {
+		return errors.Wrap(err, "failed to get response")
+	}
+
+	defer func() { _ = response.Body.Close() }()
+
+	return nil
+}
+
+// watchOperation polls the operation until it is completed.
+func (c *Client) watchOperation(ctx context.Context, operationID string) (*models.Operation, error) {
+	pollingTicker := time.NewTicker(c.pollingInterval)
+	defer pollingTicker.Stop()
+
+	for {
+		select {
+		case <-pollingTicker.C:
+			operation, err := c.GetOperation(ctx, operationID)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get operation")
+			}
+
+			if operation.Status != models.OperationRunning {
+				return operation, nil
+			}
+
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package dblabapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+func TestClientExportClone(t *testing.T) {
+	expectedExport := models.CloneExport{
+		ID:        "testExportID",
+		CloneID:   "testCloneID",
+		Format:    "directory",
+		DBName:    "test",
+		Tables:    []string{"public.users"},
+		Target:    models.ExportTargetPool,
+		Size:      1024,
+		CreatedAt: time.Date(2021, 1, 10, 0, 1, 0, 0, time.UTC),
+	}
+
+	operation := models.Operation{
+		ID:      "testExportID",
+		Type:    models.OperationExportClone,
+		CloneID: "testCloneID",
+		Status:  models.OperationRunning,
+	}
+
+	mockClient := NewTestClient(func(r *http.Request) *http.Response {
+		var response interface{}
+
+		switch r.URL.String() {
+		case "https://example.com/clone/testCloneID/export":
+			assert.Equal(t, r.Method, http.MethodPost)
+
+			exportRequest := types.CloneExportRequest{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&exportRequest))
+			assert.Equal(t, "directory", exportRequest.Format)
+			assert.Equal(t, []string{"public.users"}, exportRequest.Tables)
+
+			response = operation
+
+		case "https://example.com/operations/testExportID":
+			finishedOperation := operation
+			finishedOperation.Status = models.OperationFinished
+			response = finishedOperation
+
+		case "https://example.com/clone/testCloneID/exports":
+			response = []models.CloneExport{expectedExport}
+
+		default:
+			t.Fatalf("unexpected request: %s", r.URL.String())
+		}
+
+		responseBody, err := json.Marshal(response)
+		require.NoError(t, err)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBuffer(responseBody)),
+			Header:     make(http.Header),
+		}
+	})
+
+	c, err := NewClient(Options{
+		Host:              "https://example.com/",
+		VerificationToken: "token",
+	})
+	require.NoError(t, err)
+
+	c.client = mockClient
+	c.pollingInterval = time.Millisecond
+
+	export, err := c.ExportClone(context.Background(), "testCloneID", types.CloneExportRequest{
+		Format: "directory",
+		Tables: []string{"public.users"},
+	})
+	require.NoError(t, err)
+
+	assert.EqualValues(t, expectedExport, *export)
+}
+
+func TestClientDownloadCloneExport(t *testing.T) {
+	expectedContent := "PGDMP"
+
+	mockClient := NewTestClient(func(r *http.Request) *http.Response {
+		assert.Equal(t, r.URL.String(), "https://example.com/clone/testCloneID/export/testExportID")
+		assert.Equal(t, r.Method, http.MethodGet)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(expectedContent)),
+			Header:     make(http.Header),
+		}
+	})
+
+	c, err := NewClient(Options{
+		Host:              "https://example.com/",
+		VerificationToken: "token",
+	})
+	require.NoError(t, err)
+
+	c.client = mockClient
+
+	content, err := c.DownloadCloneExport(context.Background(), "testCloneID", "testExportID")
+	require.NoError(t, err)
+
+	defer func() { _ = content.Close() }()
+
+	body, err := io.ReadAll(content)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedContent, string(body))
+}
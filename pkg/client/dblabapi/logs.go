@@ -0,0 +1,56 @@
+/*
+2022 © Postgres.ai
+*/
+
+package dblabapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
+)
+
+// CloneLogs returns a stream of clone log lines. The caller must close the stream.
+// If Follow is set, the stream is open until the context is canceled.
+func (c *Client) CloneLogs(ctx context.Context, cloneID string, params types.CloneLogsRequest) (io.ReadCloser, error) {
+	u := c.URL(fmt.Sprintf("/clone/%s/logs", cloneID))
+
+	values := url.Values{}
+
+	if params.Source != "" {
+		values.Set("source", params.Source)
+	}
+
+	if params.Since != "" {
+		values.Set("since", params.Since)
+	}
+
+	if params.Tail > 0 {
+		values.Set("tail", strconv.Itoa(params.Tail))
+	}
+
+	if params.Follow {
+		values.Set("follow", "true")
+	}
+
+	u.RawQuery = values.Encode()
+
+	request, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make a request")
+	}
+
+	response, err := c.Do(ctx, request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get response")
+	}
+
+	return response.Body, nil
+}
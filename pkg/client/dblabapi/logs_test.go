@@ -0,0 +1,53 @@
+package dblabapi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
+)
+
+func TestClientCloneLogs(t *testing.T) {
+	expectedLogs := "LOG:  database system is ready to accept connections\nLOG:  statement: select 1;\n"
+
+	mockClient := NewTestClient(func(r *http.Request) *http.Response {
+		assert.Equal(t, r.URL.String(), "https://example.com/clone/testCloneID/logs?follow=true&since=15m&source=csv&tail=50")
+		assert.Equal(t, r.Method, http.MethodGet)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(expectedLogs)),
+			Header:     make(http.Header),
+		}
+	})
+
+	c, err := NewClient(Options{
+		Host:              "https://example.com/",
+		VerificationToken: "token",
+	})
+	require.NoError(t, err)
+
+	c.client = mockClient
+
+	// Send a request.
+	logs, err := c.CloneLogs(context.Background(), "testCloneID", types.CloneLogsRequest{
+		Source: "csv",
+		Since:  "15m",
+		Tail:   50,
+		Follow: true,
+	})
+	require.NoError(t, err)
+
+	defer func() { _ = logs.Close() }()
+
+	body, err := io.ReadAll(logs)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedLogs, string(body))
+}
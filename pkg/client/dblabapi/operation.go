@@ -0,0 +1,71 @@
+/*
+2022 © Postgres.ai
+*/
+
+package dblabapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+// GetOperation returns a clone operation by ID.
+func (c *Client) GetOperation(ctx context.Context, operationID string) (*models.Operation, error) {
+	u := c.URL(fmt.Sprintf("/operations/%s", operationID))
+
+	request, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make a request")
+	}
+
+	response, err := c.Do(ctx, request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get response")
+	}
+
+	defer func() { _ = response.Body.Close() }()
+
+	var operation models.Operation
+
+	if err := json.NewDecoder(response.Body).Decode(&operation); err != nil {
+		return nil, errors.Wrap(err, "failed to decode a response body")
+	}
+
+	return &operation, nil
+}
+
+// ListOperations returns the history of clone operations. The history is filtered by clone if cloneID is not empty.
+func (c *Client) ListOperations(ctx context.Context, cloneID string) ([]models.Operation, error) {
+	u := c.URL("/operations")
+
+	if cloneID != "" {
+		u.RawQuery = url.Values{"clone_id": []string{cloneID}}.Encode()
+	}
+
+	request, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make a request")
+	}
+
+	response, err := c.Do(ctx, request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get response")
+	}
+
+	defer func() { _ = response.Body.Close() }()
+
+	var operations []models.Operation
+
+	if err := json.NewDecoder(response.Body).Decode(&operations); err != nil {
+		return nil, errors.Wrap(err, "failed to decode a response body")
+	}
+
+	return operations, nil
+}
@@ -0,0 +1,104 @@
+package dblabapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
+)
+
+func TestClientGetOperation(t *testing.T) {
+	finishedAt := time.Date(2021, 1, 10, 0, 1, 0, 0, time.UTC)
+
+	expectedOperation := &models.Operation{
+		ID:         "testOperationID",
+		Type:       models.OperationCreateClone,
+		CloneID:    "testCloneID",
+		Status:     models.OperationFailed,
+		Step:       "starting clone session",
+		Error:      "no free ports",
+		StartedAt:  time.Date(2021, 1, 10, 0, 0, 0, 0, time.UTC),
+		UpdatedAt:  finishedAt,
+		FinishedAt: &finishedAt,
+	}
+
+	mockClient := NewTestClient(func(r *http.Request) *http.Response {
+		assert.Equal(t, r.URL.String(), "https://example.com/operations/testOperationID")
+		assert.Equal(t, r.Method, http.MethodGet)
+
+		// Prepare response.
+		responseBody, err := json.Marshal(expectedOperation)
+		require.NoError(t, err)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBuffer(responseBody)),
+			Header:     make(http.Header),
+		}
+	})
+
+	c, err := NewClient(Options{
+		Host:              "https://example.com/",
+		VerificationToken: "token",
+	})
+	require.NoError(t, err)
+
+	c.client = mockClient
+
+	// Send a request.
+	operation, err := c.GetOperation(context.Background(), "testOperationID")
+	require.NoError(t, err)
+
+	assert.EqualValues(t, expectedOperation, operation)
+}
+
+func TestClientListOperations(t *testing.T) {
+	expectedOperations := []models.Operation{
+		{
+			ID:        "testOperationID",
+			Type:      models.OperationResetClone,
+			CloneID:   "testCloneID",
+			Status:    models.OperationRunning,
+			Step:      "resetting clone session",
+			StartedAt: time.Date(2021, 1, 10, 0, 0, 0, 0, time.UTC),
+			UpdatedAt: time.Date(2021, 1, 10, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	mockClient := NewTestClient(func(r *http.Request) *http.Response {
+		assert.Equal(t, r.URL.String(), "https://example.com/operations?clone_id=testCloneID")
+		assert.Equal(t, r.Method, http.MethodGet)
+
+		// Prepare response.
+		responseBody, err := json.Marshal(expectedOperations)
+		require.NoError(t, err)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBuffer(responseBody)),
+			Header:     make(http.Header),
+		}
+	})
+
+	c, err := NewClient(Options{
+		Host:              "https://example.com/",
+		VerificationToken: "token",
+	})
+	require.NoError(t, err)
+
+	c.client = mockClient
+
+	// Send a request.
+	operations, err := c.ListOperations(context.Background(), "testCloneID")
+	require.NoError(t, err)
+
+	assert.EqualValues(t, expectedOperations, operations)
+}
@@ -5,13 +5,17 @@
 package dblabapi
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/pkg/errors"
 
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
 )
 
@@ -49,3 +53,82 @@ func (c *Client) ListSnapshotsRaw(ctx context.Context) (io.ReadCloser, error) {
 
 	return response.Body, nil
 }
+
+// CreateSnapshot starts an on-demand snapshot job.
+func (c *Client) CreateSnapshot(ctx context.Context) (*models.SnapshotJob, error) {
+	u := c.URL("/snapshot")
+
+	request, err := http.NewRequest(http.MethodPost, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make a request")
+	}
+
+	response, err := c.Do(ctx, request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get response")
+	}
+
+	defer func() { _ = response.Body.Close() }()
+
+	var snapshotJob models.SnapshotJob
+
+	if err := json.NewDecoder(response.Body).Decode(&snapshotJob); err != nil {
+		return nil, errors.Wrap(err, "failed to get response")
+	}
+
+	return &snapshotJob, nil
+}
+
+// DestroySnapshot destroys the snapshot. If force is set, clones created from the snapshot are destroyed as well.
+func (c *Client) DestroySnapshot(ctx context.Context, snapshotID string, force bool) error {
+	u := c.URL("/snapshot/" + snapshotID)
+
+	if force {
+		u.RawQuery = url.Values{"force": []string{strconv.FormatBool(force)}}.Encode()
+	}
+
+	request, err := http.NewRequest(http.MethodDelete, u.String(), nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to make a request")
+	}
+
+	response, err := c.Do(ctx, request)
+	if err != nil {
+		return errors.Wrap(err, "failed to get response")
+	}
+
+	defer func() { _ = response.Body.Close() }()
+
+	return nil
+}
+
+// UpdateSnapshot updates an existing snapshot.
+func (c *Client) UpdateSnapshot(ctx context.Context, snapshotID string,
+	updateRequest types.SnapshotUpdateRequest) (*models.Snapshot, error) {
+	u := c.URL("/snapshot/" + snapshotID)
+
+	body := bytes.NewBuffer(nil)
+	if err := json.NewEncoder(body).Encode(updateRequest); err != nil {
+		return nil, errors.Wrap(err, "failed to encode SnapshotUpdateRequest")
+	}
+
+	request, err := http.NewRequest(http.MethodPatch, u.String(), body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make a request")
+	}
+
+	response, err := c.Do(ctx, request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get response")
+	}
+
+	defer func() { _ = response.Body.Close() }()
+
+	var snapshot models.Snapshot
+
+	if err := json.NewDecoder(response.Body).Decode(&snapshot); err != nil {
+		return nil, errors.Wrap(err, "failed to decode a response body")
+	}
+
+	return &snapshot, nil
+}
@@ -7,10 +7,14 @@ import (
 	"io"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/AlekSi/pointer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+	"gitlab.com/postgres-ai/database-lab/v3/pkg/client/dblabapi/types"
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/models"
 )
 
@@ -75,3 +79,111 @@ func TestClientListSnapshotsWithFailedRequest(t *testing.T) {
 	require.EqualError(t, err, "failed to get response: EOF")
 	require.Nil(t, snapshots)
 }
+
+func TestClientCreateSnapshot(t *testing.T) {
+	expectedJob := &models.SnapshotJob{
+		ID:        "testJobID",
+		Status:    models.SnapshotJobRunning,
+		StartedAt: time.Date(2022, 1, 10, 0, 0, 0, 0, time.UTC),
+	}
+
+	mockClient := NewTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, req.URL.String(), "https://example.com/snapshot")
+		assert.Equal(t, req.Method, http.MethodPost)
+
+		// Prepare response.
+		body, err := json.Marshal(expectedJob)
+		require.NoError(t, err)
+
+		return &http.Response{
+			StatusCode: http.StatusAccepted,
+			Body:       io.NopCloser(bytes.NewBuffer(body)),
+			Header:     make(http.Header),
+		}
+	})
+
+	c, err := NewClient(Options{
+		Host:              "https://example.com/",
+		VerificationToken: "testVerify",
+	})
+	require.NoError(t, err)
+
+	c.client = mockClient
+
+	// Send a request.
+	snapshotJob, err := c.CreateSnapshot(context.Background())
+	require.NoError(t, err)
+
+	assert.EqualValues(t, expectedJob, snapshotJob)
+}
+
+func TestClientDestroySnapshot(t *testing.T) {
+	mockClient := NewTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, req.URL.String(), "https://example.com/snapshot/dblab_pool@snapshot_20220110000000?force=true")
+		assert.Equal(t, req.Method, http.MethodDelete)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBuffer(nil)),
+			Header:     make(http.Header),
+		}
+	})
+
+	c, err := NewClient(Options{
+		Host:              "https://example.com/",
+		VerificationToken: "testVerify",
+	})
+	require.NoError(t, err)
+
+	c.client = mockClient
+
+	// Send a request.
+	err = c.DestroySnapshot(context.Background(), "dblab_pool@snapshot_20220110000000", true)
+	require.NoError(t, err)
+}
+
+func TestClientUpdateSnapshot(t *testing.T) {
+	expectedSnapshot := &models.Snapshot{
+		ID:        "dblab_pool@snapshot_20220110000000",
+		Protected: true,
+		SnapshotMetadata: resources.SnapshotMetadata{
+			Description: "pre-release data",
+		},
+	}
+
+	mockClient := NewTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, req.URL.String(), "https://example.com/snapshot/dblab_pool@snapshot_20220110000000")
+		assert.Equal(t, req.Method, http.MethodPatch)
+
+		updateRequest := types.SnapshotUpdateRequest{}
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&updateRequest))
+		require.NotNil(t, updateRequest.Protected)
+		assert.True(t, *updateRequest.Protected)
+		assert.Equal(t, "pre-release data", *updateRequest.Description)
+
+		// Prepare response.
+		body, err := json.Marshal(expectedSnapshot)
+		require.NoError(t, err)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBuffer(body)),
+			Header:     make(http.Header),
+		}
+	})
+
+	c, err := NewClient(Options{
+		Host:              "https://example.com/",
+		VerificationToken: "testVerify",
+	})
+	require.NoError(t, err)
+
+	c.client = mockClient
+
+	// Send a request.
+	snapshot, err := c.UpdateSnapshot(context.Background(), "dblab_pool@snapshot_20220110000000",
+		types.SnapshotUpdateRequest{Protected: pointer.ToBool(true), Description: pointer.ToString("pre-release data")})
+	require.NoError(t, err)
+
+	assert.EqualValues(t, expectedSnapshot, snapshot)
+}
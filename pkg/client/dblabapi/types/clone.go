@@ -5,18 +5,77 @@
 // Package types provides request structures for Database Lab HTTP API.
 package types
 
+import (
+	"time"
+)
+
 // CloneCreateRequest represents clone params of a create request.
 type CloneCreateRequest struct {
-	ID        string                     `json:"id"`
-	Protected bool                       `json:"protected"`
-	DB        *DatabaseRequest           `json:"db"`
-	Snapshot  *SnapshotCloneFieldRequest `json:"snapshot"`
-	ExtraConf map[string]string          `json:"extra_conf"`
+	ID             string                     `json:"id"`
+	Protected      bool                       `json:"protected"`
+	DB             *DatabaseRequest           `json:"db"`
+	Snapshot       *SnapshotCloneFieldRequest `json:"snapshot"`
+	ConfigProfile  string                     `json:"config_profile"`
+	ExtraConf      map[string]string          `json:"extra_conf"`
+	TTL            uint                       `json:"ttl"`
+	DeleteAt       *time.Time                 `json:"delete_at"`
+	Labels         map[string]string          `json:"labels"`
+	RecoveryTarget *RecoveryTargetRequest     `json:"recovery_target"`
+	Resources      *CloneResourcesRequest     `json:"resources"`
+}
+
+// CloneResourcesRequest represents limits of the clone container resources.
+// Unspecified limits default to the maximum ones configured on the instance.
+type CloneResourcesRequest struct {
+	CPUShares   uint64 `json:"cpu_shares"`
+	CPUQuota    uint64 `json:"cpu_quota"`
+	Memory      string `json:"memory"`
+	BlkioWeight uint16 `json:"blkio_weight"`
+}
+
+// RecoveryTargetRequest represents a point in time the clone data is recovered to by replaying archived WAL.
+// Either Time or LSN must be specified.
+type RecoveryTargetRequest struct {
+	Time *time.Time `json:"time"`
+	LSN  string     `json:"lsn"`
 }
 
 // CloneUpdateRequest represents params of an update request.
+// Only specified fields are updated. If Labels is specified, it replaces all labels of the clone.
+// Owner transfers the clone to another user. Only admins are allowed to transfer clones.
 type CloneUpdateRequest struct {
-	Protected bool `json:"protected"`
+	Protected *bool             `json:"protected,omitempty"`
+	Labels    map[string]string `json:"labels"`
+	Owner     *string           `json:"owner,omitempty"`
+}
+
+// Available sorting fields and orders of the clone list.
+const (
+	SortByCreatedAt = "createdAt"
+	SortByDiffSize  = "diffSize"
+	SortByStatus    = "status"
+
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// CloneListRequest represents params of a clone list request.
+type CloneListRequest struct {
+	Offset     int
+	Limit      int
+	SortBy     string
+	Order      string
+	Status     string
+	SnapshotID string
+	Pool       string
+	Labels     []string
+}
+
+// CloneExtendRequest represents params of a request to extend the clone lease.
+// TTL defines the number of minutes from now, DeleteAt defines an absolute expiration time.
+type CloneExtendRequest struct {
+	TTL      uint       `json:"ttl"`
+	DeleteAt *time.Time `json:"delete_at"`
 }
 
 // DatabaseRequest represents database params of a clone request.
@@ -33,7 +92,30 @@ type SnapshotCloneFieldRequest struct {
 }
 
 // ResetCloneRequest represents snapshot params of a reset request.
+// If CheckpointID is specified, the clone is rolled back to its own checkpoint instead of a snapshot.
 type ResetCloneRequest struct {
-	SnapshotID string `json:"snapshotID"`
-	Latest     bool   `json:"latest"`
+	SnapshotID   string `json:"snapshotID"`
+	Latest       bool   `json:"latest"`
+	CheckpointID string `json:"checkpointID"`
+}
+
+// CloneLogsRequest represents parameters of a clone logs request.
+type CloneLogsRequest struct {
+	// Source defines the log source: "container" (default) or "csv".
+	Source string
+	// Since defines either RFC3339 time or a duration relative to the current time, e.g. 15m.
+	Since  string
+	Tail   int
+	Follow bool
+}
+
+// CloneExportRequest represents params of a clone export request.
+type CloneExportRequest struct {
+	// Format defines the pg_dump output format: "custom" (default) or "directory".
+	Format  string   `json:"format"`
+	DBName  string   `json:"db_name"`
+	Schemas []string `json:"schemas"`
+	Tables  []string `json:"tables"`
+	// Target defines where the export is stored: "pool" (default) or "s3".
+	Target string `json:"target"`
 }
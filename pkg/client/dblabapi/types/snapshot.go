@@ -0,0 +1,13 @@
+/*
+2022 © Postgres.ai
+*/
+
+package types
+
+// SnapshotUpdateRequest represents params of a snapshot update request.
+// Only specified fields are updated. If Labels is specified, it replaces all labels of the snapshot.
+type SnapshotUpdateRequest struct {
+	Protected   *bool             `json:"protected"`
+	Description *string           `json:"description"`
+	Labels      map[string]string `json:"labels"`
+}
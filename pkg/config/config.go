@@ -14,6 +14,7 @@ import (
 	"github.com/rs/xid"
 	"gopkg.in/yaml.v2"
 
+	"gitlab.com/postgres-ai/database-lab/v3/internal/audit"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/cloning"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/embeddedui"
 	"gitlab.com/postgres-ai/database-lab/v3/internal/estimator"
@@ -23,6 +24,7 @@ import (
 	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/pool"
 	retConfig "gitlab.com/postgres-ai/database-lab/v3/internal/retrieval/config"
 	srvCfg "gitlab.com/postgres-ai/database-lab/v3/internal/srv/config"
+	"gitlab.com/postgres-ai/database-lab/v3/internal/webhooks"
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/config/global"
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/log"
 	"gitlab.com/postgres-ai/database-lab/v3/pkg/util"
@@ -45,6 +47,8 @@ type Config struct {
 	Estimator   estimator.Config  `yaml:"estimator"`
 	PoolManager pool.Config       `yaml:"poolManager"`
 	EmbeddedUI  embeddedui.Config `yaml:"embeddedUI"`
+	Webhooks    webhooks.Config   `yaml:"webhooks"`
+	Audit       audit.Config      `yaml:"audit"`
 }
 
 // LoadConfiguration instances a new application configuration.
@@ -0,0 +1,84 @@
+/*
+2022 © Postgres.ai
+*/
+
+package models
+
+import (
+	"time"
+)
+
+// AuditAction defines an action recorded to the audit log.
+type AuditAction string
+
+const (
+	// AuditCloneCreate defines the clone creation.
+	AuditCloneCreate AuditAction = "clone_create"
+	// AuditCloneDestroy defines the clone removal, including the deletion of idle clones.
+	AuditCloneDestroy AuditAction = "clone_destroy"
+	// AuditCloneUpdate defines the change of clone properties.
+	AuditCloneUpdate AuditAction = "clone_update"
+	// AuditCloneReset defines the clone reset.
+	AuditCloneReset AuditAction = "clone_reset"
+	// AuditCloneExtend defines the extension of the clone lifetime.
+	AuditCloneExtend AuditAction = "clone_extend"
+	// AuditCloneTouch defines the renewal of the clone activity.
+	AuditCloneTouch AuditAction = "clone_touch"
+	// AuditCloneRestart defines the restart of the clone container.
+	AuditCloneRestart AuditAction = "clone_restart"
+	// AuditCloneExport defines the export of the clone database.
+	AuditCloneExport AuditAction = "clone_export"
+	// AuditCloneExportDownload defines the download of the clone export.
+	AuditCloneExportDownload AuditAction = "clone_export_download"
+	// AuditCloneExportDelete defines the removal of the clone export.
+	AuditCloneExportDelete AuditAction = "clone_export_delete"
+	// AuditCloneSnapshot defines the creation of a snapshot from the clone.
+	AuditCloneSnapshot AuditAction = "clone_snapshot"
+	// AuditCloneCheckpoint defines the creation of a clone checkpoint.
+	AuditCloneCheckpoint AuditAction = "clone_checkpoint"
+	// AuditSnapshotCreate defines the snapshot creation.
+	AuditSnapshotCreate AuditAction = "snapshot_create"
+	// AuditSnapshotDestroy defines the snapshot removal.
+	AuditSnapshotDestroy AuditAction = "snapshot_destroy"
+	// AuditSnapshotUpdate defines the change of snapshot properties.
+	AuditSnapshotUpdate AuditAction = "snapshot_update"
+	// AuditObservationStart defines the start of an observation session.
+	AuditObservationStart AuditAction = "observation_start"
+	// AuditObservationStop defines the stop of an observation session.
+	AuditObservationStop AuditAction = "observation_stop"
+	// AuditRefresh defines the data refresh.
+	AuditRefresh AuditAction = "refresh"
+	// AuditConfigReload defines the reload of the engine configuration.
+	AuditConfigReload AuditAction = "config_reload"
+)
+
+// AuditResult defines the result of an audited action.
+type AuditResult string
+
+const (
+	// AuditSuccess defines the successful action. Asynchronous actions are successful when they are accepted.
+	AuditSuccess AuditResult = "success"
+	// AuditFailure defines the failed action.
+	AuditFailure AuditResult = "failure"
+	// AuditDenied defines the action rejected because of missing or insufficient credentials.
+	AuditDenied AuditResult = "denied"
+	// AuditStarted defines the action, which result is recorded by another entry.
+	AuditStarted AuditResult = "started"
+)
+
+// AuditEntry describes a record of the audit log.
+type AuditEntry struct {
+	Time         time.Time   `json:"time"`
+	RequestID    string      `json:"requestId,omitempty"`
+	User         string      `json:"user,omitempty"`
+	SourceIP     string      `json:"sourceIp,omitempty"`
+	ForwardedFor string      `json:"forwardedFor,omitempty"`
+	Action       AuditAction `json:"action"`
+	Method       string      `json:"method,omitempty"`
+	Path         string      `json:"path,omitempty"`
+	Target       string      `json:"target,omitempty"`
+	OperationID  string      `json:"operationId,omitempty"`
+	Result       AuditResult `json:"result"`
+	StatusCode   int         `json:"statusCode,omitempty"`
+	Message      string      `json:"message,omitempty"`
+}
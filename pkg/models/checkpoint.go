@@ -0,0 +1,13 @@
+/*
+2022 © Postgres.ai
+*/
+
+package models
+
+// Checkpoint defines a restore point taken inside a clone.
+type Checkpoint struct {
+	ID           string `json:"id"`
+	CloneID      string `json:"cloneId"`
+	CreatedAt    string `json:"createdAt"`
+	PhysicalSize uint64 `json:"physicalSize"`
+}
@@ -6,22 +6,49 @@ package models
 
 // Clone defines a clone model.
 type Clone struct {
-	ID        string        `json:"id"`
-	Snapshot  *Snapshot     `json:"snapshot"`
-	Protected bool          `json:"protected"`
-	DeleteAt  string        `json:"deleteAt"`
-	CreatedAt string        `json:"createdAt"`
-	Status    Status        `json:"status"`
-	DB        Database      `json:"db"`
-	Metadata  CloneMetadata `json:"metadata"`
+	ID             string            `json:"id"`
+	Snapshot       *Snapshot         `json:"snapshot"`
+	Protected      bool              `json:"protected"`
+	DeleteAt       string            `json:"deleteAt"`
+	CreatedAt      string            `json:"createdAt"`
+	Status         Status            `json:"status"`
+	DB             Database          `json:"db"`
+	Metadata       CloneMetadata     `json:"metadata"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	RecoveryTarget *RecoveryTarget   `json:"recoveryTarget,omitempty"`
+	ConfigProfile  string            `json:"configProfile,omitempty"`
+	Owner          string            `json:"owner,omitempty"`
+}
+
+// RecoveryTarget defines the point in time the clone data has been recovered to.
+type RecoveryTarget struct {
+	Time string `json:"time,omitempty"`
+	LSN  string `json:"lsn,omitempty"`
+}
+
+// ClonesPage represents a page of the clone list.
+type ClonesPage struct {
+	Clones []*Clone `json:"clones"`
+	Total  int      `json:"total"`
+	Offset int      `json:"offset"`
+	Limit  int      `json:"limit"`
 }
 
 // CloneMetadata contains fields describing a clone model.
 type CloneMetadata struct {
-	CloneDiffSize  uint64  `json:"cloneDiffSize"`
-	LogicalSize    uint64  `json:"logicalSize"`
-	CloningTime    float64 `json:"cloningTime"`
-	MaxIdleMinutes uint    `json:"maxIdleMinutes"`
+	CloneDiffSize  uint64          `json:"cloneDiffSize"`
+	LogicalSize    uint64          `json:"logicalSize"`
+	CloningTime    float64         `json:"cloningTime"`
+	MaxIdleMinutes uint            `json:"maxIdleMinutes"`
+	Resources      *CloneResources `json:"resources,omitempty"`
+}
+
+// CloneResources describes limits of the clone container resources.
+type CloneResources struct {
+	CPUShares   uint64 `json:"cpuShares,omitempty"`
+	CPUQuota    uint64 `json:"cpuQuota,omitempty"`
+	Memory      uint64 `json:"memory,omitempty"`
+	BlkioWeight uint16 `json:"blkioWeight,omitempty"`
 }
 
 // CloneView represents a view of clone model.
@@ -37,3 +64,9 @@ type CloneMetadataView struct {
 	CloneDiffSize Size `json:"cloneDiffSize"`
 	LogicalSize   Size `json:"logicalSize"`
 }
+
+// ClonesPageView represents a view of the clone list page.
+type ClonesPageView struct {
+	*ClonesPage
+	Clones []*CloneView `json:"clones"`
+}
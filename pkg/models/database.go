@@ -6,10 +6,23 @@ package models
 
 // Database defines clone database parameters.
 type Database struct {
-	ConnStr  string `json:"connStr"`
-	Host     string `json:"host"`
-	Port     string `json:"port"`
-	Username string `json:"username"`
-	Password string `json:"password"`
-	DBName   string `json:"dbName"`
+	ConnStr   string           `json:"connStr"`
+	Host      string           `json:"host"`
+	Port      string           `json:"port"`
+	Username  string           `json:"username"`
+	Password  string           `json:"password"`
+	DBName    string           `json:"dbName"`
+	URI       string           `json:"uri,omitempty"`
+	JDBCURL   string           `json:"jdbcUrl,omitempty"`
+	Endpoints []AccessEndpoint `json:"endpoints,omitempty"`
+}
+
+// AccessEndpoint defines parameters of the clone database connection through a named access host.
+type AccessEndpoint struct {
+	Name    string `json:"name"`
+	Host    string `json:"host"`
+	Port    string `json:"port"`
+	ConnStr string `json:"connStr"`
+	URI     string `json:"uri"`
+	JDBCURL string `json:"jdbcUrl"`
 }
@@ -10,10 +10,12 @@ type ErrorCode string
 
 // ErrCode constants define a response error codes.
 const (
-	ErrCodeInternal     ErrorCode = "INTERNAL_ERROR"
-	ErrCodeBadRequest   ErrorCode = "BAD_REQUEST"
-	ErrCodeUnauthorized ErrorCode = "UNAUTHORIZED"
-	ErrCodeNotFound     ErrorCode = "NOT_FOUND"
+	ErrCodeInternal      ErrorCode = "INTERNAL_ERROR"
+	ErrCodeBadRequest    ErrorCode = "BAD_REQUEST"
+	ErrCodeUnauthorized  ErrorCode = "UNAUTHORIZED"
+	ErrCodeForbidden     ErrorCode = "FORBIDDEN"
+	ErrCodeNotFound      ErrorCode = "NOT_FOUND"
+	ErrCodeQuotaExceeded ErrorCode = "QUOTA_EXCEEDED"
 )
 
 // Error struct represents a response error.
@@ -0,0 +1,45 @@
+/*
+2022 © Postgres.ai
+*/
+
+package models
+
+import (
+	"time"
+)
+
+// EventType defines type of an engine event.
+type EventType string
+
+const (
+	// EventCloneStatus defines the transition of a clone to another status.
+	EventCloneStatus EventType = "clone_status"
+	// EventCloneCreated defines the clone that is ready to use after creation.
+	EventCloneCreated EventType = "clone_created"
+	// EventCloneReset defines the successful clone reset.
+	EventCloneReset EventType = "clone_reset"
+	// EventCloneDestroyed defines the clone removal.
+	EventCloneDestroyed EventType = "clone_destroyed"
+	// EventCloneIdleWarning defines the warning about the upcoming deletion of an idle clone.
+	EventCloneIdleWarning EventType = "clone_idle_warning"
+	// EventCloneIdleDeletion defines the deletion of an idle clone.
+	EventCloneIdleDeletion EventType = "clone_idle_deletion"
+	// EventSnapshotCreated defines the appearance of a new snapshot.
+	EventSnapshotCreated EventType = "snapshot_created"
+	// EventSnapshotDestroyed defines the snapshot removal.
+	EventSnapshotDestroyed EventType = "snapshot_destroyed"
+	// EventRetrievalStatus defines the change of the data retrieval status.
+	EventRetrievalStatus EventType = "retrieval_status"
+	// EventAlert defines a retrieval alert.
+	EventAlert EventType = "alert"
+)
+
+// Event describes a change of the engine state.
+type Event struct {
+	Type       EventType `json:"type"`
+	CreatedAt  time.Time `json:"createdAt"`
+	CloneID    string    `json:"cloneId,omitempty"`
+	SnapshotID string    `json:"snapshotId,omitempty"`
+	Status     string    `json:"status,omitempty"`
+	Message    string    `json:"message,omitempty"`
+}
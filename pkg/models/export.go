@@ -0,0 +1,33 @@
+/*
+2022 © Postgres.ai
+*/
+
+package models
+
+import (
+	"time"
+)
+
+// ExportTarget defines the storage of a clone export.
+type ExportTarget string
+
+const (
+	// ExportTargetPool defines exports stored in the pool of the clone.
+	ExportTargetPool ExportTarget = "pool"
+	// ExportTargetS3 defines exports uploaded to an S3-compatible storage.
+	ExportTargetS3 ExportTarget = "s3"
+)
+
+// CloneExport describes a database dump exported from a clone.
+type CloneExport struct {
+	ID        string       `json:"id"`
+	CloneID   string       `json:"cloneId"`
+	Format    string       `json:"format"`
+	DBName    string       `json:"dbName"`
+	Schemas   []string     `json:"schemas,omitempty"`
+	Tables    []string     `json:"tables,omitempty"`
+	Target    ExportTarget `json:"target"`
+	Location  string       `json:"location,omitempty"`
+	Size      uint64       `json:"size"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
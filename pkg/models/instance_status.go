@@ -41,6 +41,7 @@ type Cloning struct {
 	ExpectedCloningTime float64  `json:"expectedCloningTime"`
 	NumClones           uint64   `json:"numClones"`
 	Clones              []*Clone `json:"clones"`
+	ConfigProfiles      []string `json:"configProfiles,omitempty"`
 }
 
 // Engine represents info about Database Lab Engine instance.
@@ -0,0 +1,50 @@
+/*
+2022 © Postgres.ai
+*/
+
+package models
+
+import (
+	"time"
+)
+
+// OperationType defines type of a clone operation.
+type OperationType string
+
+const (
+	// OperationCreateClone defines the clone creation.
+	OperationCreateClone OperationType = "createClone"
+	// OperationResetClone defines the clone reset.
+	OperationResetClone OperationType = "resetClone"
+	// OperationDestroyClone defines the clone destruction.
+	OperationDestroyClone OperationType = "destroyClone"
+	// OperationRestartClone defines the restart of the clone container.
+	OperationRestartClone OperationType = "restartClone"
+	// OperationExportClone defines the export of the clone database.
+	OperationExportClone OperationType = "exportClone"
+)
+
+// OperationStatus defines status of a clone operation.
+type OperationStatus string
+
+const (
+	// OperationRunning defines status when the operation is in progress.
+	OperationRunning OperationStatus = "running"
+	// OperationFinished defines status when the operation is finished.
+	OperationFinished OperationStatus = "finished"
+	// OperationFailed defines status when the operation is failed.
+	OperationFailed OperationStatus = "failed"
+)
+
+// Operation describes a long-running action performed on a clone.
+type Operation struct {
+	ID         string          `json:"id"`
+	Type       OperationType   `json:"type"`
+	CloneID    string          `json:"cloneId"`
+	Status     OperationStatus `json:"status"`
+	Step       string          `json:"step,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	StartedAt  time.Time       `json:"startedAt"`
+	UpdatedAt  time.Time       `json:"updatedAt"`
+	FinishedAt *time.Time      `json:"finishedAt,omitempty"`
+}
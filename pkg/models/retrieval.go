@@ -59,11 +59,34 @@ const (
 
 // Retrieving represents state of retrieval subsystem.
 type Retrieving struct {
-	Mode        RetrievalMode       `json:"mode"`
-	Status      RetrievalStatus     `json:"status"`
-	LastRefresh *time.Time          `json:"lastRefresh"`
-	NextRefresh *time.Time          `json:"nextRefresh"`
-	Alerts      map[AlertType]Alert `json:"alerts"`
+	Mode         RetrievalMode       `json:"mode"`
+	Status       RetrievalStatus     `json:"status"`
+	LastRefresh  *time.Time          `json:"lastRefresh"`
+	NextRefresh  *time.Time          `json:"nextRefresh"`
+	Alerts       map[AlertType]Alert `json:"alerts"`
+	SnapshotJobs []SnapshotJob       `json:"snapshotJobs,omitempty"`
+}
+
+// SnapshotJobStatus defines status of an on-demand snapshot job.
+type SnapshotJobStatus string
+
+const (
+	// SnapshotJobRunning defines status when the snapshot job is in progress.
+	SnapshotJobRunning SnapshotJobStatus = "running"
+	// SnapshotJobFinished defines status when the snapshot job is finished.
+	SnapshotJobFinished SnapshotJobStatus = "finished"
+	// SnapshotJobFailed defines status when the snapshot job is failed.
+	SnapshotJobFailed SnapshotJobStatus = "failed"
+)
+
+// SnapshotJob describes an on-demand snapshot job.
+type SnapshotJob struct {
+	ID         string            `json:"id"`
+	Status     SnapshotJobStatus `json:"status"`
+	SnapshotID string            `json:"snapshotId,omitempty"`
+	Message    string            `json:"message,omitempty"`
+	StartedAt  time.Time         `json:"startedAt"`
+	FinishedAt *time.Time        `json:"finishedAt,omitempty"`
 }
 
 // Alert describes retrieval subsystem alert.
@@ -4,6 +4,10 @@
 
 package models
 
+import (
+	"gitlab.com/postgres-ai/database-lab/v3/internal/provision/resources"
+)
+
 // Snapshot defines a snapshot entity.
 type Snapshot struct {
 	ID           string `json:"id"`
@@ -13,6 +17,9 @@ type Snapshot struct {
 	LogicalSize  uint64 `json:"logicalSize"`
 	Pool         string `json:"pool"`
 	NumClones    int    `json:"numClones"`
+	CloneID      string `json:"cloneId,omitempty"`
+	Protected    bool   `json:"protected"`
+	resources.SnapshotMetadata
 }
 
 // SnapshotView represents a view of snapshot.
@@ -15,19 +15,27 @@ type StatusCode string
 
 // Constants declares available status codes and messages.
 const (
-	StatusOK        StatusCode = "OK"
-	StatusCreating  StatusCode = "CREATING"
-	StatusResetting StatusCode = "RESETTING"
-	StatusDeleting  StatusCode = "DELETING"
-	StatusExporting StatusCode = "EXPORTING"
-	StatusFatal     StatusCode = "FATAL"
-	StatusWarning   StatusCode = "WARNING"
+	StatusOK           StatusCode = "OK"
+	StatusCreating     StatusCode = "CREATING"
+	StatusResetting    StatusCode = "RESETTING"
+	StatusDeleting     StatusCode = "DELETING"
+	StatusExporting    StatusCode = "EXPORTING"
+	StatusSnapshotting StatusCode = "SNAPSHOTTING"
+	StatusRestarting   StatusCode = "RESTARTING"
+	StatusFatal        StatusCode = "FATAL"
+	StatusWarning      StatusCode = "WARNING"
 
-	CloneMessageOK        = "Clone is ready to accept Postgres connections."
-	CloneMessageCreating  = "Clone is being created."
-	CloneMessageResetting = "Clone is being reset."
-	CloneMessageDeleting  = "Clone is being deleted."
-	CloneMessageFatal     = "Cloning failure."
+	CloneMessageOK            = "Clone is ready to accept Postgres connections."
+	CloneMessageCreating      = "Clone is being created."
+	CloneMessageResetting     = "Clone is being reset."
+	CloneMessageDeleting      = "Clone is being deleted."
+	CloneMessageFatal         = "Cloning failure."
+	CloneMessageRestarting    = "Clone container is being restarted."
+	CloneMessageExporting     = "Clone database is being exported."
+	CloneMessageSnapshotting  = "Clone snapshot is being taken."
+	CloneMessageCheckpointing = "Clone checkpoint is being taken."
+
+	CloneMessageIdleWarning = "Clone has no activity and will be deleted in %d minutes unless it is used or touched."
 
 	InstanceMessageOK      = "Instance is ready"
 	InstanceMessageWarning = "Subsystems that need attention"
@@ -0,0 +1,50 @@
+/*
+2022 © Postgres.ai
+*/
+
+// Package util provides utility functions. Label related functions.
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+const labelSeparator = "="
+
+// ParseLabelSelector parses label requirements in the "key=value" format.
+// Every element may contain several comma-separated requirements.
+func ParseLabelSelector(selectors []string) (map[string]string, error) {
+	selector := make(map[string]string)
+
+	for _, item := range selectors {
+		for _, requirement := range strings.Split(item, ",") {
+			requirement = strings.TrimSpace(requirement)
+			if requirement == "" {
+				continue
+			}
+
+			const maxSplitParts = 2
+
+			parts := strings.SplitN(requirement, labelSeparator, maxSplitParts)
+			if len(parts) != maxSplitParts || strings.TrimSpace(parts[0]) == "" {
+				return nil, fmt.Errorf("invalid label selector %q: expected format is key=value", requirement)
+			}
+
+			selector[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+
+	return selector, nil
+}
+
+// MatchLabels checks if labels contain all requirements of the selector.
+func MatchLabels(labels, selector map[string]string) bool {
+	for key, value := range selector {
+		if labelValue, ok := labels[key]; !ok || labelValue != value {
+			return false
+		}
+	}
+
+	return true
+}
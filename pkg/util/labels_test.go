@@ -0,0 +1,35 @@
+/*
+2022 © Postgres.ai
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	selector, err := ParseLabelSelector([]string{"team=analytics", "pr=1234, env = ci", ""})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "analytics", "pr": "1234", "env": "ci"}, selector)
+
+	_, err = ParseLabelSelector([]string{"team"})
+	assert.Error(t, err)
+
+	_, err = ParseLabelSelector([]string{"=analytics"})
+	assert.Error(t, err)
+}
+
+func TestMatchLabels(t *testing.T) {
+	labels := map[string]string{"team": "analytics", "pr": "1234"}
+
+	assert.True(t, MatchLabels(labels, nil))
+	assert.True(t, MatchLabels(labels, map[string]string{"team": "analytics"}))
+	assert.True(t, MatchLabels(labels, map[string]string{"team": "analytics", "pr": "1234"}))
+	assert.False(t, MatchLabels(labels, map[string]string{"team": "backend"}))
+	assert.False(t, MatchLabels(labels, map[string]string{"owner": "john"}))
+	assert.False(t, MatchLabels(nil, map[string]string{"team": "analytics"}))
+}